@@ -4,41 +4,122 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"net/http"
+	"net/url"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"strings"
 	"syscall"
+	"time"
 
 	"github.com/bigwhite/issue2md/internal/cli"
 	"github.com/bigwhite/issue2md/internal/config"
 	"github.com/bigwhite/issue2md/internal/converter"
+	"github.com/bigwhite/issue2md/internal/gitea"
 	"github.com/bigwhite/issue2md/internal/github"
+	"github.com/bigwhite/issue2md/internal/gitlab"
+	"github.com/bigwhite/issue2md/internal/model"
 	"github.com/bigwhite/issue2md/internal/parser"
+	"github.com/bigwhite/issue2md/internal/source"
+	"github.com/bigwhite/issue2md/internal/transport"
 )
 
 const (
 	name    = "issue2md"
 	version = "1.0.0"
-	usage   = `issue2md - Convert GitHub issues to Markdown
+	usage   = `issue2md - Convert GitHub issues, pull requests and discussions, GitLab issues,
+merge requests and epics, and Gitea/Forgejo issues and pull requests to Markdown
 
 Usage:
-  issue2md [owner/repo] [issue-number] [flags]
+  issue2md [flags] <url> [output_file]
+  issue2md [flags] <repository_url> <output_dir>
+  issue2md search [flags] <query> <output_dir>
+  issue2md import [flags] <jira.xml|migration.tar.gz> <output_dir>
+  issue2md render [flags] <json_file|-> [output_file]
 
 Examples:
-  issue2md facebook/react 12345
-  issue2md facebook/react 12345 --output=issue.md
-  issue2md facebook/react 12345 --format=html --no-comments
+  issue2md https://github.com/facebook/react/issues/12345
+  issue2md https://github.com/facebook/react/pull/12346 pr.md
+  issue2md -format=json https://github.com/org/repo/discussions/7 discussion.json
+  issue2md -offline https://github.com/facebook/react/issues/12345
+  issue2md -state=all -labels=bug -since=2024-01-01 https://github.com/org/repo backlog/
+  issue2md search 'repo:org/x is:closed label:incident created:>2024-01-01' incidents/
+  issue2md search -host git.example.com 'org:platform is:open' platform/
+  issue2md import jira-export.xml docs/jira/
+  issue2md import -f html migration-archive.tar.gz docs/archive/
+  gh issue view 42 --json number,title,body,state,author,labels,comments,createdAt,updatedAt,url | issue2md render - issue.md
+  issue2md render -f html snapshots/issue-42.json issue.html
+  issue2md -recursive https://github.com/org/repo/issues/100 roadmap/
+  issue2md -replay ./cassettes/bug-42 https://github.com/org/repo/issues/42
+  ISSUE2MD_HOSTS=git.example.com issue2md https://git.example.com/team/app/issues/42
+  issue2md https://gitlab.com/group/project/-/merge_requests/7 mr.md
+  ISSUE2MD_GITLAB_HOSTS=gitlab.example.com issue2md https://gitlab.example.com/groups/team/-/epics/3
+  ISSUE2MD_GITEA_HOSTS=forge.example.com issue2md https://forge.example.com/team/app/pulls/7
 
 Flags:
-  -h, --help              Show help information
-  -v, --version           Show version information
-  -o, --output string     Output file path (default: "output.md")
-  -f, --format string     Output format: markdown, html, json (default: "markdown")
-  -t, --token string      GitHub token (or set GITHUB_TOKEN env var)
-  --no-comments          Exclude comments from output
-  --no-metadata          Exclude metadata from output
-  --no-timestamps        Exclude timestamps from output
-  --overwrite            Overwrite existing output file
-  --debug                Enable debug logging`
+  -h, -help               Show help information
+  -v, -version            Show version information
+  -f, -format string      Output format: markdown, html, json (default: "markdown")
+  -enable-reactions       Include reaction counts
+  -enable-user-links      Render usernames as GitHub profile links
+  -overwrite              Overwrite existing output file
+  -refresh                Ignore cached responses and fetch everything again
+  -offline                Use cached responses only, never touch the network
+  -no-cache               Disable the on-disk response cache
+  -cache-dir string       Cache directory (default: user cache dir/issue2md)
+  -record dir             Record every GitHub API exchange into a cassette directory
+  -replay dir             Replay a recorded cassette, never touch the network
+  -token string           GitHub token for the URL's host, overrides other sources
+  -token-file string      File containing the token (GitLab and Gitea: URL's host only)
+  -verbose                Report progress and which credential source was used
+  -edit-history           Include the edit history of the body and comments as collapsible diffs
+  -download-assets        Download attachments and images into assets/ next to the output and link them locally
+  -minimized string       Comments hidden by moderators: include, collapse or drop (default: "collapse")
+  -recursive              Export an issue with its whole sub-issue tree into output_file as a directory
+
+Repository export flags:
+  -state string           open, closed or all (default: "open")
+  -labels string          Comma-separated labels that issues must all have
+  -milestone string       Milestone number or title, * for any, none for no milestone
+  -assignee string        Assignee login, * for any, none for unassigned
+  -creator string         Issue author login
+  -since string           Only issues updated since this time (RFC 3339 or YYYY-MM-DD)
+  -sort string            created, updated or comments (default: "created")
+  -direction string       asc or desc (default: "desc")
+  -include-prs            Also export pull requests
+
+Search flags:
+  -host string            GitHub Enterprise Server host to search (default: github.com)
+
+Environment:
+  GITHUB_TOKEN, GH_TOKEN  GitHub token for github.com
+  GH_ENTERPRISE_TOKEN     Token for GitHub Enterprise Server hosts
+  ISSUE2MD_APP_ID         GitHub App ID, authenticate as the app instead of GITHUB_TOKEN
+  ISSUE2MD_APP_PRIVATE_KEY
+                          Path to the GitHub App private key (PEM)
+  ISSUE2MD_APP_INSTALLATION_ID
+                          GitHub App installation (default: the one on the URL's owner)
+  ISSUE2MD_APP_HOST       Enterprise host the GitHub App belongs to (default: github.com)
+  ISSUE2MD_HOSTS          Comma-separated Enterprise hosts, each "host" or "host=api_url"
+  GITLAB_TOKEN            Token for gitlab.com and self-managed GitLab hosts
+  ISSUE2MD_GITLAB_HOSTS   Comma-separated self-managed GitLab hosts, each "host" or "host=api_url"
+  FORGEJO_TOKEN, GITEA_TOKEN
+                          Token for Gitea and Forgejo hosts
+  ISSUE2MD_GITEA_HOSTS    Comma-separated Gitea/Forgejo hosts, each "host" or "host=api_url"
+  ISSUE2MD_TOKEN_FILE     File containing the GitHub token
+  ISSUE2MD_CACHE_DIR      Cache directory
+
+Credentials are looked up in this order: -token, GITHUB_TOKEN/GH_TOKEN,
+token file, gh CLI hosts.yml, ~/.netrc, git credential fill. GitLab and
+Gitea hosts only use their own token variables, ~/.netrc and git credential
+fill, and fall back to anonymous access for public projects.
+
+import reads a Jira XML export or a GitHub migration archive (tar or tar.gz)
+without any network access and writes one document per issue or pull
+request, grouped by repository or Jira project key. render reads the JSON of
+one issue or pull request from gh issue view --json, gh pr view --json or
+issue2md -format=json and renders it without any network access.`
 )
 
 func main() {
@@ -69,24 +150,251 @@ func main() {
 
 // runCLI 执行CLI逻辑
 func runCLI(ctx context.Context, app *cli.CLI, cfg *config.Config) error {
-	// TODO: 实现命令行参数解析和业务逻辑
-	fmt.Printf("%s v%s\n", name, version)
-	fmt.Println("Phase 1 Foundation completed successfully!")
-	fmt.Println("Core data structures have been initialized.")
+	args, err := app.ParseArgs()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, usage)
+		return err
+	}
+	if args.ShowHelp {
+		fmt.Println(usage)
+		return nil
+	}
+	if args.ShowVersion {
+		fmt.Printf("%s v%s\n", name, version)
+		return nil
+	}
+
+	applyArgs(cfg, args)
 
 	// 验证配置
 	if err := cfg.Validate(); err != nil {
 		return fmt.Errorf("configuration validation failed: %w", err)
 	}
 
+	markdownParser, conv := initializeServices(cfg)
+
+	// 客户端按URL的主机延迟创建，只需要目标主机的凭据；
+	// 被折叠评论的三种处理方式都依赖折叠状态: drop据此去掉评论，collapse和include据此标注
+	registry := newSourceRegistry(cfg)
+	exporter := &cli.Exporter{
+		ClientForHost:  registry.ClientForHost,
+		URLParser:      registry,
+		Parser:         markdownParser,
+		Converter:      conv,
+		Timeline:       true,
+		SubIssues:      true,
+		Resolution:     true,
+		Projects:       true,
+		EditHistory:    args.EditHistory,
+		Minimized:      true,
+		DownloadAssets: args.DownloadAssets,
+		OnAssetError: func(rawURL string, err error) {
+			log.Printf("Warning: keeping remote link %s: %v", rawURL, err)
+		},
+		OnProjectsError: func(url string, err error) {
+			log.Printf("Warning: leaving out projects of %s, the token needs the read:project scope: %v", url, err)
+		},
+	}
+
+	if args.Query != "" {
+		return exportSearch(ctx, app, exporter, cfg, args)
+	}
+	if args.ImportFile != "" {
+		return exportImport(ctx, app, exporter, cfg, args)
+	}
+	if args.RenderFile != "" {
+		return renderSnapshot(app, exporter, cfg, args)
+	}
+	if args.Recursive {
+		return exportTree(ctx, app, exporter, cfg, args)
+	}
+	if _, err := exporter.URLParser.ParseRepository(args.URL); err == nil {
+		return exportRepository(ctx, app, exporter, cfg, args)
+	}
+
+	doc, err := exporter.ExportDocument(ctx, args.URL)
+	if err != nil {
+		return err
+	}
+
+	if err := app.WriteOutput(doc.Data, args.OutputFile, cfg.Output.Overwrite); err != nil {
+		return err
+	}
+	return app.WriteAssets(doc.Assets, filepath.Dir(args.OutputFile))
+}
+
+// exportRepository 将仓库中符合过滤条件的Issue逐个导出到输出目录
+func exportRepository(ctx context.Context, app *cli.CLI, exporter *cli.Exporter, cfg *config.Config, args *cli.Args) error {
+	if args.OutputFile == "" {
+		return fmt.Errorf("exporting a repository requires an output directory")
+	}
+
+	opts := &model.IssueListOptions{
+		State:               args.State,
+		Labels:              args.Labels,
+		Milestone:           args.Milestone,
+		Assignee:            args.Assignee,
+		Creator:             args.Creator,
+		Since:               args.Since,
+		Sort:                args.Sort,
+		Direction:           args.Direction,
+		ExcludePullRequests: !args.IncludePRs,
+	}
+	emit := writeDocuments(app, cfg, args.OutputFile, func(doc *cli.ExportedDocument) string {
+		return cli.OutputFileName(doc.Number, cfg.Output.Format)
+	})
+	n, err := exporter.ExportIssues(ctx, args.URL, opts, emit)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Exported %d documents to %s", n, args.OutputFile)
 	return nil
 }
 
-// initializeServices 初始化服务
-func initializeServices(cfg *config.Config) (*github.GitHubClient, *parser.MarkdownParser, converter.Converter, error) {
-	// 初始化GitHub客户端
-	githubClient := github.NewClient(cfg.GitHubToken)
+// exportSearch 将搜索到的Issue和Pull Request按仓库分目录导出到输出目录
+func exportSearch(ctx context.Context, app *cli.CLI, exporter *cli.Exporter, cfg *config.Config, args *cli.Args) error {
+	emit := writeDocuments(app, cfg, args.OutputFile, func(doc *cli.ExportedDocument) string {
+		return cli.SearchOutputPath(doc, cfg.Output.Format)
+	})
+	n, err := exporter.ExportSearch(ctx, args.Host, args.Query, emit)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Exported %d documents to %s", n, args.OutputFile)
+	return nil
+}
+
+// exportImport 将离线导出文件中的Issue和Pull Request按仓库或Jira项目分目录导出到输出目录
+func exportImport(ctx context.Context, app *cli.CLI, exporter *cli.Exporter, cfg *config.Config, args *cli.Args) error {
+	emit := writeDocuments(app, cfg, args.OutputFile, func(doc *cli.ExportedDocument) string {
+		return cli.SearchOutputPath(doc, cfg.Output.Format)
+	})
+	n, err := exporter.ExportImport(ctx, args.ImportFile, emit)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Exported %d documents to %s", n, args.OutputFile)
+	return nil
+}
+
+// renderSnapshot 渲染JSON文件或标准输入中的Issue或Pull Request
+func renderSnapshot(app *cli.CLI, exporter *cli.Exporter, cfg *config.Config, args *cli.Args) error {
+	input := os.Stdin
+	if args.RenderFile != "-" {
+		f, err := os.Open(args.RenderFile)
+		if err != nil {
+			return fmt.Errorf("failed to open JSON input: %w", err)
+		}
+		defer f.Close()
+		input = f
+	}
+
+	data, err := exporter.ExportSnapshot(input)
+	if err != nil {
+		return err
+	}
+	return app.WriteOutput(data, args.OutputFile, cfg.Output.Overwrite)
+}
+
+// exportTree 将Issue及其全部子Issue导出到输出目录，文档之间互相链接
+func exportTree(ctx context.Context, app *cli.CLI, exporter *cli.Exporter, cfg *config.Config, args *cli.Args) error {
+	root, err := exporter.URLParser.Parse(args.URL)
+	if err != nil {
+		return err
+	}
+	name := func(owner, repo string, number int) string {
+		return cli.TreeOutputFileName(root.Owner, root.Repo, owner, repo, number, cfg.Output.Format)
+	}
+
+	emit := writeDocuments(app, cfg, args.OutputFile, func(doc *cli.ExportedDocument) string {
+		return name(doc.Owner, doc.Repo, doc.Number)
+	})
+	n, err := exporter.ExportTree(ctx, args.URL, name, emit)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Exported %d documents to %s", n, args.OutputFile)
+	return nil
+}
+
+// writeDocuments 返回把批量导出的文档写入输出目录的回调，name给出文档相对输出目录的路径
+func writeDocuments(app *cli.CLI, cfg *config.Config, dir string, name func(*cli.ExportedDocument) string) func(*cli.ExportedDocument) error {
+	return func(doc *cli.ExportedDocument) error {
+		path := filepath.Join(dir, name(doc))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return fmt.Errorf("failed to create output directory: %w", err)
+		}
+		if cfg.Verbose {
+			log.Printf("Writing %s", path)
+		}
+		if err := app.WriteOutput(doc.Data, path, cfg.Output.Overwrite); err != nil {
+			return err
+		}
+		return app.WriteAssets(doc.Assets, filepath.Dir(path))
+	}
+}
+
+// applyArgs 用命令行参数覆盖配置
+func applyArgs(cfg *config.Config, args *cli.Args) {
+	if args.Format != "" {
+		cfg.Output.Format = args.Format
+	}
+	if args.EnableReactions {
+		cfg.Parser.EnableReactions = true
+	}
+	if args.Minimized != "" {
+		cfg.Parser.MinimizedComments = args.Minimized
+	}
+	cfg.Parser.IncludeUserLinks = args.EnableUserLinks
+	cfg.Output.Overwrite = args.Overwrite
+	cfg.Verbose = args.Verbose
+
+	switch {
+	case args.Record != "":
+		cfg.Cassette = config.CassetteConfig{Dir: args.Record, Mode: "record"}
+	case args.Replay != "":
+		cfg.Cassette = config.CassetteConfig{Dir: args.Replay, Mode: "replay"}
+	}
+	if cfg.Cassette.Dir != "" {
+		// 缓存命中的请求不会到达磁带，录制回放时不使用缓存
+		cfg.Cache.Enabled = false
+	}
+
+	if args.Token != "" {
+		cfg.GitHubToken = args.Token
+		cfg.TokenSource = "--token flag"
+	}
+	if args.TokenFile != "" {
+		cfg.TokenFile = args.TokenFile
+	}
+	if args.Token != "" || args.TokenFile != "" {
+		// --token和--token-file只发送给目标URL所在的主机，search子命令发送给搜索的主机
+		if u, err := url.Parse(args.URL); err == nil && u.Host != "" {
+			cfg.TokenHost = strings.ToLower(u.Host)
+		} else if args.Host != "" {
+			cfg.TokenHost = args.Host
+		}
+	}
+
+	if args.CacheDir != "" {
+		cfg.Cache.Dir = args.CacheDir
+	}
+	switch {
+	case args.NoCache:
+		cfg.Cache.Enabled = false
+	case args.Refresh:
+		cfg.Cache.Mode = "refresh"
+	case args.Offline:
+		cfg.Cache.Mode = "offline"
+	}
+}
 
+// initializeServices 初始化服务
+func initializeServices(cfg *config.Config) (*parser.MarkdownParser, converter.Converter) {
 	// 初始化解析器
 	parserOptions := &parser.Options{
 		IncludeComments:    cfg.Parser.IncludeComments,
@@ -95,6 +403,8 @@ func initializeServices(cfg *config.Config) (*github.GitHubClient, *parser.Markd
 		IncludeUserLinks:   cfg.Parser.IncludeUserLinks,
 		EmojisEnabled:      cfg.Parser.EmojisEnabled,
 		PreserveLineBreaks: cfg.Parser.PreserveLineBreaks,
+		EnableReactions:    cfg.Parser.EnableReactions,
+		MinimizedComments:  cfg.Parser.MinimizedComments,
 	}
 	markdownParser := parser.NewParser(parserOptions)
 
@@ -111,5 +421,230 @@ func initializeServices(cfg *config.Config) (*github.GitHubClient, *parser.Markd
 		conv = converter.NewMarkdownConverter(converterOptions)
 	}
 
-	return githubClient, markdownParser, conv, nil
+	return markdownParser, conv
+}
+
+// lookupToken 按凭据链查找访问主机的令牌，详细模式下报告令牌来源
+func lookupToken(cfg *config.Config, host string) (string, error) {
+	cred, err := cfg.LookupToken(host)
+	if err != nil {
+		return "", err
+	}
+	if cred == nil {
+		return "", fmt.Errorf("no GitHub token found for %s: use --token or --token-file, set GITHUB_TOKEN or GH_TOKEN, run `gh auth login`, or add it to ~/.netrc or a git credential helper", host)
+	}
+	if cfg.Verbose {
+		log.Printf("Using token for %s from %s", host, cred.Source)
+	}
+	return cred.Token, nil
+}
+
+// newSourceRegistry 创建GitHub、GitLab和Gitea来源平台的注册表，按URL的主机选择平台
+func newSourceRegistry(cfg *config.Config) *source.Registry {
+	enterpriseHosts, gitlabHosts := cfg.EnterpriseHosts(), cfg.GitLabHosts()
+	// 安装在子路径下的Gitea实例按接口地址去掉URL中的子路径
+	giteaParser := gitea.NewURLParser(cfg.GiteaHosts()...)
+	for _, host := range cfg.GiteaHosts() {
+		if apiURL := cfg.Hosts[host].APIURL; apiURL != "" {
+			giteaParser.SetAPIURL(host, apiURL)
+		}
+	}
+	return source.NewRegistry(
+		&source.Provider{
+			Name:      "github",
+			Hosts:     append([]string{parser.DefaultHost}, enterpriseHosts...),
+			URLParser: parser.NewURLParser(enterpriseHosts...),
+			NewClient: func(host string) (source.Client, error) {
+				return newGitHubClient(cfg, host)
+			},
+		},
+		&source.Provider{
+			Name:      "gitlab",
+			Hosts:     append([]string{gitlab.DefaultHost}, gitlabHosts...),
+			URLParser: gitlab.NewURLParser(gitlabHosts...),
+			NewClient: func(host string) (source.Client, error) {
+				return newGitLabClient(cfg, host)
+			},
+		},
+		&source.Provider{
+			Name:      "gitea",
+			Hosts:     cfg.GiteaHosts(),
+			URLParser: giteaParser,
+			NewClient: func(host string) (source.Client, error) {
+				return newGiteaClient(cfg, host)
+			},
+		},
+	)
+}
+
+// newGitHubClient 创建访问指定主机的GitHub客户端，并按配置设置限流提示和缓存
+func newGitHubClient(cfg *config.Config, host string) (*github.GitHubClient, error) {
+	var base http.RoundTripper
+	token := ""
+	switch {
+	case offline(cfg):
+		// 不访问网络，不需要令牌，也不需要换取App安装令牌
+	case cfg.AppFor(host).Enabled():
+		app := cfg.AppFor(host)
+		appTransport, err := github.NewAppTransportFromFile(nil, app.ID, app.PrivateKeyPath)
+		if err != nil {
+			return nil, err
+		}
+		appTransport.InstallationID = app.InstallationID
+		if host != parser.DefaultHost {
+			appTransport.BaseURL = enterpriseAPIURL(cfg, host)
+		}
+		base = appTransport
+	default:
+		var err error
+		if token, err = lookupToken(cfg, host); err != nil {
+			return nil, err
+		}
+	}
+
+	rt, err := cassetteTransport(cfg, base)
+	if err != nil {
+		return nil, err
+	}
+	httpClient := &http.Client{Transport: rt}
+
+	var githubClient *github.GitHubClient
+	if host == parser.DefaultHost {
+		githubClient = github.NewClientWithHTTPClient(httpClient, token)
+	} else {
+		hostCfg := cfg.Hosts[host]
+		if githubClient, err = github.NewEnterpriseClient(httpClient, enterpriseAPIURL(cfg, host), hostCfg.UploadURL, token); err != nil {
+			return nil, err
+		}
+	}
+
+	githubClient.RateLimiter.OnWait = func(w github.RateLimitWait) {
+		kind := "rate limit"
+		if w.Secondary {
+			kind = "secondary rate limit"
+		}
+		log.Printf("%s %s %s hit, waiting %s", host, w.Resource, kind, w.Duration.Round(time.Second))
+	}
+	configureCache(cfg, githubClient.Cache)
+
+	return githubClient, nil
+}
+
+// enterpriseAPIURL 返回GitHub Enterprise Server主机的REST接口地址
+func enterpriseAPIURL(cfg *config.Config, host string) string {
+	if apiURL := cfg.Hosts[host].APIURL; apiURL != "" {
+		return apiURL
+	}
+	return "https://" + host + "/api/v3/"
+}
+
+// newGitLabClient 创建访问指定主机的GitLab客户端，并按配置设置缓存
+// 找不到令牌时匿名访问，只能导出公开项目
+func newGitLabClient(cfg *config.Config, host string) (*gitlab.Client, error) {
+	token, err := lookupOptionalToken(cfg, host)
+	if err != nil {
+		return nil, err
+	}
+	rt, err := cassetteTransport(cfg, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	apiURL := cfg.Hosts[host].APIURL
+	if apiURL == "" {
+		apiURL = "https://" + host
+	}
+	gitlabClient, err := gitlab.NewClient(&http.Client{Transport: rt}, apiURL, token)
+	if err != nil {
+		return nil, err
+	}
+	configureCache(cfg, gitlabClient.Cache)
+
+	return gitlabClient, nil
+}
+
+// newGiteaClient 创建访问指定主机的Gitea/Forgejo客户端，并按配置设置缓存
+// 找不到令牌时匿名访问，只能导出公开仓库
+func newGiteaClient(cfg *config.Config, host string) (*gitea.Client, error) {
+	token, err := lookupOptionalToken(cfg, host)
+	if err != nil {
+		return nil, err
+	}
+	rt, err := cassetteTransport(cfg, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	apiURL := cfg.Hosts[host].APIURL
+	if apiURL == "" {
+		apiURL = "https://" + host
+	}
+	giteaClient, err := gitea.NewClient(&http.Client{Transport: rt}, apiURL, token)
+	if err != nil {
+		return nil, err
+	}
+	configureCache(cfg, giteaClient.Cache)
+
+	return giteaClient, nil
+}
+
+// offline 判断本次运行是否不访问网络: 回放磁带，或只读取磁盘缓存（-offline）
+// 此时不查找令牌，避免调用gh或git凭据助手
+func offline(cfg *config.Config) bool {
+	replay := cfg.Cassette.Dir != "" && cfg.Cassette.Mode == "replay"
+	return replay || (cfg.Cache.Enabled && cfg.Cache.Mode == "offline")
+}
+
+// lookupOptionalToken 查找访问允许匿名访问的主机使用的令牌，找不到时返回空字符串
+// 不访问网络时不需要令牌
+func lookupOptionalToken(cfg *config.Config, host string) (string, error) {
+	if offline(cfg) {
+		return "", nil
+	}
+	cred, err := cfg.LookupToken(host)
+	if err != nil {
+		return "", err
+	}
+	if cred == nil {
+		if cfg.Verbose {
+			log.Printf("No token found for %s, accessing it anonymously", host)
+		}
+		return "", nil
+	}
+	if cfg.Verbose {
+		log.Printf("Using token for %s from %s", host, cred.Source)
+	}
+	return cred.Token, nil
+}
+
+// cassetteTransport 按配置用录制或回放磁带包装base，未配置磁带时原样返回
+func cassetteTransport(cfg *config.Config, base http.RoundTripper) (http.RoundTripper, error) {
+	if cfg.Cassette.Dir == "" {
+		return base, nil
+	}
+	mode := transport.CassetteRecord
+	if cfg.Cassette.Mode == "replay" {
+		mode = transport.CassetteReplay
+	}
+	cassette, err := transport.NewCassetteTransport(base, cfg.Cassette.Dir, mode)
+	if err != nil {
+		return nil, err
+	}
+	return cassette, nil
+}
+
+// configureCache 按配置启用磁盘缓存
+func configureCache(cfg *config.Config, cache *transport.CacheTransport) {
+	if !cfg.Cache.Enabled {
+		return
+	}
+	cache.Dir = cfg.Cache.Dir
+	switch cfg.Cache.Mode {
+	case "refresh":
+		cache.Mode = transport.CacheRefresh
+	case "offline":
+		cache.Mode = transport.CacheOffline
+	default:
+		cache.Mode = transport.CacheRevalidate
+	}
 }
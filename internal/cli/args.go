@@ -0,0 +1,233 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Args 命令行参数
+type Args struct {
+	URL             string
+	Query           string // search子命令的搜索查询，设置时URL为空
+	Host            string // search子命令搜索的主机，为空时为github.com
+	ImportFile      string // import子命令的导出文件，设置时URL为空
+	RenderFile      string // render子命令读取的JSON文件，-表示标准输入，设置时URL为空
+	OutputFile      string
+	Format          string
+	EnableReactions bool
+	EnableUserLinks bool
+	Overwrite       bool
+	Refresh         bool
+	Offline         bool
+	NoCache         bool
+	CacheDir        string
+	Record          string
+	Replay          string
+	Token           string
+	TokenFile       string
+	Verbose         bool
+	Recursive       bool
+	EditHistory     bool
+	DownloadAssets  bool
+	Minimized       string // 被折叠评论的处理方式: include、collapse或drop，为空时使用配置
+	ShowHelp        bool
+	ShowVersion     bool
+
+	// 以下参数只用于导出整个仓库
+	State      string
+	Labels     []string
+	Milestone  string
+	Assignee   string
+	Creator    string
+	Since      time.Time
+	Sort       string
+	Direction  string
+	IncludePRs bool
+}
+
+// ParseArgs 解析命令行参数
+// 语法为 [flags] <url> [output_file]，未指定输出文件时输出到标准输出；
+// url为仓库地址时导出仓库中符合过滤条件的全部Issue，output_file为输出目录；
+// search [flags] <query> <output_dir> 导出搜索到的全部Issue和Pull Request；
+// import [flags] <file> <output_dir> 导出Jira XML导出或GitHub迁移归档中的全部Issue；
+// render [flags] <json_file|-> [output_file] 渲染gh --json或issue2md JSON输出中的Issue或Pull Request
+// 参数: 无
+// 返回值: (*Args, error) - 解析后的参数；参数无效时返回退出码为2的CLI错误
+func (c *CLI) ParseArgs() (*Args, error) {
+	args := &Args{}
+
+	fs := flag.NewFlagSet(c.name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.StringVar(&args.Format, "format", "", "output format: markdown, html, json")
+	fs.StringVar(&args.Format, "f", "", "output format (shorthand)")
+	fs.BoolVar(&args.EnableReactions, "enable-reactions", false, "include reaction counts")
+	fs.BoolVar(&args.EnableUserLinks, "enable-user-links", false, "render usernames as profile links")
+	fs.BoolVar(&args.Overwrite, "overwrite", false, "overwrite an existing output file")
+	fs.BoolVar(&args.Refresh, "refresh", false, "ignore cached responses and fetch everything again")
+	fs.BoolVar(&args.Offline, "offline", false, "use cached responses only, never touch the network")
+	fs.BoolVar(&args.NoCache, "no-cache", false, "disable the on-disk response cache")
+	fs.StringVar(&args.CacheDir, "cache-dir", "", "directory for cached responses")
+	fs.StringVar(&args.Record, "record", "", "record every GitHub API exchange into this cassette directory")
+	fs.StringVar(&args.Replay, "replay", "", "replay GitHub API exchanges from this cassette directory, never touch the network")
+	fs.StringVar(&args.Token, "token", "", "GitHub token, overrides all other credential sources")
+	fs.StringVar(&args.TokenFile, "token-file", "", "file containing the GitHub token")
+	fs.BoolVar(&args.Verbose, "verbose", false, "report progress and which credential source was used")
+	fs.BoolVar(&args.EditHistory, "edit-history", false, "include the edit history of the body and comments as collapsible diffs")
+	fs.BoolVar(&args.DownloadAssets, "download-assets", false, "download attachments and images into assets/ next to the output and link them locally")
+	fs.StringVar(&args.Minimized, "minimized", "", "how to render comments hidden by moderators: include, collapse or drop")
+	fs.BoolVar(&args.Recursive, "recursive", false, "export an issue together with its whole sub-issue tree into output_file as a directory")
+	var labels, since string
+	fs.StringVar(&args.State, "state", "", "repository export: open, closed or all (default open)")
+	fs.StringVar(&labels, "labels", "", "repository export: comma-separated labels that issues must all have")
+	fs.StringVar(&args.Milestone, "milestone", "", "repository export: milestone number or title, * for any, none for no milestone")
+	fs.StringVar(&args.Assignee, "assignee", "", "repository export: assignee login, * for any, none for unassigned")
+	fs.StringVar(&args.Creator, "creator", "", "repository export: issue author login")
+	fs.StringVar(&since, "since", "", "repository export: only issues updated at or after this time (RFC 3339 or YYYY-MM-DD)")
+	fs.StringVar(&args.Sort, "sort", "", "repository export: created, updated or comments")
+	fs.StringVar(&args.Direction, "direction", "", "repository export: asc or desc")
+	fs.BoolVar(&args.IncludePRs, "include-prs", false, "repository export: also export pull requests")
+	fs.StringVar(&args.Host, "host", "", "search: GitHub Enterprise Server host to search instead of github.com")
+	fs.BoolVar(&args.ShowHelp, "help", false, "show help information")
+	fs.BoolVar(&args.ShowHelp, "h", false, "show help information (shorthand)")
+	fs.BoolVar(&args.ShowVersion, "version", false, "show version information")
+	fs.BoolVar(&args.ShowVersion, "v", false, "show version information (shorthand)")
+
+	if err := fs.Parse(c.args); err != nil {
+		return nil, NewError(err.Error(), 2)
+	}
+	if args.ShowHelp || args.ShowVersion {
+		return args, nil
+	}
+
+	switch fs.Arg(0) {
+	case "search":
+		// 子命令之后仍可以出现参数
+		if err := fs.Parse(fs.Args()[1:]); err != nil {
+			return nil, NewError(err.Error(), 2)
+		}
+		switch fs.NArg() {
+		case 2:
+			args.Query, args.OutputFile = fs.Arg(0), fs.Arg(1)
+		case 0, 1:
+			return nil, NewError("search requires a query and an output directory", 2)
+		default:
+			return nil, NewError("too many arguments, quote the search query", 2)
+		}
+	case "import":
+		if err := fs.Parse(fs.Args()[1:]); err != nil {
+			return nil, NewError(err.Error(), 2)
+		}
+		switch fs.NArg() {
+		case 2:
+			args.ImportFile, args.OutputFile = fs.Arg(0), fs.Arg(1)
+		case 0, 1:
+			return nil, NewError("import requires an export file and an output directory", 2)
+		default:
+			return nil, NewError("too many arguments", 2)
+		}
+	case "render":
+		if err := fs.Parse(fs.Args()[1:]); err != nil {
+			return nil, NewError(err.Error(), 2)
+		}
+		switch fs.NArg() {
+		case 1:
+			args.RenderFile = fs.Arg(0)
+		case 2:
+			args.RenderFile, args.OutputFile = fs.Arg(0), fs.Arg(1)
+		case 0:
+			return nil, NewError("render requires a JSON file, use - for standard input", 2)
+		default:
+			return nil, NewError("too many arguments", 2)
+		}
+	default:
+		switch fs.NArg() {
+		case 1:
+			args.URL = fs.Arg(0)
+		case 2:
+			args.URL, args.OutputFile = fs.Arg(0), fs.Arg(1)
+		case 0:
+			return nil, NewError("missing URL argument", 2)
+		default:
+			return nil, NewError("too many arguments", 2)
+		}
+	}
+
+	if args.Refresh && args.Offline {
+		return nil, NewError("--refresh and --offline cannot be used together", 2)
+	}
+	if args.NoCache && (args.Refresh || args.Offline) {
+		return nil, NewError("--no-cache cannot be combined with --refresh or --offline", 2)
+	}
+
+	if labels != "" {
+		for _, label := range strings.Split(labels, ",") {
+			if label = strings.TrimSpace(label); label != "" {
+				args.Labels = append(args.Labels, label)
+			}
+		}
+	}
+	if since != "" {
+		var err error
+		if args.Since, err = parseSince(since); err != nil {
+			return nil, NewError(err.Error(), 2)
+		}
+	}
+	if err := checkChoice("minimized", args.Minimized, "include", "collapse", "drop"); err != nil {
+		return nil, err
+	}
+	if err := checkChoice("state", args.State, "open", "closed", "all"); err != nil {
+		return nil, err
+	}
+	if err := checkChoice("sort", args.Sort, "created", "updated", "comments"); err != nil {
+		return nil, err
+	}
+	if err := checkChoice("direction", args.Direction, "asc", "desc"); err != nil {
+		return nil, err
+	}
+
+	if args.Host != "" && args.Query == "" {
+		return nil, NewError("--host can only be used with search", 2)
+	}
+	args.Host = strings.ToLower(args.Host)
+	if args.Recursive && (args.URL == "" || args.OutputFile == "") {
+		return nil, NewError("--recursive requires an issue URL and an output directory", 2)
+	}
+	if args.DownloadAssets && args.OutputFile == "" {
+		return nil, NewError("--download-assets requires an output file", 2)
+	}
+	if args.DownloadAssets && (args.ImportFile != "" || args.RenderFile != "") {
+		return nil, NewError("--download-assets cannot be used with import or render", 2)
+	}
+	if args.Record != "" && args.Replay != "" {
+		return nil, NewError("--record and --replay cannot be used together", 2)
+	}
+
+	return args, nil
+}
+
+// parseSince 解析--since参数，支持RFC 3339时间和YYYY-MM-DD日期（按UTC零点）
+func parseSince(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --since %q: use RFC 3339 or YYYY-MM-DD", value)
+}
+
+// checkChoice 检查参数取值是否在允许范围内，空值表示使用默认值
+func checkChoice(name, value string, choices ...string) error {
+	if value == "" {
+		return nil
+	}
+	for _, choice := range choices {
+		if value == choice {
+			return nil
+		}
+	}
+	return NewError(fmt.Sprintf("invalid --%s %q: must be one of %s", name, value, strings.Join(choices, ", ")), 2)
+}
@@ -0,0 +1,203 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseArgs(t *testing.T) {
+	const url = "https://github.com/owner/repo/issues/1"
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    *Args
+		wantErr bool
+	}{
+		{
+			name: "URL only",
+			args: []string{url},
+			want: &Args{URL: url},
+		},
+		{
+			name: "Flags, URL and output file",
+			args: []string{"-enable-reactions", "--enable-user-links", "-f", "json", "--refresh", url, "out.json"},
+			want: &Args{URL: url, OutputFile: "out.json", Format: "json", EnableReactions: true, EnableUserLinks: true, Refresh: true},
+		},
+		{
+			name: "Offline with cache dir",
+			args: []string{"-offline", "-cache-dir", "/tmp/cache", url},
+			want: &Args{URL: url, Offline: true, CacheDir: "/tmp/cache"},
+		},
+		{
+			name: "Credentials and verbose",
+			args: []string{"-token", "ghp_x", "-token-file", "/run/secrets/gh", "-verbose", url},
+			want: &Args{URL: url, Token: "ghp_x", TokenFile: "/run/secrets/gh", Verbose: true},
+		},
+		{
+			name: "Replay cassette",
+			args: []string{"-replay", "testdata/bug-123", url},
+			want: &Args{URL: url, Replay: "testdata/bug-123"},
+		},
+		{
+			name:    "Record and replay",
+			args:    []string{"-record", "a", "-replay", "b", url},
+			wantErr: true,
+		},
+		{
+			name: "Repository export filters",
+			args: []string{"-state", "all", "-labels", "bug, ui,", "-milestone", "v1.0", "-assignee", "*", "-creator", "alice",
+				"-since", "2024-03-01", "-sort", "updated", "-direction", "asc", "-include-prs", "https://github.com/owner/repo", "backlog"},
+			want: &Args{URL: "https://github.com/owner/repo", OutputFile: "backlog", State: "all", Labels: []string{"bug", "ui"},
+				Milestone: "v1.0", Assignee: "*", Creator: "alice", Since: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+				Sort: "updated", Direction: "asc", IncludePRs: true},
+		},
+		{
+			name: "Search with flags after subcommand",
+			args: []string{"-verbose", "search", "-f", "html", "repo:org/x is:closed label:incident", "incidents"},
+			want: &Args{Query: "repo:org/x is:closed label:incident", OutputFile: "incidents", Format: "html", Verbose: true},
+		},
+		{
+			name: "Search on enterprise host",
+			args: []string{"search", "-host", "Git.Example.com", "is:open", "open"},
+			want: &Args{Query: "is:open", Host: "git.example.com", OutputFile: "open"},
+		},
+		{
+			name:    "Host without search",
+			args:    []string{"-host", "git.example.com", "https://git.example.com/org/repo/issues/1"},
+			wantErr: true,
+		},
+		{
+			name:    "Search without output directory",
+			args:    []string{"search", "repo:org/x"},
+			wantErr: true,
+		},
+		{
+			name:    "Search with unquoted query",
+			args:    []string{"search", "repo:org/x", "is:closed", "incidents"},
+			wantErr: true,
+		},
+		{
+			name: "Import with flags after subcommand",
+			args: []string{"import", "-f", "json", "jira.xml", "docs"},
+			want: &Args{ImportFile: "jira.xml", OutputFile: "docs", Format: "json"},
+		},
+		{
+			name:    "Import without output directory",
+			args:    []string{"import", "migration.tar.gz"},
+			wantErr: true,
+		},
+		{
+			name:    "Import with download assets",
+			args:    []string{"import", "-download-assets", "migration.tar.gz", "docs"},
+			wantErr: true,
+		},
+		{
+			name: "Render from standard input",
+			args: []string{"render", "-enable-reactions", "-"},
+			want: &Args{RenderFile: "-", EnableReactions: true},
+		},
+		{
+			name: "Render file to output file",
+			args: []string{"-f", "html", "render", "pr.json", "pr.html"},
+			want: &Args{RenderFile: "pr.json", OutputFile: "pr.html", Format: "html"},
+		},
+		{
+			name:    "Render without input",
+			args:    []string{"render"},
+			wantErr: true,
+		},
+		{
+			name: "Recursive export with edit history",
+			args: []string{"-recursive", "-edit-history", url, "epic"},
+			want: &Args{URL: url, OutputFile: "epic", Recursive: true, EditHistory: true},
+		},
+		{
+			name:    "Recursive without output directory",
+			args:    []string{"-recursive", url},
+			wantErr: true,
+		},
+		{
+			name:    "Invalid state",
+			args:    []string{"-state", "merged", url},
+			wantErr: true,
+		},
+		{
+			name:    "Invalid since",
+			args:    []string{"-since", "last week", url},
+			wantErr: true,
+		},
+		{
+			name: "Help without URL",
+			args: []string{"-h"},
+			want: &Args{ShowHelp: true},
+		},
+		{
+			name:    "Missing URL",
+			args:    []string{"-enable-reactions"},
+			wantErr: true,
+		},
+		{
+			name:    "Too many arguments",
+			args:    []string{url, "a.md", "b.md"},
+			wantErr: true,
+		},
+		{
+			name:    "Refresh and offline",
+			args:    []string{"-refresh", "-offline", url},
+			wantErr: true,
+		},
+		{
+			name:    "No cache and offline",
+			args:    []string{"-no-cache", "-offline", url},
+			wantErr: true,
+		},
+		{
+			name:    "Unknown flag",
+			args:    []string{"-bogus", url},
+			wantErr: true,
+		},
+		{
+			name: "Minimized policy",
+			args: []string{"-minimized", "drop", url},
+			want: &Args{URL: url, Minimized: "drop"},
+		},
+		{
+			name: "Download assets",
+			args: []string{"-download-assets", url, "out.md"},
+			want: &Args{URL: url, OutputFile: "out.md", DownloadAssets: true},
+		},
+		{
+			name:    "Download assets to stdout",
+			args:    []string{"-download-assets", url},
+			wantErr: true,
+		},
+		{
+			name:    "Unknown minimized policy",
+			args:    []string{"-minimized", "hide", url},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewCLI("issue2md", tt.args).ParseArgs()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("ParseArgs() expected error, but got nil")
+				}
+				if cliErr, ok := err.(*Error); !ok || cliErr.Code != 2 {
+					t.Errorf("ParseArgs() error = %#v, want CLI error with code 2", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseArgs() unexpected error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseArgs() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package cli
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/bigwhite/issue2md/internal/model"
+	"github.com/bigwhite/issue2md/internal/parser"
+	"github.com/bigwhite/issue2md/internal/source"
+)
+
+// AssetDir 下载的附件所在目录，位于导出文件旁，文档中的链接相对导出文件
+const AssetDir = "assets"
+
+// assetURLPattern 匹配文档中的链接地址，包括Markdown链接、HTML属性和单独成行的地址
+var assetURLPattern = regexp.MustCompile(`https?://[^\s()<>"'\[\]]+`)
+
+// assetHosts 存放用户上传图片的GitHub主机，地址带有签名，一段时间后失效
+var assetHosts = map[string]bool{
+	"user-images.githubusercontent.com":         true,
+	"private-user-images.githubusercontent.com": true,
+}
+
+// assetExtensions 常见附件类型的扩展名，地址本身没有扩展名时使用
+var assetExtensions = map[string]string{
+	"image/png":       ".png",
+	"image/jpeg":      ".jpg",
+	"image/gif":       ".gif",
+	"image/webp":      ".webp",
+	"image/svg+xml":   ".svg",
+	"video/mp4":       ".mp4",
+	"video/quicktime": ".mov",
+	"application/pdf": ".pdf",
+	"application/zip": ".zip",
+	"text/plain":      ".txt",
+}
+
+// localizeAssets 下载文档中引用的附件和图片，并把链接改写为AssetDir下的相对路径
+// 同一地址只下载一次；下载失败的链接保持原样并报告给OnAssetError，客户端不支持下载附件时不做改动
+// 返回值为文件名到内容的映射，文件名由内容的SHA-256决定
+func (e *Exporter) localizeAssets(ctx context.Context, client source.Client, host string, doc *parser.MarkdownDocument) (map[string][]byte, error) {
+	downloader, ok := client.(source.AssetClient)
+	if !ok {
+		return nil, nil
+	}
+	if host == "" {
+		host = parser.DefaultHost
+	}
+
+	assets := make(map[string][]byte)
+	local := make(map[string]string)
+	for _, rawURL := range assetURLPattern.FindAllString(doc.Content, -1) {
+		if _, done := local[rawURL]; done || !isAssetURL(rawURL, host) {
+			continue
+		}
+
+		asset, err := downloader.DownloadAsset(ctx, rawURL)
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return nil, ctxErr
+			}
+			if e.OnAssetError != nil {
+				e.OnAssetError(rawURL, err)
+			}
+			local[rawURL] = ""
+			continue
+		}
+		name := assetFileName(rawURL, asset)
+		assets[name] = asset.Data
+		local[rawURL] = AssetDir + "/" + name
+	}
+	if len(assets) == 0 {
+		return nil, nil
+	}
+
+	doc.Content = rewriteAssetLinks(doc.Content, local)
+	return assets, nil
+}
+
+// gitLabUploadPattern 匹配GitLab上传文件的路径，如 /<group>/<project>/uploads/<32位十六进制>/<文件名>
+var gitLabUploadPattern = regexp.MustCompile(`/uploads/[0-9a-f]{32}/[^/]+$`)
+
+// giteaAttachmentPattern 匹配Gitea附件的路径，如 /attachments/<uuid> 或 /<owner>/<repo>/attachments/<uuid>
+var giteaAttachmentPattern = regexp.MustCompile(`^(/[^/]+/[^/]+)?/attachments/[0-9a-f]{8}(-[0-9a-f]{4}){3}-[0-9a-f]{12}$`)
+
+// isAssetURL 判断地址是否为用户上传到GitHub、GitLab或Gitea的附件或图片
+// 包括 https://<host>/user-attachments/...、旧式的 https://<host>/<owner>/<repo>/assets/...、
+// GitLab的 https://<host>/<project>/uploads/...、Gitea的 https://<host>/attachments/... 和用户图片主机
+func isAssetURL(rawURL, host string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	if assetHosts[strings.ToLower(u.Host)] {
+		return true
+	}
+	if !strings.EqualFold(u.Host, host) {
+		return false
+	}
+	if strings.HasPrefix(u.Path, "/user-attachments/") || gitLabUploadPattern.MatchString(u.Path) || giteaAttachmentPattern.MatchString(u.Path) {
+		return true
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	return len(parts) > 3 && parts[2] == "assets"
+}
+
+// rewriteAssetLinks 把已下载的地址替换为本地路径
+// 单独出现的地址在GitHub上会渲染为链接或内嵌视频，替换后写成Markdown链接以保持可点击；
+// 位于链接文字、链接目标或HTML属性中的地址（前一个字符为[、(、引号、=或<）直接替换为本地路径
+func rewriteAssetLinks(content string, local map[string]string) string {
+	var b strings.Builder
+	last := 0
+	for _, loc := range assetURLPattern.FindAllStringIndex(content, -1) {
+		target := local[content[loc[0]:loc[1]]]
+		if target == "" {
+			continue
+		}
+		b.WriteString(content[last:loc[0]])
+		if loc[0] > 0 && strings.IndexByte(`[("'=<`, content[loc[0]-1]) >= 0 {
+			b.WriteString(target)
+		} else {
+			fmt.Fprintf(&b, "[%s](%s)", path.Base(target), target)
+		}
+		last = loc[1]
+	}
+	b.WriteString(content[last:])
+	return b.String()
+}
+
+// assetFileName 返回附件的文件名: 内容的SHA-256加扩展名
+// 扩展名优先取自地址路径，其次按Content-Type推断
+func assetFileName(rawURL string, asset *model.Asset) string {
+	sum := sha256.Sum256(asset.Data)
+	name := hex.EncodeToString(sum[:])
+
+	if u, err := url.Parse(rawURL); err == nil {
+		if ext := strings.ToLower(path.Ext(u.Path)); len(ext) > 1 && len(ext) <= 6 {
+			return name + ext
+		}
+	}
+	mediaType, _, _ := mime.ParseMediaType(asset.ContentType)
+	if ext, ok := assetExtensions[mediaType]; ok {
+		return name + ext
+	}
+	if exts, _ := mime.ExtensionsByType(mediaType); len(exts) > 0 {
+		return name + exts[0]
+	}
+	return name
+}
+
+// WriteAssets 把下载的附件写入dir下的AssetDir目录
+// 文件名由内容决定，已存在的文件内容相同，直接跳过
+// 参数:
+//   - assets: 文件名到内容的映射
+//   - dir: 导出文件所在目录
+//
+// 返回值: error - 创建目录或写入失败时返回错误
+func (c *CLI) WriteAssets(assets map[string][]byte, dir string) error {
+	if len(assets) == 0 {
+		return nil
+	}
+
+	assetDir := filepath.Join(dir, AssetDir)
+	if err := os.MkdirAll(assetDir, 0o755); err != nil {
+		return fmt.Errorf("failed to create asset directory: %w", err)
+	}
+	for name, data := range assets {
+		file := filepath.Join(assetDir, name)
+		if _, err := os.Stat(file); err == nil {
+			continue
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to check asset %s: %w", name, err)
+		}
+		if err := os.WriteFile(file, data, 0o644); err != nil {
+			return fmt.Errorf("failed to write asset %s: %w", name, err)
+		}
+	}
+	return nil
+}
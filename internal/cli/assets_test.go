@@ -0,0 +1,180 @@
+package cli
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bigwhite/issue2md/internal/converter"
+	"github.com/bigwhite/issue2md/internal/model"
+	"github.com/bigwhite/issue2md/internal/parser"
+)
+
+// assetClient 正文带有附件的客户端，expired.png下载失败
+type assetClient struct {
+	stubClient
+	downloads []string
+}
+
+func (c *assetClient) GetIssue(ctx context.Context, owner, repo string, number int) (*model.Issue, error) {
+	return &model.Issue{
+		Number: number,
+		Title:  "Screenshot",
+		State:  "open",
+		Body: "![shot](https://github.com/user-attachments/assets/1a2b)\n" +
+			"<img src=\"https://github.com/user-attachments/assets/1a2b\" width=\"300\">\n" +
+			"https://github.com/user-attachments/assets/1a2b\n" +
+			"[log](https://github.com/user-attachments/files/7/build.log)\n" +
+			"![old](https://user-images.githubusercontent.com/1/expired.png)\n" +
+			"![badge](https://img.shields.io/badge/ci-passing-green.svg)",
+	}, nil
+}
+
+func (c *assetClient) DownloadAsset(ctx context.Context, rawURL string) (*model.Asset, error) {
+	c.downloads = append(c.downloads, rawURL)
+	switch {
+	case strings.HasSuffix(rawURL, "/1a2b"):
+		return &model.Asset{Data: []byte("PNG"), ContentType: "image/png"}, nil
+	case strings.HasSuffix(rawURL, "/build.log"):
+		return &model.Asset{Data: []byte("ok"), ContentType: "text/plain; charset=utf-8"}, nil
+	default:
+		return nil, errors.New("404 Not Found")
+	}
+}
+
+func TestExporterDownloadAssets(t *testing.T) {
+	client := &assetClient{}
+	var failed []string
+	exporter := &Exporter{
+		Client:         client,
+		URLParser:      parser.NewURLParser(),
+		Parser:         parser.NewParser(&parser.Options{}),
+		Converter:      converter.NewMarkdownConverter(nil),
+		DownloadAssets: true,
+		OnAssetError:   func(rawURL string, err error) { failed = append(failed, rawURL) },
+	}
+
+	doc, err := exporter.ExportDocument(context.Background(), "https://github.com/owner/repo/issues/1")
+	if err != nil {
+		t.Fatalf("ExportDocument() unexpected error = %v", err)
+	}
+
+	png := assetName([]byte("PNG"), ".png")
+	log := assetName([]byte("ok"), ".log")
+	for _, want := range []string{
+		"![shot](assets/" + png + ")\n",
+		"<img src=\"assets/" + png + "\" width=\"300\">\n",
+		"[" + png + "](assets/" + png + ")\n",
+		"[log](assets/" + log + ")\n",
+		"![old](https://user-images.githubusercontent.com/1/expired.png)\n",
+		"![badge](https://img.shields.io/badge/ci-passing-green.svg)",
+	} {
+		if !strings.Contains(string(doc.Data), want) {
+			t.Errorf("ExportDocument() content missing %q\n%s", want, doc.Data)
+		}
+	}
+	if len(doc.Assets) != 2 || string(doc.Assets[png]) != "PNG" || string(doc.Assets[log]) != "ok" {
+		t.Errorf("ExportDocument() assets = %v", doc.Assets)
+	}
+	if len(client.downloads) != 3 {
+		t.Errorf("downloads = %v, want each asset URL once", client.downloads)
+	}
+	if len(failed) != 1 || !strings.HasSuffix(failed[0], "expired.png") {
+		t.Errorf("OnAssetError() calls = %v, want expired.png", failed)
+	}
+}
+
+// assetName 返回内容对应的附件文件名
+func assetName(data []byte, ext string) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]) + ext
+}
+
+func TestIsAssetURL(t *testing.T) {
+	tests := []struct {
+		url  string
+		host string
+		want bool
+	}{
+		{url: "https://github.com/user-attachments/assets/1a2b", host: "github.com", want: true},
+		{url: "https://github.com/owner/repo/assets/1/abcd", host: "github.com", want: true},
+		{url: "https://private-user-images.githubusercontent.com/1/x.png?jwt=y", host: "github.com", want: true},
+		{url: "https://git.example.com/user-attachments/assets/1a2b", host: "git.example.com", want: true},
+		{url: "https://git.example.com/user-attachments/assets/1a2b", host: "github.com", want: false},
+		{url: "https://gitlab.com/group/app/uploads/0123456789abcdef0123456789abcdef/log.png", host: "gitlab.com", want: true},
+		{url: "https://gitlab.com/group/app/-/blob/main/uploads/readme.md", host: "gitlab.com", want: false},
+		{url: "https://forge.example.com/attachments/0b2c9a3e-1111-2222-3333-444455556666", host: "forge.example.com", want: true},
+		{url: "https://forge.example.com/team/app/attachments/0b2c9a3e-1111-2222-3333-444455556666", host: "forge.example.com", want: true},
+		{url: "https://github.com/owner/repo/issues/1", host: "github.com", want: false},
+		{url: "https://example.com/image.png", host: "github.com", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := isAssetURL(tt.url, tt.host); got != tt.want {
+			t.Errorf("isAssetURL(%q, %q) = %v, want %v", tt.url, tt.host, got, tt.want)
+		}
+	}
+}
+
+func TestRewriteAssetLinks(t *testing.T) {
+	local := map[string]string{"https://github.com/user-attachments/assets/a.png": "assets/1a2b.png"}
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "bare URL",
+			content: "see https://github.com/user-attachments/assets/a.png\n",
+			want:    "see [1a2b.png](assets/1a2b.png)\n",
+		},
+		{
+			name:    "image",
+			content: "![shot](https://github.com/user-attachments/assets/a.png)",
+			want:    "![shot](assets/1a2b.png)",
+		},
+		{
+			name:    "URL as link text",
+			content: "[https://github.com/user-attachments/assets/a.png](https://github.com/user-attachments/assets/a.png)",
+			want:    "[assets/1a2b.png](assets/1a2b.png)",
+		},
+		{
+			name:    "HTML attribute",
+			content: `<img src="https://github.com/user-attachments/assets/a.png" width=200>`,
+			want:    `<img src="assets/1a2b.png" width=200>`,
+		},
+		{
+			name:    "not downloaded",
+			content: "https://example.com/b.png",
+			want:    "https://example.com/b.png",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rewriteAssetLinks(tt.content, local); got != tt.want {
+				t.Errorf("rewriteAssetLinks() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCLIWriteAssets(t *testing.T) {
+	dir := t.TempDir()
+	existing := filepath.Join(dir, AssetDir, "a.png")
+	os.MkdirAll(filepath.Dir(existing), 0o755)
+	os.WriteFile(existing, []byte("old"), 0o444)
+
+	app := NewCLI("issue2md", nil)
+	if err := app.WriteAssets(map[string][]byte{"a.png": []byte("old"), "b.gif": []byte("GIF")}, dir); err != nil {
+		t.Fatalf("WriteAssets() unexpected error = %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, AssetDir, "b.gif")); string(data) != "GIF" {
+		t.Errorf("b.gif = %q, want GIF", data)
+	}
+}
@@ -0,0 +1,691 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bigwhite/issue2md/internal/converter"
+	"github.com/bigwhite/issue2md/internal/github"
+	"github.com/bigwhite/issue2md/internal/importer"
+	"github.com/bigwhite/issue2md/internal/model"
+	"github.com/bigwhite/issue2md/internal/parser"
+	"github.com/bigwhite/issue2md/internal/source"
+)
+
+// Exporter 串联获取、解析和转换，将GitHub或GitLab资源导出为目标格式
+type Exporter struct {
+	// Client 访问github.com使用的客户端
+	Client source.Client
+	// ClientForHost 按主机创建客户端，用于GitHub Enterprise Server、GitLab或延迟创建github.com客户端，
+	// 可直接使用source.Registry.ClientForHost；为nil时所有主机都使用Client
+	ClientForHost func(host string) (source.Client, error)
+	URLParser     parser.URLParser
+	Parser        parser.Parser
+	Converter     converter.Converter
+	// 以下附加数据只在客户端实现了source中对应的可选接口时获取，来源平台不支持时跳过
+
+	// Timeline 是否获取Issue时间线事件（需要GraphQL认证）
+	Timeline bool
+	// SubIssues 是否获取Issue的父Issue、子Issue和正文任务列表跟踪的Issue
+	SubIssues bool
+	// Resolution 是否获取关闭Issue的Pull Request和提交（需要GraphQL认证），只对已关闭的Issue生效
+	Resolution bool
+	// EditHistory 是否获取Issue和Pull Request正文及评论的修改历史（需要GraphQL认证）
+	EditHistory bool
+	// Minimized 是否获取Issue和Pull Request评论的折叠状态（需要GraphQL认证），Discussion评论总是带有折叠状态
+	Minimized bool
+	// Projects 是否获取Issue和Pull Request所属的Projects (v2)条目及字段值（需要GraphQL认证和read:project权限）
+	Projects bool
+	// DownloadAssets 是否下载正文和评论中引用的附件和图片，并把链接改写为AssetDir下的相对路径
+	DownloadAssets bool
+	// OnAssetError 附件下载失败时调用，失败的链接保持原样；为nil时忽略失败
+	OnAssetError func(rawURL string, err error)
+	// OnProjectsError 令牌无权读取Projects (v2)时调用，文档不包含Projects条目，导出继续；
+	// 为nil时权限错误和其他错误一样使导出失败
+	OnProjectsError func(url string, err error)
+}
+
+// Export 导出URL对应的Issue、Pull Request或Discussion
+// 参数:
+//   - ctx: 上下文，用于取消请求
+//   - rawURL: GitHub资源URL
+//
+// 返回值: ([]byte, error) - 转换后的文档内容，获取或转换失败时返回错误
+func (e *Exporter) Export(ctx context.Context, rawURL string) ([]byte, error) {
+	exported, err := e.ExportDocument(ctx, rawURL)
+	if err != nil {
+		return nil, err
+	}
+	return exported.Data, nil
+}
+
+// ExportDocument 导出URL对应的Issue、Pull Request或Discussion，结果带有下载的附件
+// 参数:
+//   - ctx: 上下文，用于取消请求
+//   - rawURL: GitHub资源URL
+//
+// 返回值: (*ExportedDocument, error) - 导出的文档，获取或转换失败时返回错误
+func (e *Exporter) ExportDocument(ctx context.Context, rawURL string) (*ExportedDocument, error) {
+	res, err := e.URLParser.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := e.client(res.Host)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := e.document(ctx, client, res)
+	if err != nil {
+		return nil, err
+	}
+	return e.exported(ctx, client, res, doc)
+}
+
+// exported 按设置下载附件并转换文档
+func (e *Exporter) exported(ctx context.Context, client source.Client, res *parser.ResourceURL, doc *parser.MarkdownDocument) (*ExportedDocument, error) {
+	exported := &ExportedDocument{Owner: res.Owner, Repo: res.Repo, Number: res.Number, Type: res.Type}
+	if e.DownloadAssets {
+		var err error
+		if exported.Assets, err = e.localizeAssets(ctx, client, res.Host, doc); err != nil {
+			return nil, err
+		}
+	}
+
+	data, err := e.Converter.Convert(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert %s/%s#%d: %w", res.Owner, res.Repo, res.Number, err)
+	}
+	exported.Data = data
+	return exported, nil
+}
+
+// client 返回访问指定主机的客户端
+// github.com优先使用Client，未设置Client时与其他主机一样由ClientForHost创建
+func (e *Exporter) client(host string) (source.Client, error) {
+	if host == "" {
+		host = parser.DefaultHost
+	}
+	if e.ClientForHost == nil || (e.Client != nil && host == parser.DefaultHost) {
+		return e.Client, nil
+	}
+	client, err := e.ClientForHost(host)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client for %s: %w", host, err)
+	}
+	return client, nil
+}
+
+// document 按资源类型获取数据并渲染为文档
+func (e *Exporter) document(ctx context.Context, client source.Client, res *parser.ResourceURL) (*parser.MarkdownDocument, error) {
+	switch res.Type {
+	case "issue":
+		issue, err := client.GetIssue(ctx, res.Owner, res.Repo, res.Number)
+		if err != nil {
+			return nil, err
+		}
+		return e.issueDocument(ctx, client, res, issue)
+	case "pull":
+		var pr *model.PullRequest
+		g, groupCtx := github.NewGroup(ctx, 0)
+		g.Go(func() error {
+			var err error
+			pr, err = client.GetPullRequest(groupCtx, res.Owner, res.Repo, res.Number)
+			return err
+		})
+		d := e.fetchDetails(groupCtx, g, client, res)
+		var projects []*model.ProjectItem
+		if pc, ok := client.(source.ProjectClient); ok && e.Projects {
+			g.Go(func() error {
+				var err error
+				projects, err = e.projectItems(groupCtx, pc, res)
+				return err
+			})
+		}
+		if err := g.Wait(); err != nil {
+			return nil, err
+		}
+
+		d.apply(&pr.Edits, pr.Comments)
+		pr.Projects = projects
+		return e.Parser.ParsePullRequest(pr)
+	case "discussion":
+		discussions, ok := client.(source.DiscussionClient)
+		if !ok {
+			return nil, fmt.Errorf("discussions: %w", source.ErrUnsupported)
+		}
+		discussion, err := discussions.GetDiscussion(ctx, res.Owner, res.Repo, res.Number)
+		if err != nil {
+			return nil, err
+		}
+		return e.Parser.ParseDiscussion(discussion)
+	case "epic":
+		epics, ok := client.(source.EpicClient)
+		if !ok {
+			return nil, fmt.Errorf("epics: %w", source.ErrUnsupported)
+		}
+		epic, comments, err := epics.GetEpic(ctx, res.Owner, res.Number)
+		if err != nil {
+			return nil, err
+		}
+		return e.Parser.Parse(epic, comments)
+	default:
+		return nil, fmt.Errorf("unsupported resource type %q", res.Type)
+	}
+}
+
+// issueDocument 获取已取得的Issue的评论和时间线并渲染为文档
+func (e *Exporter) issueDocument(ctx context.Context, client source.Client, res *parser.ResourceURL, issue *model.Issue) (*parser.MarkdownDocument, error) {
+	comments, err := e.issueDetails(ctx, client, res, issue, e.SubIssues)
+	if err != nil {
+		return nil, err
+	}
+	return e.Parser.Parse(issue, comments)
+}
+
+// issueDetails 获取Issue的评论，按设置填充时间线、修改历史、折叠状态、所属项目和解决方式，relations为true时填充父子关系
+// 客户端不支持的数据跳过；各项数据来自互相独立的请求，并发获取，任一请求失败会取消其余请求；
+// 全部成功后再按固定顺序合并，结果与逐个获取时相同
+func (e *Exporter) issueDetails(ctx context.Context, client source.Client, res *parser.ResourceURL, issue *model.Issue, relations bool) ([]*model.Comment, error) {
+	var (
+		comments   []*model.Comment
+		timeline   []*model.TimelineEvent
+		projects   []*model.ProjectItem
+		resolution *model.Resolution
+		parent     *model.IssueReference
+		subIssues  []*model.SubIssue
+	)
+
+	g, groupCtx := github.NewGroup(ctx, 0)
+	g.Go(func() error {
+		var err error
+		comments, err = client.GetIssueComments(groupCtx, res.Owner, res.Repo, res.Number)
+		return err
+	})
+	if tc, ok := client.(source.TimelineClient); ok && e.Timeline {
+		g.Go(func() error {
+			var err error
+			timeline, err = tc.GetIssueTimeline(groupCtx, res.Owner, res.Repo, res.Number)
+			return err
+		})
+	}
+	d := e.fetchDetails(groupCtx, g, client, res)
+	if pc, ok := client.(source.ProjectClient); ok && e.Projects {
+		g.Go(func() error {
+			var err error
+			projects, err = e.projectItems(groupCtx, pc, res)
+			return err
+		})
+	}
+	if rc, ok := client.(source.ResolutionClient); ok && e.Resolution && issue.ClosedAt != nil {
+		g.Go(func() error {
+			var err error
+			resolution, err = rc.GetIssueResolution(groupCtx, res.Owner, res.Repo, res.Number)
+			return err
+		})
+	}
+	if sc, ok := client.(source.SubIssueClient); ok && relations {
+		g.Go(func() error {
+			var err error
+			parent, err = sc.GetParentIssue(groupCtx, res.Owner, res.Repo, res.Number)
+			return err
+		})
+		g.Go(func() error {
+			var err error
+			subIssues, err = sc.GetSubIssues(groupCtx, res.Owner, res.Repo, res.Number)
+			return err
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	if e.Timeline {
+		issue.Timeline = timeline
+	}
+	d.apply(&issue.Edits, comments)
+	if e.Projects {
+		issue.Projects = projects
+	}
+	if e.Resolution && issue.ClosedAt != nil {
+		issue.Resolution = resolution
+	}
+	if relations {
+		issue.Parent, issue.SubIssues = parent, mergeTrackedIssues(res, issue.Body, subIssues)
+	}
+	return comments, nil
+}
+
+// commentDetails 评论的修改历史和折叠状态，由fetchDetails并发获取
+type commentDetails struct {
+	history   *model.EditHistory
+	minimized map[int64]string
+}
+
+// projectItems 获取Issue或Pull Request所属的Projects条目
+// 权限不足且设置了OnProjectsError时报告错误并返回nil，其余错误原样返回
+func (e *Exporter) projectItems(ctx context.Context, client source.ProjectClient, res *parser.ResourceURL) ([]*model.ProjectItem, error) {
+	projects, err := client.GetProjectItems(ctx, res.Owner, res.Repo, res.Number)
+	if err != nil && github.IsPermissionError(err) && e.OnProjectsError != nil {
+		e.OnProjectsError(res.URL, err)
+		return nil, nil
+	}
+	return projects, err
+}
+
+// fetchDetails 按设置把获取修改历史和被折叠评论的请求加入g，g.Wait成功后结果才可用
+func (e *Exporter) fetchDetails(ctx context.Context, g *github.Group, client source.Client, res *parser.ResourceURL) *commentDetails {
+	d := &commentDetails{}
+	if ec, ok := client.(source.EditHistoryClient); ok && e.EditHistory {
+		g.Go(func() error {
+			var err error
+			d.history, err = ec.GetEditHistory(ctx, res.Owner, res.Repo, res.Number)
+			return err
+		})
+	}
+	if mc, ok := client.(source.MinimizedClient); ok && e.Minimized {
+		g.Go(func() error {
+			var err error
+			d.minimized, err = mc.GetMinimizedComments(ctx, res.Owner, res.Repo, res.Number)
+			return err
+		})
+	}
+	return d
+}
+
+// apply 把修改历史填充到正文和按ID对应的评论，并按ID标记被折叠的评论
+func (d *commentDetails) apply(edits *[]*model.Edit, comments []*model.Comment) {
+	if d.history != nil {
+		*edits = d.history.Body
+	}
+	for _, comment := range comments {
+		if d.history != nil {
+			comment.Edits = d.history.Comments[comment.ID]
+		}
+		if reason, ok := d.minimized[comment.ID]; ok {
+			comment.Minimized, comment.MinimizedReason = true, reason
+		}
+	}
+}
+
+// mergeTrackedIssues 合并子Issue和正文任务列表跟踪的Issue
+// 子Issue在前，正文任务列表跟踪的Issue在后，同一个Issue只保留一次
+func mergeTrackedIssues(res *parser.ResourceURL, body string, subIssues []*model.SubIssue) []*model.SubIssue {
+	seen := make(map[string]bool)
+	for _, sub := range subIssues {
+		seen[referenceKey(&sub.IssueReference)] = true
+	}
+	for _, tracked := range github.TrackedIssues(body, res.Owner, res.Repo) {
+		if !seen[referenceKey(&tracked.IssueReference)] {
+			subIssues = append(subIssues, tracked)
+		}
+	}
+	return subIssues
+}
+
+// referenceKey 返回Issue引用的唯一键，如 owner/repo#12
+func referenceKey(ref *model.IssueReference) string {
+	return fmt.Sprintf("%s#%d", strings.ToLower(ref.Repo), ref.Number)
+}
+
+// ExportedDocument 批量导出得到的一个文档
+type ExportedDocument struct {
+	Owner  string
+	Repo   string
+	Number int
+	Type   string // "issue" 或 "pull"
+	Data   []byte
+	// Assets 下载的附件，文件名到内容，应写入文档旁的AssetDir目录
+	Assets map[string][]byte
+}
+
+// ExportIssues 导出仓库中符合条件的全部Issue
+// 参数:
+//   - ctx: 上下文，用于取消请求
+//   - rawURL: 仓库URL
+//   - opts: 过滤条件，为nil时导出全部打开的Issue
+//   - emit: 每导出一个文档调用一次，返回错误时停止导出
+//
+// 返回值: (int, error) - 导出的文档数，列出、获取、转换失败或emit返回错误时返回错误
+func (e *Exporter) ExportIssues(ctx context.Context, rawURL string, opts *model.IssueListOptions, emit func(*ExportedDocument) error) (int, error) {
+	repo, err := e.URLParser.ParseRepository(rawURL)
+	if err != nil {
+		return 0, err
+	}
+
+	client, err := e.client(repo.Host)
+	if err != nil {
+		return 0, err
+	}
+
+	lister, ok := client.(source.ListClient)
+	if !ok {
+		return 0, fmt.Errorf("listing issues: %w", source.ErrUnsupported)
+	}
+	issues, err := lister.ListIssues(ctx, repo.Owner, repo.Repo, opts)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, issue := range issues {
+		res := &parser.ResourceURL{
+			Type:   "issue",
+			Host:   repo.Host,
+			Owner:  repo.Owner,
+			Repo:   repo.Repo,
+			Number: issue.Number,
+			URL:    issue.HTMLURL,
+		}
+		if err := e.exportListed(ctx, client, res, issue, emit); err != nil {
+			return i, err
+		}
+	}
+	return len(issues), nil
+}
+
+// ExportSearch 导出GitHub搜索语法查询到的全部Issue和Pull Request
+// 参数:
+//   - ctx: 上下文，用于取消请求
+//   - host: 搜索的主机，如GitHub Enterprise Server主机，为空时为github.com
+//   - query: 搜索查询，如 repo:org/x is:closed label:incident
+//   - emit: 每导出一个文档调用一次，返回错误时停止导出
+//
+// 返回值: (int, error) - 导出的文档数，搜索、获取、转换失败或emit返回错误时返回错误
+func (e *Exporter) ExportSearch(ctx context.Context, host, query string, emit func(*ExportedDocument) error) (int, error) {
+	if host == "" {
+		host = parser.DefaultHost
+	}
+	client, err := e.client(host)
+	if err != nil {
+		return 0, err
+	}
+
+	searcher, ok := client.(source.SearchClient)
+	if !ok {
+		return 0, fmt.Errorf("search: %w", source.ErrUnsupported)
+	}
+	issues, err := searcher.SearchIssues(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, issue := range issues {
+		owner, repo, ok := strings.Cut(issue.Repository, "/")
+		if !ok {
+			return i, fmt.Errorf("search result #%d has no repository", issue.Number)
+		}
+		res := &parser.ResourceURL{
+			Type:   "issue",
+			Host:   host,
+			Owner:  owner,
+			Repo:   repo,
+			Number: issue.Number,
+			URL:    issue.HTMLURL,
+		}
+		if err := e.exportListed(ctx, client, res, issue, emit); err != nil {
+			return i, err
+		}
+	}
+	return len(issues), nil
+}
+
+// ExportImport 导出离线导出文件中的全部Issue和Pull Request，不访问任何API，也不下载附件
+// 文件可以是Jira的XML导出或GitHub组织迁移归档，按内容识别格式；
+// Jira Issue的Owner为项目键、Repo为空，迁移归档中的Issue和Pull Request按所属仓库填充Owner和Repo
+// 参数:
+//   - ctx: 上下文，取消后停止导出
+//   - path: 导出文件路径
+//   - emit: 每导出一个文档调用一次，返回错误时停止导出
+//
+// 返回值: (int, error) - 导出的文档数，读取、转换失败或emit返回错误时返回错误
+func (e *Exporter) ExportImport(ctx context.Context, path string, emit func(*ExportedDocument) error) (int, error) {
+	count := 0
+	err := importer.ReadFile(path, func(record *importer.Record) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		issue := record.Issue
+		kind := "issue"
+		var doc *parser.MarkdownDocument
+		var err error
+		if record.PullRequest != nil {
+			kind = "pull"
+			doc, err = e.Parser.ParsePullRequest(record.PullRequest)
+		} else {
+			doc, err = e.Parser.Parse(issue, record.Comments)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to export %s#%d: %w", issue.Repository, issue.Number, err)
+		}
+		data, err := e.Converter.Convert(doc)
+		if err != nil {
+			return fmt.Errorf("failed to convert %s#%d: %w", issue.Repository, issue.Number, err)
+		}
+
+		owner, repo, _ := strings.Cut(issue.Repository, "/")
+		if err := emit(&ExportedDocument{Owner: owner, Repo: repo, Number: issue.Number, Type: kind, Data: data}); err != nil {
+			return err
+		}
+		count++
+		return nil
+	})
+	return count, err
+}
+
+// ExportSnapshot 渲染JSON中的Issue或Pull Request，不访问任何API，也不下载附件
+// 参数:
+//   - r: gh issue view --json、gh pr view --json或issue2md JSON输出的内容
+//
+// 返回值: ([]byte, error) - 转换后的文档内容，JSON无法识别或转换失败时返回错误
+func (e *Exporter) ExportSnapshot(r io.Reader) ([]byte, error) {
+	snapshot, err := importer.ReadSnapshot(r)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := snapshot.Document
+	switch {
+	case snapshot.PullRequest != nil:
+		doc, err = e.Parser.ParsePullRequest(snapshot.PullRequest)
+	case snapshot.Issue != nil:
+		doc, err = e.Parser.Parse(snapshot.Issue, snapshot.Comments)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return e.Converter.Convert(doc)
+}
+
+// exportListed 导出列表或搜索结果中的一项，Pull Request会重新获取完整数据
+func (e *Exporter) exportListed(ctx context.Context, client source.Client, res *parser.ResourceURL, issue *model.Issue, emit func(*ExportedDocument) error) error {
+	var doc *parser.MarkdownDocument
+	var err error
+	if issue.IsPullRequest {
+		res.Type = "pull"
+		doc, err = e.document(ctx, client, res)
+	} else {
+		doc, err = e.issueDocument(ctx, client, res, issue)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to export %s/%s#%d: %w", res.Owner, res.Repo, res.Number, err)
+	}
+
+	exported, err := e.exported(ctx, client, res, doc)
+	if err != nil {
+		return err
+	}
+	return emit(exported)
+}
+
+// ExportTree 递归导出Issue及其全部子Issue，每个Issue一个文档
+// 子Issue先于父Issue导出；文档中的子Issue清单嵌套展示整棵子树，并链接到各子Issue的导出文件
+// 参数:
+//   - ctx: 上下文，用于取消请求
+//   - rawURL: 根Issue的URL
+//   - name: 返回Issue导出文件的文件名，用作文档间的链接
+//   - emit: 每导出一个文档调用一次，返回错误时停止导出
+//
+// 返回值: (int, error) - 导出的文档数，获取、转换失败或emit返回错误时返回错误
+func (e *Exporter) ExportTree(ctx context.Context, rawURL string, name func(owner, repo string, number int) string, emit func(*ExportedDocument) error) (int, error) {
+	res, err := e.URLParser.Parse(rawURL)
+	if err != nil {
+		return 0, err
+	}
+	if res.Type != "issue" {
+		return 0, fmt.Errorf("recursive export requires an issue URL, got a %s", res.Type)
+	}
+
+	client, err := e.client(res.Host)
+	if err != nil {
+		return 0, err
+	}
+
+	tree := &treeExport{exporter: e, client: client, host: res.Host, name: name, emit: emit, visited: make(map[string]bool)}
+	if _, err := tree.visit(ctx, res.Owner, res.Repo, res.Number); err != nil {
+		return tree.count, err
+	}
+	return tree.count, nil
+}
+
+// treeExport 一次递归导出的状态
+type treeExport struct {
+	exporter *Exporter
+	client   source.Client
+	host     string
+	name     func(owner, repo string, number int) string
+	emit     func(*ExportedDocument) error
+	visited  map[string]bool
+	count    int
+}
+
+// visit 导出Issue及其子树，返回已填充子Issue的Issue
+// 任务列表可以互相跟踪形成环，已访问过的Issue只链接不再展开
+func (t *treeExport) visit(ctx context.Context, owner, repo string, number int) (*model.Issue, error) {
+	t.visited[referenceKey(&model.IssueReference{Repo: owner + "/" + repo, Number: number})] = true
+
+	res := &parser.ResourceURL{Type: "issue", Host: t.host, Owner: owner, Repo: repo, Number: number}
+	issue, err := t.client.GetIssue(ctx, owner, repo, number)
+	if err != nil {
+		return nil, err
+	}
+	comments, err := t.exporter.issueDetails(ctx, t.client, res, issue, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if parent := issue.Parent; parent != nil && t.visited[referenceKey(parent)] {
+		parentOwner, parentRepo, _ := strings.Cut(parent.Repo, "/")
+		parent.File = t.name(parentOwner, parentRepo, parent.Number)
+	}
+	for _, sub := range issue.SubIssues {
+		subOwner, subRepo, ok := strings.Cut(sub.Repo, "/")
+		if !ok {
+			continue
+		}
+		sub.File = t.name(subOwner, subRepo, sub.Number)
+		if t.visited[referenceKey(&sub.IssueReference)] {
+			continue
+		}
+
+		child, err := t.visit(ctx, subOwner, subRepo, sub.Number)
+		if err != nil {
+			return nil, fmt.Errorf("failed to export sub-issue %s#%d: %w", sub.Repo, sub.Number, err)
+		}
+		sub.Title, sub.State, sub.URL, sub.Children = child.Title, child.State, child.HTMLURL, child.SubIssues
+	}
+
+	doc, err := t.exporter.Parser.Parse(issue, comments)
+	if err != nil {
+		return nil, err
+	}
+	exported, err := t.exporter.exported(ctx, t.client, res, doc)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.emit(exported); err != nil {
+		return nil, err
+	}
+	t.count++
+	return issue, nil
+}
+
+// TreeOutputFileName 返回递归导出时文档的文件名
+// 与根Issue同仓库的文档为 123.md，其他仓库的文档为 owner_repo_123.md，所有文档位于同一目录
+// 参数:
+//   - rootOwner, rootRepo: 根Issue所在仓库
+//   - owner, repo, number: 文档对应的Issue
+//   - format: 输出格式 markdown、html 或 json
+//
+// 返回值: string - 文件名
+func TreeOutputFileName(rootOwner, rootRepo, owner, repo string, number int, format string) string {
+	name := OutputFileName(number, format)
+	if strings.EqualFold(owner, rootOwner) && strings.EqualFold(repo, rootRepo) {
+		return name
+	}
+	return owner + "_" + repo + "_" + name
+}
+
+// OutputFileName 返回批量导出时文档的文件名，如 123.md
+// 参数:
+//   - number: Issue或Pull Request编号
+//   - format: 输出格式 markdown、html 或 json
+//
+// 返回值: string - 文件名
+func OutputFileName(number int, format string) string {
+	ext := "md"
+	switch format {
+	case "html", "json":
+		ext = format
+	}
+	return fmt.Sprintf("%d.%s", number, ext)
+}
+
+// SearchOutputPath 返回搜索和导入时文档相对输出目录的路径，如 owner/repo/123.md
+// 搜索和导入的结果可能来自多个仓库，按仓库分目录避免编号冲突；Jira Issue位于项目键目录下，如 PROJ/12.md
+// 参数:
+//   - doc: 导出的文档
+//   - format: 输出格式 markdown、html 或 json
+//
+// 返回值: string - 相对路径
+func SearchOutputPath(doc *ExportedDocument, format string) string {
+	return filepath.Join(doc.Owner, doc.Repo, OutputFileName(doc.Number, format))
+}
+
+// WriteOutput 写出导出结果
+// 参数:
+//   - data: 导出内容
+//   - path: 输出文件路径，为空时写到标准输出
+//   - overwrite: 文件已存在时是否覆盖
+//
+// 返回值: error - 文件已存在且不允许覆盖或写入失败时返回错误
+func (c *CLI) WriteOutput(data []byte, path string, overwrite bool) error {
+	if path == "" {
+		_, err := c.output.Writer.Write(data)
+		return err
+	}
+
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if !overwrite {
+		flags |= os.O_EXCL
+	}
+	f, err := os.OpenFile(path, flags, 0o644)
+	if err != nil {
+		if errors.Is(err, os.ErrExist) {
+			return fmt.Errorf("output file %s already exists, use --overwrite to replace it", path)
+		}
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+	return f.Close()
+}
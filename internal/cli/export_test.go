@@ -0,0 +1,1068 @@
+package cli
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bigwhite/issue2md/internal/converter"
+	"github.com/bigwhite/issue2md/internal/gitea"
+	"github.com/bigwhite/issue2md/internal/github"
+	"github.com/bigwhite/issue2md/internal/gitlab"
+	"github.com/bigwhite/issue2md/internal/model"
+	"github.com/bigwhite/issue2md/internal/parser"
+	"github.com/bigwhite/issue2md/internal/source"
+)
+
+// stubClient 测试用的GitHub客户端，未实现的方法由内嵌接口兜底（调用会panic）
+type stubClient struct {
+	source.Client
+	timelineCalls int
+}
+
+func (s *stubClient) GetIssue(ctx context.Context, owner, repo string, number int) (*model.Issue, error) {
+	return &model.Issue{Number: number, Title: "Stub issue", State: "open", User: model.User{Login: "alice"}}, nil
+}
+
+func (s *stubClient) GetIssueComments(ctx context.Context, owner, repo string, number int) ([]*model.Comment, error) {
+	return []*model.Comment{{ID: 1, Body: "First!", User: model.User{Login: "bob"}}}, nil
+}
+
+func (s *stubClient) GetIssueTimeline(ctx context.Context, owner, repo string, number int) ([]*model.TimelineEvent, error) {
+	s.timelineCalls++
+	return []*model.TimelineEvent{{Event: model.EventLabeled, Actor: model.User{Login: "alice"}, Label: "bug", CreatedAt: time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC)}}, nil
+}
+
+func (s *stubClient) GetIssueResolution(ctx context.Context, owner, repo string, number int) (*model.Resolution, error) {
+	return &model.Resolution{ClosedBy: []*model.ClosingPullRequest{{Repo: owner + "/" + repo, Number: 9, Title: "Fix it", State: "merged"}}}, nil
+}
+
+func (s *stubClient) GetPullRequest(ctx context.Context, owner, repo string, number int) (*model.PullRequest, error) {
+	return &model.PullRequest{Issue: model.Issue{Number: number, Title: "Stub PR", State: "closed"}, Merged: true}, nil
+}
+
+func TestExporterExport(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		timeline bool
+		wantErr  bool
+		contains []string
+	}{
+		{
+			name:     "Issue with timeline",
+			url:      "https://github.com/owner/repo/issues/1",
+			timeline: true,
+			contains: []string{"# Stub issue - Open", "First!", "*@alice added label `bug`"},
+		},
+		{
+			name:     "Pull request",
+			url:      "https://github.com/owner/repo/pull/2",
+			contains: []string{"# Stub PR - Merged", "type: \"pr\""},
+		},
+		{
+			name:    "Invalid URL",
+			url:     "https://example.com/owner/repo/issues/1",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &stubClient{}
+			exporter := &Exporter{
+				Client:    client,
+				URLParser: parser.NewURLParser(),
+				Parser:    parser.NewParser(&parser.Options{IncludeComments: true, IncludeMetadata: true}),
+				Converter: converter.NewMarkdownConverter(nil),
+				Timeline:  tt.timeline,
+			}
+
+			data, err := exporter.Export(context.Background(), tt.url)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("Export() expected error, but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Export() unexpected error = %v", err)
+			}
+			for _, want := range tt.contains {
+				if !strings.Contains(string(data), want) {
+					t.Errorf("Export() content missing %q\n%s", want, data)
+				}
+			}
+			if tt.timeline != (client.timelineCalls == 1) {
+				t.Errorf("GetIssueTimeline() calls = %d with Timeline %v", client.timelineCalls, tt.timeline)
+			}
+		})
+	}
+}
+
+func (s *stubClient) ListIssues(ctx context.Context, owner, repo string, opts *model.IssueListOptions) ([]*model.Issue, error) {
+	issues := []*model.Issue{
+		{Number: 1, Title: "Listed issue", State: "open"},
+		{Number: 2, Title: "Listed PR", State: "open", IsPullRequest: true},
+	}
+	if opts != nil && opts.ExcludePullRequests {
+		issues = issues[:1]
+	}
+	return issues, nil
+}
+
+func TestExporterExportIssues(t *testing.T) {
+	tests := []struct {
+		name      string
+		url       string
+		opts      *model.IssueListOptions
+		failEmit  bool
+		wantTypes []string
+		wantErr   bool
+	}{
+		{
+			name:      "Issues and pull requests",
+			url:       "https://github.com/owner/repo",
+			wantTypes: []string{"1:issue", "2:pull"},
+		},
+		{
+			name:      "Pull requests excluded",
+			url:       "https://github.com/owner/repo/issues",
+			opts:      &model.IssueListOptions{ExcludePullRequests: true},
+			wantTypes: []string{"1:issue"},
+		},
+		{
+			name:    "Single issue URL",
+			url:     "https://github.com/owner/repo/issues/1",
+			wantErr: true,
+		},
+		{
+			name:     "Emit error stops export",
+			url:      "https://github.com/owner/repo",
+			failEmit: true,
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &stubClient{}
+			exporter := &Exporter{
+				Client:    client,
+				URLParser: parser.NewURLParser(),
+				Parser:    parser.NewParser(&parser.Options{IncludeComments: true, IncludeMetadata: true}),
+				Converter: converter.NewMarkdownConverter(nil),
+				Timeline:  true,
+			}
+
+			var got []string
+			n, err := exporter.ExportIssues(context.Background(), tt.url, tt.opts, func(doc *ExportedDocument) error {
+				if tt.failEmit {
+					return errors.New("disk full")
+				}
+				got = append(got, fmt.Sprintf("%d:%s", doc.Number, doc.Type))
+				if doc.Type == "issue" && !strings.Contains(string(doc.Data), "# Listed issue") {
+					t.Errorf("issue document missing title:\n%s", doc.Data)
+				}
+				return nil
+			})
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("ExportIssues() expected error, but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ExportIssues() unexpected error = %v", err)
+			}
+			if n != len(tt.wantTypes) || strings.Join(got, ",") != strings.Join(tt.wantTypes, ",") {
+				t.Errorf("ExportIssues() = %d %v, want %v", n, got, tt.wantTypes)
+			}
+			if client.timelineCalls != 1 {
+				t.Errorf("GetIssueTimeline() calls = %d, want 1", client.timelineCalls)
+			}
+		})
+	}
+}
+
+func (s *stubClient) SearchIssues(ctx context.Context, query string) ([]*model.Issue, error) {
+	return []*model.Issue{
+		{Number: 1, Title: "Listed issue", State: "closed", Repository: "org/x"},
+		{Number: 2, Title: "Listed PR", State: "closed", Repository: "org/y", IsPullRequest: true},
+	}, nil
+}
+
+func TestExporterExportSearch(t *testing.T) {
+	exporter := &Exporter{
+		Client:    &stubClient{},
+		URLParser: parser.NewURLParser(),
+		Parser:    parser.NewParser(&parser.Options{IncludeComments: true, IncludeMetadata: true}),
+		Converter: converter.NewMarkdownConverter(nil),
+	}
+
+	var got []string
+	n, err := exporter.ExportSearch(context.Background(), "", "is:closed label:incident", func(doc *ExportedDocument) error {
+		got = append(got, SearchOutputPath(doc, "markdown")+":"+doc.Type)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ExportSearch() unexpected error = %v", err)
+	}
+	want := []string{filepath.Join("org", "x", "1.md") + ":issue", filepath.Join("org", "y", "2.md") + ":pull"}
+	if n != 2 || strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("ExportSearch() = %d %v, want %v", n, got, want)
+	}
+
+	// 指定主机时搜索和导出都使用该主机的客户端
+	var hosts []string
+	exporter.ClientForHost = func(host string) (source.Client, error) {
+		hosts = append(hosts, host)
+		return &stubClient{}, nil
+	}
+	if n, err := exporter.ExportSearch(context.Background(), "git.example.com", "is:closed", func(doc *ExportedDocument) error { return nil }); err != nil || n != 2 {
+		t.Fatalf("ExportSearch(git.example.com) = %d, %v, want 2 documents", n, err)
+	}
+	if len(hosts) != 1 || hosts[0] != "git.example.com" {
+		t.Errorf("ClientForHost called with %v, want [git.example.com]", hosts)
+	}
+}
+
+func TestExporterExportImport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jira.xml")
+	export := `<rss version="0.92"><channel>
+	<item>
+		<link>https://jira.example.com/browse/PROJ-12</link>
+		<key>PROJ-12</key>
+		<summary>Crash on startup</summary>
+		<description>&lt;p&gt;Steps to reproduce&lt;/p&gt;</description>
+		<reporter username="alice">Alice</reporter>
+		<created>Mon, 5 Feb 2024 09:00:00 +0000</created>
+		<comments><comment id="1" author="bob" created="Mon, 5 Feb 2024 10:00:00 +0000">Reproduced</comment></comments>
+	</item>
+	<item><key>PROJ-13</key><summary>Dark mode</summary></item>
+</channel></rss>`
+	if err := os.WriteFile(path, []byte(export), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// 导入不访问API，不需要客户端
+	exporter := &Exporter{
+		URLParser: parser.NewURLParser(),
+		Parser:    parser.NewParser(&parser.Options{IncludeComments: true, IncludeMetadata: true}),
+		Converter: converter.NewMarkdownConverter(nil),
+	}
+
+	var got []string
+	var first string
+	n, err := exporter.ExportImport(context.Background(), path, func(doc *ExportedDocument) error {
+		if first == "" {
+			first = string(doc.Data)
+		}
+		got = append(got, SearchOutputPath(doc, "markdown"))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ExportImport() unexpected error = %v", err)
+	}
+	want := []string{filepath.Join("PROJ", "12.md"), filepath.Join("PROJ", "13.md")}
+	if n != 2 || strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("ExportImport() = %d %v, want %v", n, got, want)
+	}
+	for _, want := range []string{"Crash on startup", "<p>Steps to reproduce</p>", "@bob", "Reproduced"} {
+		if !strings.Contains(first, want) {
+			t.Errorf("ExportImport() content missing %q\n%s", want, first)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := exporter.ExportImport(ctx, path, func(*ExportedDocument) error { return nil }); !errors.Is(err, context.Canceled) {
+		t.Errorf("ExportImport() with canceled context error = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestExporterExportSnapshotRoundTrip(t *testing.T) {
+	p := parser.NewParser(&parser.Options{IncludeComments: true, IncludeMetadata: true})
+	issue := &model.Issue{
+		Number: 7, Title: "Timeout", Body: "Requests hang", State: "closed",
+		User: model.User{Login: "alice"}, HTMLURL: "https://github.com/org/api/issues/7",
+		Labels:    []model.Label{{Name: "bug"}},
+		Reactions: model.Reactions{TotalCount: 1, Heart: 1},
+		CreatedAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+	comments := []*model.Comment{{ID: 1, Body: "Seen it too", User: model.User{Login: "bob"}}}
+	pr := &model.PullRequest{
+		Issue:    model.Issue{Number: 8, Title: "Fix timeout", State: "closed", HTMLURL: "https://github.com/org/api/pull/8"},
+		Merged:   true,
+		Head:     model.Branch{Label: "alice:fix", Ref: "fix"},
+		Base:     model.Branch{Label: "org:main", Ref: "main"},
+		Comments: []*model.Comment{{ID: 2, Body: "Nit", Path: "client.go", Line: 3, User: model.User{Login: "bob"}}},
+	}
+
+	issueDoc, err := p.Parse(issue, comments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prDoc, err := p.ParsePullRequest(pr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// issue2md的JSON输出重新渲染后与直接导出的Markdown相同
+	for _, doc := range []*parser.MarkdownDocument{issueDoc, prDoc} {
+		input, err := converter.NewJSONConverter(nil).Convert(doc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := converter.NewMarkdownConverter(nil).Convert(doc)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		exporter := &Exporter{URLParser: parser.NewURLParser(), Parser: p, Converter: converter.NewMarkdownConverter(nil)}
+		got, err := exporter.ExportSnapshot(bytes.NewReader(input))
+		if err != nil {
+			t.Fatalf("ExportSnapshot(%s) unexpected error = %v", doc.Title, err)
+		}
+		if string(got) != string(want) {
+			t.Errorf("ExportSnapshot(%s) =\n%s\nwant\n%s", doc.Title, got, want)
+		}
+
+		// 再次输出JSON时数据保持不变
+		exporter.Converter = converter.NewJSONConverter(nil)
+		again, err := exporter.ExportSnapshot(bytes.NewReader(input))
+		if err != nil {
+			t.Fatalf("ExportSnapshot(%s) unexpected error = %v", doc.Title, err)
+		}
+		if string(again) != string(input) {
+			t.Errorf("ExportSnapshot(%s) JSON =\n%s\nwant\n%s", doc.Title, again, input)
+		}
+	}
+}
+
+func TestExporterExportImportPullRequest(t *testing.T) {
+	files := map[string]string{
+		"pull_requests_000001.json": `[{"url": "https://github.com/org/api/pull/8", "user": "https://github.com/carol",
+			"title": "Fix timeout", "body": "Fixes #7",
+			"head": {"ref": "fix-timeout", "user": "https://github.com/carol"}, "base": {"ref": "main", "user": "https://github.com/org"},
+			"merged_at": "2023-05-03T12:00:00Z", "closed_at": "2023-05-03T12:00:00Z", "created_at": "2023-05-03T09:00:00Z"}]`,
+		"issue_comments_000001.json": `[{"url": "https://github.com/org/api/pull/8#issuecomment-300",
+			"pull_request": "https://github.com/org/api/pull/8", "user": "https://github.com/bob", "body": "LGTM",
+			"created_at": "2023-05-03T10:00:00Z"}]`,
+		"pull_request_review_comments_000001.json": `[{"url": "https://github.com/org/api/pull/8/files#r400",
+			"pull_request": "https://github.com/org/api/pull/8", "user": "https://github.com/bob", "body": "Nit",
+			"path": "client.go", "created_at": "2023-05-03T09:30:00Z"}]`,
+	}
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for name, content := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "migration.tar")
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	exporter := &Exporter{
+		URLParser: parser.NewURLParser(),
+		Parser:    parser.NewParser(&parser.Options{IncludeComments: true, IncludeMetadata: true}),
+		Converter: converter.NewMarkdownConverter(nil),
+	}
+	var docs []*ExportedDocument
+	n, err := exporter.ExportImport(context.Background(), path, func(doc *ExportedDocument) error {
+		docs = append(docs, doc)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ExportImport() unexpected error = %v", err)
+	}
+	if n != 1 || len(docs) != 1 {
+		t.Fatalf("ExportImport() = %d documents, want 1", n)
+	}
+	doc := docs[0]
+	if doc.Owner != "org" || doc.Repo != "api" || doc.Number != 8 || doc.Type != "pull" {
+		t.Errorf("document = %s/%s#%d %s, want org/api#8 pull", doc.Owner, doc.Repo, doc.Number, doc.Type)
+	}
+	// 按Pull Request渲染：合并状态、分支和代码评审评论的位置
+	for _, want := range []string{"# Fix timeout - Merged", "`carol:fix-timeout` → `org:main`", "client.go", "Nit", "LGTM"} {
+		if !strings.Contains(string(doc.Data), want) {
+			t.Errorf("ExportImport() content missing %q\n%s", want, doc.Data)
+		}
+	}
+}
+
+func TestExporterExportSnapshot(t *testing.T) {
+	exporter := &Exporter{
+		URLParser: parser.NewURLParser(),
+		Parser:    parser.NewParser(&parser.Options{IncludeComments: true, IncludeMetadata: true}),
+		Converter: converter.NewMarkdownConverter(nil),
+	}
+
+	tests := []struct {
+		name     string
+		input    string
+		contains []string
+	}{
+		{
+			name: "gh issue view",
+			input: `{"number": 42, "title": "Crash", "body": "Boom", "state": "OPEN", "author": {"login": "alice"},
+				"url": "https://github.com/org/app/issues/42",
+				"comments": [{"author": {"login": "bob"}, "body": "Seen it too", "url": "https://github.com/org/app/issues/42#issuecomment-1"}]}`,
+			contains: []string{"# Crash - Open", "Boom", "@bob", "Seen it too"},
+		},
+		{
+			name: "gh pr view",
+			input: `{"number": 43, "title": "Fix crash", "state": "MERGED", "author": {"login": "bob"},
+				"headRefName": "fix", "baseRefName": "main", "url": "https://github.com/org/app/pull/43"}`,
+			contains: []string{"# Fix crash - Merged", "`org:fix` → `org:main`"},
+		},
+		{
+			name:     "issue2md JSON output",
+			input:    `{"title": "Crash", "content": "# Crash - Open\n\nRendered before\n"}`,
+			contains: []string{"Rendered before"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := exporter.ExportSnapshot(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("ExportSnapshot() unexpected error = %v", err)
+			}
+			for _, want := range tt.contains {
+				if !strings.Contains(string(data), want) {
+					t.Errorf("ExportSnapshot() content missing %q\n%s", want, data)
+				}
+			}
+		})
+	}
+}
+
+// slowClient 评论请求阻塞到上下文取消，时间线请求立即失败
+type slowClient struct {
+	stubClient
+}
+
+func (s *slowClient) GetIssueComments(ctx context.Context, owner, repo string, number int) ([]*model.Comment, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (s *slowClient) GetIssueTimeline(ctx context.Context, owner, repo string, number int) ([]*model.TimelineEvent, error) {
+	return nil, errTimeline
+}
+
+var errTimeline = errors.New("timeline unavailable")
+
+func TestExporterFetchesDetailsConcurrently(t *testing.T) {
+	exporter := &Exporter{
+		Client:    &slowClient{},
+		URLParser: parser.NewURLParser(),
+		Parser:    parser.NewParser(nil),
+		Converter: converter.NewMarkdownConverter(nil),
+		Timeline:  true,
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := exporter.Export(context.Background(), "https://github.com/owner/repo/issues/1")
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, errTimeline) {
+			t.Errorf("Export() error = %v, want %v", err, errTimeline)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Export() did not return, the failed timeline request did not cancel the comment request")
+	}
+}
+
+func (s *stubClient) GetEditHistory(ctx context.Context, owner, repo string, number int) (*model.EditHistory, error) {
+	return &model.EditHistory{
+		Body: []*model.Edit{{Body: "Draft"}, {Editor: model.User{Login: "alice"}, Body: "Final"}},
+		Comments: map[int64][]*model.Edit{
+			1: {{Body: "First"}, {Editor: model.User{Login: "bob"}, Body: "First!"}},
+		},
+	}, nil
+}
+
+func TestExporterEditHistory(t *testing.T) {
+	bodyDiff := "**@alice edited**\n\n```diff\n-Draft\n+Final\n```"
+	tests := []struct {
+		name     string
+		url      string
+		contains []string
+	}{
+		{
+			name:     "Issue body and comments",
+			url:      "https://github.com/owner/repo/issues/1",
+			contains: []string{"edited: true", bodyDiff, "**@bob edited**\n\n```diff\n-First\n+First!\n```"},
+		},
+		{
+			name:     "Pull request body",
+			url:      "https://github.com/owner/repo/pull/2",
+			contains: []string{"edited: true", bodyDiff},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exporter := &Exporter{
+				Client:      &stubClient{},
+				URLParser:   parser.NewURLParser(),
+				Parser:      parser.NewParser(&parser.Options{IncludeComments: true, IncludeMetadata: true}),
+				Converter:   converter.NewMarkdownConverter(nil),
+				EditHistory: true,
+			}
+
+			data, err := exporter.Export(context.Background(), tt.url)
+			if err != nil {
+				t.Fatalf("Export() unexpected error = %v", err)
+			}
+			for _, want := range tt.contains {
+				if !strings.Contains(string(data), want) {
+					t.Errorf("Export() content missing %q\n%s", want, data)
+				}
+			}
+		})
+	}
+}
+
+func (s *stubClient) GetMinimizedComments(ctx context.Context, owner, repo string, number int) (map[int64]string, error) {
+	return map[int64]string{1: "spam"}, nil
+}
+
+func TestExporterMinimized(t *testing.T) {
+	tests := []struct {
+		name      string
+		minimized bool
+		policy    string
+		want      bool
+		wantBody  bool
+	}{
+		{name: "Marked", minimized: true, want: true, wantBody: true},
+		{name: "Not fetched", minimized: false, want: false, wantBody: true},
+		{name: "Dropped", minimized: true, policy: parser.MinimizedDrop, want: false, wantBody: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exporter := &Exporter{
+				Client:    &stubClient{},
+				URLParser: parser.NewURLParser(),
+				Parser:    parser.NewParser(&parser.Options{IncludeComments: true, MinimizedComments: tt.policy}),
+				Converter: converter.NewMarkdownConverter(nil),
+				Minimized: tt.minimized,
+			}
+
+			data, err := exporter.Export(context.Background(), "https://github.com/owner/repo/issues/1")
+			if err != nil {
+				t.Fatalf("Export() unexpected error = %v", err)
+			}
+			if got := strings.Contains(string(data), "[Hidden: spam]"); got != tt.want {
+				t.Errorf("Export() hidden marker = %v, want %v\n%s", got, tt.want, data)
+			}
+			if got := strings.Contains(string(data), "First!"); got != tt.wantBody {
+				t.Errorf("Export() minimized comment body = %v, want %v\n%s", got, tt.wantBody, data)
+			}
+		})
+	}
+}
+
+func (s *stubClient) GetProjectItems(ctx context.Context, owner, repo string, number int) ([]*model.ProjectItem, error) {
+	return []*model.ProjectItem{{Project: "Roadmap", Number: 3, Fields: []*model.ProjectField{{Name: "Status", Value: "Todo"}}}}, nil
+}
+
+func TestExporterProjects(t *testing.T) {
+	tests := []struct {
+		name      string
+		url       string
+		converter converter.Converter
+		want      string
+	}{
+		{
+			name:      "Issue frontmatter",
+			url:       "https://github.com/owner/repo/issues/1",
+			converter: converter.NewMarkdownConverter(nil),
+			want:      "projects:\n  Roadmap:\n    Status: \"Todo\"\n",
+		},
+		{
+			name:      "Pull request JSON",
+			url:       "https://github.com/owner/repo/pull/2",
+			converter: converter.NewJSONConverter(nil),
+			want:      "\"projects\": {\n    \"Roadmap\": {\n      \"Status\": \"Todo\"\n    }\n  }",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exporter := &Exporter{
+				Client:    &stubClient{},
+				URLParser: parser.NewURLParser(),
+				Parser:    parser.NewParser(&parser.Options{IncludeMetadata: true}),
+				Converter: tt.converter,
+				Projects:  true,
+			}
+
+			data, err := exporter.Export(context.Background(), tt.url)
+			if err != nil {
+				t.Fatalf("Export() unexpected error = %v", err)
+			}
+			if !strings.Contains(string(data), tt.want) {
+				t.Errorf("Export() content missing %q\n%s", tt.want, data)
+			}
+		})
+	}
+}
+
+// noProjectScopeClient 令牌缺少read:project权限的客户端
+type noProjectScopeClient struct {
+	stubClient
+}
+
+func (c *noProjectScopeClient) GetProjectItems(ctx context.Context, owner, repo string, number int) ([]*model.ProjectItem, error) {
+	return nil, fmt.Errorf("failed to get project items: %w", &github.GraphQLError{Type: "INSUFFICIENT_SCOPES", Messages: []string{"read:project"}})
+}
+
+func TestExporterProjectsPermission(t *testing.T) {
+	const url = "https://github.com/owner/repo/issues/1"
+	exporter := &Exporter{
+		Client:    &noProjectScopeClient{},
+		URLParser: parser.NewURLParser(),
+		Parser:    parser.NewParser(&parser.Options{IncludeMetadata: true}),
+		Converter: converter.NewMarkdownConverter(nil),
+		Projects:  true,
+	}
+
+	if _, err := exporter.Export(context.Background(), url); !github.IsPermissionError(err) {
+		t.Fatalf("Export() without OnProjectsError error = %v, want permission error", err)
+	}
+
+	var warned []string
+	exporter.OnProjectsError = func(url string, err error) {
+		warned = append(warned, url)
+	}
+	data, err := exporter.Export(context.Background(), url)
+	if err != nil {
+		t.Fatalf("Export() unexpected error = %v", err)
+	}
+	if strings.Contains(string(data), "projects:") {
+		t.Errorf("Export() content has projects\n%s", data)
+	}
+	if len(warned) != 1 || warned[0] != url {
+		t.Errorf("OnProjectsError called with %v, want [%s]", warned, url)
+	}
+}
+
+// closedClient 返回已关闭Issue的客户端
+type closedClient struct {
+	stubClient
+}
+
+func (c *closedClient) GetIssue(ctx context.Context, owner, repo string, number int) (*model.Issue, error) {
+	closedAt := time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC)
+	return &model.Issue{Number: number, Title: "Fixed issue", State: "closed", ClosedAt: &closedAt}, nil
+}
+
+func TestExporterResolution(t *testing.T) {
+	tests := []struct {
+		name   string
+		client source.Client
+		want   bool
+	}{
+		{name: "Closed issue", client: &closedClient{}, want: true},
+		{name: "Open issue", client: &stubClient{}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exporter := &Exporter{
+				Client:     tt.client,
+				URLParser:  parser.NewURLParser(),
+				Parser:     parser.NewParser(&parser.Options{IncludeMetadata: true}),
+				Converter:  converter.NewMarkdownConverter(nil),
+				Resolution: true,
+			}
+
+			data, err := exporter.Export(context.Background(), "https://github.com/owner/repo/issues/1")
+			if err != nil {
+				t.Fatalf("Export() unexpected error = %v", err)
+			}
+			got := strings.Contains(string(data), "## Resolution\n\nClosed by:\n\n- owner/repo#9 Fix it · Merged\n")
+			if got != tt.want {
+				t.Errorf("Export() resolution rendered = %v, want %v\n%s", got, tt.want, data)
+			}
+		})
+	}
+}
+
+// treeClient 测试递归导出的客户端
+// Issue 1的子Issue为2和other/repo#3，Issue 2的子Issue为4，Issue 4的正文又跟踪了Issue 1
+type treeClient struct {
+	stubClient
+}
+
+func (c *treeClient) GetIssue(ctx context.Context, owner, repo string, number int) (*model.Issue, error) {
+	issue := &model.Issue{Number: number, Title: fmt.Sprintf("Issue %d", number), State: "open", HTMLURL: fmt.Sprintf("https://github.com/%s/%s/issues/%d", owner, repo, number)}
+	switch number {
+	case 2:
+		issue.State = "closed"
+	case 4:
+		issue.Body = "- [ ] #1"
+	}
+	return issue, nil
+}
+
+func (c *treeClient) GetSubIssues(ctx context.Context, owner, repo string, number int) ([]*model.SubIssue, error) {
+	switch number {
+	case 1:
+		return []*model.SubIssue{
+			{IssueReference: model.IssueReference{Repo: "owner/repo", Number: 2}},
+			{IssueReference: model.IssueReference{Repo: "other/repo", Number: 3}},
+		}, nil
+	case 2:
+		return []*model.SubIssue{{IssueReference: model.IssueReference{Repo: "owner/repo", Number: 4}}}, nil
+	}
+	return nil, nil
+}
+
+func (c *treeClient) GetParentIssue(ctx context.Context, owner, repo string, number int) (*model.IssueReference, error) {
+	switch number {
+	case 2, 3:
+		return &model.IssueReference{Repo: "owner/repo", Number: 1, Title: "Issue 1"}, nil
+	case 4:
+		return &model.IssueReference{Repo: "owner/repo", Number: 2, Title: "Issue 2"}, nil
+	}
+	return nil, nil
+}
+
+func TestExporterExportTree(t *testing.T) {
+	exporter := &Exporter{
+		Client:    &treeClient{},
+		URLParser: parser.NewURLParser(),
+		Parser:    parser.NewParser(&parser.Options{IncludeMetadata: true}),
+		Converter: converter.NewMarkdownConverter(nil),
+	}
+	name := func(owner, repo string, number int) string {
+		return TreeOutputFileName("owner", "repo", owner, repo, number, "markdown")
+	}
+
+	docs := make(map[string]string)
+	var order []string
+	n, err := exporter.ExportTree(context.Background(), "https://github.com/owner/repo/issues/1", name, func(doc *ExportedDocument) error {
+		file := name(doc.Owner, doc.Repo, doc.Number)
+		docs[file] = string(doc.Data)
+		order = append(order, file)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ExportTree() unexpected error = %v", err)
+	}
+	if want := "4.md,2.md,other_repo_3.md,1.md"; n != 4 || strings.Join(order, ",") != want {
+		t.Errorf("ExportTree() = %d %v, want %s", n, order, want)
+	}
+
+	epic := docs["1.md"]
+	wantTree := "## Sub-issues (1/2)\n\n" +
+		"- [x] [owner/repo#2](2.md) Issue 2 · Closed\n" +
+		"  - [ ] [owner/repo#4](4.md) Issue 4 · Open\n" +
+		"    - [ ] [owner/repo#1](1.md)\n" +
+		"- [ ] [other/repo#3](other_repo_3.md) Issue 3 · Open\n"
+	if !strings.Contains(epic, wantTree) {
+		t.Errorf("epic document missing tree %q\n%s", wantTree, epic)
+	}
+	if want := "**父Issue:** [owner/repo#2](2.md) Issue 2"; !strings.Contains(docs["4.md"], want) {
+		t.Errorf("child document missing parent link %q\n%s", want, docs["4.md"])
+	}
+
+	if _, err := exporter.ExportTree(context.Background(), "https://github.com/owner/repo/pull/1", name, nil); err == nil {
+		t.Error("ExportTree() expected error for pull request URL, but got nil")
+	}
+}
+
+func TestOutputFileName(t *testing.T) {
+	tests := map[string]string{"markdown": "7.md", "": "7.md", "html": "7.html", "json": "7.json"}
+	for format, want := range tests {
+		if got := OutputFileName(7, format); got != want {
+			t.Errorf("OutputFileName(7, %q) = %q, want %q", format, got, want)
+		}
+	}
+}
+
+func TestExporterClientForHost(t *testing.T) {
+	public, enterprise := &stubClient{}, &stubClient{}
+	var hosts []string
+	exporter := &Exporter{
+		Client: public,
+		ClientForHost: func(host string) (source.Client, error) {
+			hosts = append(hosts, host)
+			if host != "git.example.com" {
+				return nil, errors.New("unknown host")
+			}
+			return enterprise, nil
+		},
+		URLParser: parser.NewURLParser("git.example.com", "other.example.com"),
+		Parser:    parser.NewParser(&parser.Options{IncludeComments: true, IncludeMetadata: true}),
+		Converter: converter.NewMarkdownConverter(nil),
+		Timeline:  true,
+	}
+
+	if _, err := exporter.Export(context.Background(), "https://git.example.com/owner/repo/issues/1"); err != nil {
+		t.Fatalf("Export() unexpected error: %v", err)
+	}
+	if _, err := exporter.Export(context.Background(), "https://github.com/owner/repo/issues/1"); err != nil {
+		t.Fatalf("Export() unexpected error: %v", err)
+	}
+	if enterprise.timelineCalls != 1 || public.timelineCalls != 1 {
+		t.Errorf("timeline calls = enterprise %d, public %d, want 1 each", enterprise.timelineCalls, public.timelineCalls)
+	}
+
+	if _, err := exporter.Export(context.Background(), "https://other.example.com/owner/repo/issues/1"); err == nil {
+		t.Error("Export() expected error for host without client, but got nil")
+	}
+	if len(hosts) != 2 || hosts[0] != "git.example.com" || hosts[1] != "other.example.com" {
+		t.Errorf("ClientForHost called with %v", hosts)
+	}
+}
+
+// epicClient 支持GitLab Epic的客户端
+type epicClient struct {
+	stubClient
+}
+
+func (c *epicClient) GetEpic(ctx context.Context, group string, number int) (*model.Issue, []*model.Comment, error) {
+	epic := &model.Issue{Number: number, Title: "Roadmap", State: "open", Repository: group}
+	epic.SubIssues = []*model.SubIssue{{IssueReference: model.IssueReference{Type: "issue", Repo: group + "/app", Number: 12, Title: "Crash", State: "closed"}}}
+	return epic, []*model.Comment{{ID: 1, Body: "Kick-off", User: model.User{Login: "bob"}}}, nil
+}
+
+func TestExporterEpic(t *testing.T) {
+	gitlabHost := &source.Provider{Name: "gitlab", Hosts: []string{"gitlab.com"}, URLParser: gitlab.NewURLParser()}
+	githubHost := &source.Provider{Name: "github", Hosts: []string{"github.com"}, URLParser: parser.NewURLParser()}
+
+	tests := []struct {
+		name     string
+		client   source.Client
+		url      string
+		wantErr  error
+		contains []string
+	}{
+		{
+			name:     "GitLab epic",
+			client:   &epicClient{},
+			url:      "https://gitlab.com/groups/group/-/epics/3",
+			contains: []string{"# Roadmap - Open", "Kick-off", "group/app#12"},
+		},
+		{
+			name:     "GitLab issue",
+			client:   &epicClient{},
+			url:      "https://gitlab.com/group/app/-/issues/4",
+			contains: []string{"# Stub issue - Open", "First!"},
+		},
+		{
+			name:    "Client without epics",
+			client:  &stubClient{},
+			url:     "https://gitlab.com/groups/group/-/epics/3",
+			wantErr: source.ErrUnsupported,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exporter := &Exporter{
+				Client:    tt.client,
+				URLParser: source.NewRegistry(githubHost, gitlabHost),
+				Parser:    parser.NewParser(&parser.Options{IncludeComments: true, IncludeMetadata: true}),
+				Converter: converter.NewMarkdownConverter(nil),
+			}
+
+			data, err := exporter.Export(context.Background(), tt.url)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Export() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Export() unexpected error = %v", err)
+			}
+			for _, want := range tt.contains {
+				if !strings.Contains(string(data), want) {
+					t.Errorf("Export() content missing %q\n%s", want, data)
+				}
+			}
+		})
+	}
+}
+
+// coreClient 只实现source.Client核心操作的客户端，没有任何可选接口
+type coreClient struct {
+	source.Client
+}
+
+func (c *coreClient) GetIssue(ctx context.Context, owner, repo string, number int) (*model.Issue, error) {
+	return &model.Issue{Number: number, Title: "Core issue", State: "closed", ClosedAt: &time.Time{}, Body: "- [ ] #7"}, nil
+}
+
+func (c *coreClient) GetIssueComments(ctx context.Context, owner, repo string, number int) ([]*model.Comment, error) {
+	return []*model.Comment{{ID: 1, Body: "First! https://github.com/user-attachments/assets/1a2b", User: model.User{Login: "bob"}}}, nil
+}
+
+func TestExporterSkipsUnsupportedCapabilities(t *testing.T) {
+	exporter := &Exporter{
+		Client:         &coreClient{},
+		URLParser:      parser.NewURLParser(),
+		Parser:         parser.NewParser(&parser.Options{IncludeComments: true}),
+		Converter:      converter.NewMarkdownConverter(nil),
+		Timeline:       true,
+		SubIssues:      true,
+		Resolution:     true,
+		EditHistory:    true,
+		Minimized:      true,
+		Projects:       true,
+		DownloadAssets: true,
+	}
+
+	exported, err := exporter.ExportDocument(context.Background(), "https://github.com/owner/repo/issues/1")
+	if err != nil {
+		t.Fatalf("ExportDocument() unexpected error = %v", err)
+	}
+	for _, want := range []string{"# Core issue - Closed", "First! https://github.com/user-attachments/assets/1a2b", "owner/repo#7"} {
+		if !strings.Contains(string(exported.Data), want) {
+			t.Errorf("ExportDocument() content missing %q\n%s", want, exported.Data)
+		}
+	}
+	if len(exported.Assets) != 0 {
+		t.Errorf("ExportDocument() assets = %v, want none", exported.Assets)
+	}
+
+	if _, err := exporter.Export(context.Background(), "https://github.com/owner/repo/discussions/2"); !errors.Is(err, source.ErrUnsupported) {
+		t.Errorf("Export() discussion error = %v, want %v", err, source.ErrUnsupported)
+	}
+	emit := func(*ExportedDocument) error { return nil }
+	if _, err := exporter.ExportIssues(context.Background(), "https://github.com/owner/repo", nil, emit); !errors.Is(err, source.ErrUnsupported) {
+		t.Errorf("ExportIssues() error = %v, want %v", err, source.ErrUnsupported)
+	}
+	if _, err := exporter.ExportSearch(context.Background(), "", "is:open", emit); !errors.Is(err, source.ErrUnsupported) {
+		t.Errorf("ExportSearch() error = %v, want %v", err, source.ErrUnsupported)
+	}
+}
+
+func TestSourceCapabilities(t *testing.T) {
+	gitLabClient, err := gitlab.NewClient(nil, "https://gitlab.com", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	giteaClient, err := gitea.NewClient(nil, "https://git.example.com", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		client source.Client
+		github bool
+		gitlab bool
+	}{
+		{name: "GitHub", client: github.NewClient(""), github: true},
+		{name: "GitLab", client: gitLabClient, gitlab: true},
+		{name: "Gitea", client: giteaClient},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := tt.client
+			checks := map[string][2]bool{}
+			_, ok := c.(source.TimelineClient)
+			checks["timeline"] = [2]bool{ok, tt.github}
+			_, ok = c.(source.SubIssueClient)
+			checks["sub-issues"] = [2]bool{ok, tt.github}
+			_, ok = c.(source.ResolutionClient)
+			checks["resolution"] = [2]bool{ok, tt.github}
+			_, ok = c.(source.ProjectClient)
+			checks["projects"] = [2]bool{ok, tt.github}
+			_, ok = c.(source.EditHistoryClient)
+			checks["edit history"] = [2]bool{ok, tt.github}
+			_, ok = c.(source.MinimizedClient)
+			checks["minimized"] = [2]bool{ok, tt.github}
+			_, ok = c.(source.DiscussionClient)
+			checks["discussions"] = [2]bool{ok, tt.github}
+			_, ok = c.(source.ListClient)
+			checks["listing"] = [2]bool{ok, tt.github}
+			_, ok = c.(source.SearchClient)
+			checks["search"] = [2]bool{ok, tt.github}
+			_, ok = c.(source.EpicClient)
+			checks["epics"] = [2]bool{ok, tt.gitlab}
+			_, ok = c.(source.AssetClient)
+			checks["assets"] = [2]bool{ok, true}
+
+			for name, check := range checks {
+				if check[0] != check[1] {
+					t.Errorf("supports %s = %v, want %v", name, check[0], check[1])
+				}
+			}
+		})
+	}
+}
+
+func TestWriteOutput(t *testing.T) {
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "existing.md")
+	if err := os.WriteFile(existing, []byte("old"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		path      string
+		overwrite bool
+		wantErr   bool
+		wantFile  string
+	}{
+		{name: "Stdout"},
+		{name: "New file", path: filepath.Join(dir, "new.md"), wantFile: "new"},
+		{name: "Existing file without overwrite", path: existing, wantErr: true, wantFile: "old"},
+		{name: "Existing file with overwrite", path: existing, overwrite: true, wantFile: "new"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			app := NewCLI("issue2md", nil)
+			app.output.Writer = &stdout
+
+			err := app.WriteOutput([]byte("new"), tt.path, tt.overwrite)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("WriteOutput() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.path == "" {
+				if stdout.String() != "new" {
+					t.Errorf("stdout = %q, want new", stdout.String())
+				}
+				return
+			}
+			got, _ := os.ReadFile(tt.path)
+			if string(got) != tt.wantFile {
+				t.Errorf("file content = %q, want %q", got, tt.wantFile)
+			}
+		})
+	}
+}
@@ -0,0 +1,392 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// gitCredentialTimeout git credential fill的最长执行时间
+const gitCredentialTimeout = 10 * time.Second
+
+// Credential 查找到的令牌及其来源
+type Credential struct {
+	Token  string
+	Source string // 来源描述，如 "--token flag"、"GITHUB_TOKEN"、"gh hosts.yml"
+}
+
+// CredentialProvider 令牌来源
+type CredentialProvider interface {
+	// Lookup 返回访问指定主机的令牌，未找到时返回 (nil, nil)
+	// 只有来源被显式配置却无法读取时才返回错误
+	Lookup(host string) (*Credential, error)
+}
+
+// CredentialChain 按顺序尝试的令牌来源链
+type CredentialChain []CredentialProvider
+
+// Lookup 依次尝试各来源，返回第一个找到的令牌
+// 参数:
+//   - host: 目标主机名，如 github.com
+//
+// 返回值: (*Credential, error) - 找到的令牌，所有来源都未找到时返回 (nil, nil)
+func (c CredentialChain) Lookup(host string) (*Credential, error) {
+	for _, provider := range c {
+		cred, err := provider.Lookup(host)
+		if err != nil {
+			return nil, err
+		}
+		if cred != nil && cred.Token != "" {
+			return cred, nil
+		}
+	}
+	return nil, nil
+}
+
+// DefaultCredentialChain 返回默认的令牌来源链
+// 依次为环境变量、令牌文件、gh CLI的hosts.yml、~/.netrc和git credential fill
+// 参数:
+//   - tokenFile: 令牌文件路径，为空时跳过
+//
+// 返回值: CredentialChain - 来源链
+func DefaultCredentialChain(tokenFile string) CredentialChain {
+	return CredentialChain{
+		EnvCredentials{},
+		TokenFileCredentials{Path: tokenFile},
+		GHHostsCredentials{},
+		NetrcCredentials{},
+		GitCredentials{},
+	}
+}
+
+// GitLabCredentialChain 返回GitLab主机的令牌来源链
+// 依次为GITLAB_TOKEN环境变量、令牌文件、~/.netrc和git credential fill；GitHub专用的来源不参与查找
+// 参数:
+//   - tokenFile: 令牌文件路径，为空时跳过
+//
+// 返回值: CredentialChain - 来源链
+func GitLabCredentialChain(tokenFile string) CredentialChain {
+	return CredentialChain{
+		NamedEnvCredentials{Keys: []string{"GITLAB_TOKEN"}},
+		TokenFileCredentials{Path: tokenFile},
+		NetrcCredentials{},
+		GitCredentials{},
+	}
+}
+
+// GiteaCredentialChain 返回Gitea/Forgejo主机的令牌来源链
+// 依次为FORGEJO_TOKEN或GITEA_TOKEN环境变量、令牌文件、~/.netrc和git credential fill；GitHub专用的来源不参与查找
+// 参数:
+//   - tokenFile: 令牌文件路径，为空时跳过
+//
+// 返回值: CredentialChain - 来源链
+func GiteaCredentialChain(tokenFile string) CredentialChain {
+	return CredentialChain{
+		NamedEnvCredentials{Keys: []string{"FORGEJO_TOKEN", "GITEA_TOKEN"}},
+		TokenFileCredentials{Path: tokenFile},
+		NetrcCredentials{},
+		GitCredentials{},
+	}
+}
+
+// StaticCredentials 固定的令牌，用于--token参数，适用于所有主机
+type StaticCredentials struct {
+	Token  string
+	Source string
+}
+
+// Lookup 实现CredentialProvider接口
+func (s StaticCredentials) Lookup(host string) (*Credential, error) {
+	if s.Token == "" {
+		return nil, nil
+	}
+	return &Credential{Token: s.Token, Source: s.Source}, nil
+}
+
+// EnvCredentials 从环境变量读取令牌
+// github.com使用GITHUB_TOKEN或GH_TOKEN，其他主机使用GH_ENTERPRISE_TOKEN或GITHUB_ENTERPRISE_TOKEN
+type EnvCredentials struct{}
+
+// Lookup 实现CredentialProvider接口
+func (EnvCredentials) Lookup(host string) (*Credential, error) {
+	keys := []string{"GITHUB_TOKEN", "GH_TOKEN"}
+	if !isPublicHost(host) {
+		keys = []string{"GH_ENTERPRISE_TOKEN", "GITHUB_ENTERPRISE_TOKEN"}
+	}
+	for _, key := range keys {
+		if token := os.Getenv(key); token != "" {
+			return &Credential{Token: token, Source: key}, nil
+		}
+	}
+	return nil, nil
+}
+
+// NamedEnvCredentials 从指定的环境变量读取令牌，适用于所有主机
+type NamedEnvCredentials struct {
+	// Keys 依次尝试的环境变量名
+	Keys []string
+}
+
+// Lookup 实现CredentialProvider接口
+func (n NamedEnvCredentials) Lookup(host string) (*Credential, error) {
+	for _, key := range n.Keys {
+		if token := os.Getenv(key); token != "" {
+			return &Credential{Token: token, Source: key}, nil
+		}
+	}
+	return nil, nil
+}
+
+// TokenFileCredentials 从文件读取令牌，文件内容首尾空白会被忽略，适用于所有主机
+type TokenFileCredentials struct {
+	Path string
+}
+
+// Lookup 实现CredentialProvider接口
+func (f TokenFileCredentials) Lookup(host string) (*Credential, error) {
+	if f.Path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(f.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token file: %w", err)
+	}
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return nil, fmt.Errorf("token file %s is empty", f.Path)
+	}
+	return &Credential{Token: token, Source: "token file " + f.Path}, nil
+}
+
+// GHHostsCredentials 读取gh CLI保存在hosts.yml中的令牌
+// 令牌保存在系统钥匙串中时hosts.yml不含令牌，此时视为未找到
+type GHHostsCredentials struct {
+	// Path hosts.yml路径，为空时按gh的规则定位
+	Path string
+}
+
+// Lookup 实现CredentialProvider接口
+func (g GHHostsCredentials) Lookup(host string) (*Credential, error) {
+	path := g.Path
+	if path == "" {
+		path = ghHostsPath()
+	}
+	if path == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil
+	}
+	if token := parseGHHosts(data, host); token != "" {
+		return &Credential{Token: token, Source: "gh " + path}, nil
+	}
+	return nil, nil
+}
+
+// ghHostsPath 返回gh CLI的hosts.yml路径
+func ghHostsPath() string {
+	if dir := os.Getenv("GH_CONFIG_DIR"); dir != "" {
+		return filepath.Join(dir, "hosts.yml")
+	}
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "gh", "hosts.yml")
+	}
+	if runtime.GOOS == "windows" {
+		if dir := os.Getenv("AppData"); dir != "" {
+			return filepath.Join(dir, "GitHub CLI", "hosts.yml")
+		}
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "gh", "hosts.yml")
+}
+
+// parseGHHosts 从hosts.yml中取出主机的oauth_token
+// hosts.yml的结构固定为以主机名为顶层键的简单映射，这里只做逐行解析，不引入YAML依赖
+func parseGHHosts(data []byte, host string) string {
+	inHost := false
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		if line[0] != ' ' && line[0] != '\t' {
+			key := strings.TrimSuffix(trimmed, ":")
+			inHost = strings.EqualFold(unquoteYAML(key), host)
+			continue
+		}
+		if !inHost {
+			continue
+		}
+
+		if key, value, ok := strings.Cut(trimmed, ":"); ok && strings.TrimSpace(key) == "oauth_token" {
+			if token := unquoteYAML(strings.TrimSpace(value)); token != "" {
+				return token
+			}
+		}
+	}
+	return ""
+}
+
+// unquoteYAML 去掉YAML标量两侧的引号
+func unquoteYAML(value string) string {
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
+
+// NetrcCredentials 读取.netrc中主机对应的password作为令牌
+// github.com同时匹配api.github.com条目
+type NetrcCredentials struct {
+	// Path .netrc路径，为空时使用NETRC环境变量或主目录下的.netrc（Windows为_netrc）
+	Path string
+}
+
+// Lookup 实现CredentialProvider接口
+func (n NetrcCredentials) Lookup(host string) (*Credential, error) {
+	path := n.Path
+	if path == "" {
+		path = netrcPath()
+	}
+	if path == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil
+	}
+
+	machines := []string{host}
+	if isPublicHost(host) {
+		machines = append(machines, "api.github.com")
+	}
+	if token := parseNetrc(data, machines...); token != "" {
+		return &Credential{Token: token, Source: path}, nil
+	}
+	return nil, nil
+}
+
+// netrcPath 返回.netrc路径
+func netrcPath() string {
+	if path := os.Getenv("NETRC"); path != "" {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	if runtime.GOOS == "windows" {
+		return filepath.Join(home, "_netrc")
+	}
+	return filepath.Join(home, ".netrc")
+}
+
+// parseNetrc 返回.netrc中第一个匹配machines的条目的password，没有匹配条目时使用default条目
+func parseNetrc(data []byte, machines ...string) string {
+	fields := strings.Fields(string(data))
+	var current, fallback string
+	var matched, inDefault bool
+	for i := 0; i < len(fields); i++ {
+		switch fields[i] {
+		case "machine":
+			if matched {
+				return current
+			}
+			inDefault, current = false, ""
+			if i+1 < len(fields) {
+				i++
+				matched = false
+				for _, machine := range machines {
+					matched = matched || strings.EqualFold(fields[i], machine)
+				}
+			}
+		case "default":
+			if matched {
+				return current
+			}
+			matched, inDefault, current = false, true, ""
+		case "macdef":
+			// 宏定义到空行为止，Fields无法区分，之后的内容不再可靠
+			if matched {
+				return current
+			}
+			return fallback
+		case "password":
+			if i+1 < len(fields) {
+				i++
+				if matched {
+					current = fields[i]
+				} else if inDefault {
+					fallback = fields[i]
+				}
+			}
+		case "login", "account":
+			i++
+		}
+	}
+	if matched && current != "" {
+		return current
+	}
+	return fallback
+}
+
+// GitCredentials 通过git credential fill向git配置的凭据助手查询令牌
+// 查询时禁止交互式提示，git不可用或没有保存的凭据时视为未找到
+type GitCredentials struct {
+	// run 执行git credential fill，测试时可替换
+	run func(ctx context.Context, input string) ([]byte, error)
+}
+
+// Lookup 实现CredentialProvider接口
+func (g GitCredentials) Lookup(host string) (*Credential, error) {
+	run := g.run
+	if run == nil {
+		run = runGitCredentialFill
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), gitCredentialTimeout)
+	defer cancel()
+
+	out, err := run(ctx, fmt.Sprintf("protocol=https\nhost=%s\n\n", host))
+	if err != nil {
+		return nil, nil
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		if password := strings.TrimPrefix(scanner.Text(), "password="); password != scanner.Text() && password != "" {
+			return &Credential{Token: password, Source: "git credential helper"}, nil
+		}
+	}
+	return nil, nil
+}
+
+// runGitCredentialFill 执行git credential fill
+// 凭据助手没有保存凭据时git会提示输入，这里关闭终端提示并让askpass返回空值，避免阻塞或弹窗
+func runGitCredentialFill(ctx context.Context, input string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "git", "credential", "fill")
+	cmd.Stdin = strings.NewReader(input)
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0", "GIT_ASKPASS=true", "SSH_ASKPASS=true", "GCM_INTERACTIVE=never")
+	return cmd.Output()
+}
+
+// isPublicHost 判断主机是否为公共GitHub
+func isPublicHost(host string) bool {
+	return host == "" || strings.EqualFold(host, "github.com")
+}
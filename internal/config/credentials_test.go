@@ -0,0 +1,211 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// setEnv 设置环境变量，并在测试结束时恢复原值
+func setEnv(t *testing.T, values map[string]string) {
+	for key, value := range values {
+		key := key
+		original, ok := os.LookupEnv(key)
+		t.Cleanup(func() {
+			if ok {
+				os.Setenv(key, original)
+			} else {
+				os.Unsetenv(key)
+			}
+		})
+		if value == "" {
+			os.Unsetenv(key)
+		} else {
+			os.Setenv(key, value)
+		}
+	}
+}
+
+// writeFile 在临时目录中写入文件并返回路径
+func writeFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestCredentialChainOrder(t *testing.T) {
+	setEnv(t, map[string]string{"GITHUB_TOKEN": "", "GH_TOKEN": "env-token"})
+	tokenFile := writeFile(t, "token", "  file-token\n")
+	netrc := writeFile(t, "netrc", "machine github.com login x password netrc-token\n")
+
+	tests := []struct {
+		name       string
+		chain      CredentialChain
+		wantToken  string
+		wantSource string
+	}{
+		{
+			name:       "Flag wins",
+			chain:      CredentialChain{StaticCredentials{Token: "flag-token", Source: "--token flag"}, EnvCredentials{}, TokenFileCredentials{Path: tokenFile}},
+			wantToken:  "flag-token",
+			wantSource: "--token flag",
+		},
+		{
+			name:       "Environment before token file",
+			chain:      CredentialChain{StaticCredentials{}, EnvCredentials{}, TokenFileCredentials{Path: tokenFile}},
+			wantToken:  "env-token",
+			wantSource: "GH_TOKEN",
+		},
+		{
+			name:       "Token file",
+			chain:      CredentialChain{TokenFileCredentials{Path: tokenFile}, NetrcCredentials{Path: netrc}},
+			wantToken:  "file-token",
+			wantSource: "token file " + tokenFile,
+		},
+		{
+			name:       "Skips sources without token",
+			chain:      CredentialChain{TokenFileCredentials{}, GHHostsCredentials{Path: filepath.Join(t.TempDir(), "missing.yml")}, NetrcCredentials{Path: netrc}},
+			wantToken:  "netrc-token",
+			wantSource: netrc,
+		},
+		{
+			name:  "Nothing found",
+			chain: CredentialChain{TokenFileCredentials{}, GitCredentials{run: failingGit}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.chain.Lookup("github.com")
+			if err != nil {
+				t.Fatalf("Lookup() unexpected error = %v", err)
+			}
+			if tt.wantToken == "" {
+				if got != nil {
+					t.Errorf("Lookup() = %+v, want nil", got)
+				}
+				return
+			}
+			if got == nil || got.Token != tt.wantToken || got.Source != tt.wantSource {
+				t.Errorf("Lookup() = %+v, want token %q from %q", got, tt.wantToken, tt.wantSource)
+			}
+		})
+	}
+}
+
+func TestTokenFileCredentialsErrors(t *testing.T) {
+	empty := writeFile(t, "empty", "\n")
+	for _, path := range []string{empty, filepath.Join(t.TempDir(), "missing")} {
+		if _, err := (TokenFileCredentials{Path: path}).Lookup("github.com"); err == nil {
+			t.Errorf("Lookup() with %s expected error, but got nil", path)
+		}
+	}
+}
+
+func TestEnvCredentialsEnterprise(t *testing.T) {
+	setEnv(t, map[string]string{"GITHUB_TOKEN": "public", "GH_ENTERPRISE_TOKEN": "", "GITHUB_ENTERPRISE_TOKEN": "ghe"})
+
+	got, _ := EnvCredentials{}.Lookup("git.example.com")
+	if got == nil || got.Token != "ghe" || got.Source != "GITHUB_ENTERPRISE_TOKEN" {
+		t.Errorf("Lookup(enterprise) = %+v, want ghe from GITHUB_ENTERPRISE_TOKEN", got)
+	}
+	got, _ = EnvCredentials{}.Lookup("github.com")
+	if got == nil || got.Token != "public" {
+		t.Errorf("Lookup(github.com) = %+v, want public", got)
+	}
+}
+
+func TestParseGHHosts(t *testing.T) {
+	const hosts = `# gh hosts
+github.com:
+    user: alice
+    oauth_token: gho_public
+    git_protocol: https
+    users:
+        alice:
+            oauth_token: gho_nested
+"git.example.com":
+    oauth_token: "gho_enterprise"
+keyring.example.com:
+    user: bob
+    git_protocol: ssh
+`
+
+	tests := []struct {
+		host string
+		want string
+	}{
+		{host: "github.com", want: "gho_public"},
+		{host: "GIT.example.com", want: "gho_enterprise"},
+		{host: "keyring.example.com", want: ""},
+		{host: "other.example.com", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			if got := parseGHHosts([]byte(hosts), tt.host); got != tt.want {
+				t.Errorf("parseGHHosts(%q) = %q, want %q", tt.host, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseNetrc(t *testing.T) {
+	const netrc = `machine api.github.com
+  login alice
+  password api-token
+machine git.example.com login bob password ghe-token
+default login anonymous password default-token
+`
+
+	tests := []struct {
+		name     string
+		machines []string
+		want     string
+	}{
+		{name: "Exact machine", machines: []string{"git.example.com"}, want: "ghe-token"},
+		{name: "Alias machine", machines: []string{"github.com", "api.github.com"}, want: "api-token"},
+		{name: "Default entry", machines: []string{"other.example.com"}, want: "default-token"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseNetrc([]byte(netrc), tt.machines...); got != tt.want {
+				t.Errorf("parseNetrc(%v) = %q, want %q", tt.machines, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGitCredentials(t *testing.T) {
+	var input string
+	git := GitCredentials{run: func(ctx context.Context, in string) ([]byte, error) {
+		input = in
+		return []byte("protocol=https\nhost=git.example.com\nusername=bob\npassword=git-token\n"), nil
+	}}
+
+	got, err := git.Lookup("git.example.com")
+	if err != nil {
+		t.Fatalf("Lookup() unexpected error = %v", err)
+	}
+	if got == nil || got.Token != "git-token" || got.Source != "git credential helper" {
+		t.Errorf("Lookup() = %+v, want git-token from git credential helper", got)
+	}
+	if !strings.Contains(input, "host=git.example.com\n") || !strings.HasSuffix(input, "\n\n") {
+		t.Errorf("git credential fill input = %q", input)
+	}
+
+	if got, err := (GitCredentials{run: failingGit}).Lookup("github.com"); got != nil || err != nil {
+		t.Errorf("Lookup() with failing git = %+v, %v, want nil, nil", got, err)
+	}
+}
+
+// failingGit 模拟没有可用凭据的git credential fill
+func failingGit(ctx context.Context, input string) ([]byte, error) {
+	return nil, errors.New("fatal: could not read Username: terminal prompts disabled")
+}
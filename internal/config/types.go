@@ -3,15 +3,27 @@ package config
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
 	"strconv"
+	"strings"
 )
 
 // Config 应用程序配置
 // 包含GitHub令牌、输出配置和解析器配置
 type Config struct {
-	GitHubToken string      `json:"github_token"`
-	Output      OutputConfig `json:"output"`
-	Parser      ParserConfig `json:"parser"`
+	GitHubToken string                `json:"github_token"` // 显式指定的令牌，为空时按凭据链查找
+	TokenSource string                `json:"-"`            // GitHubToken的来源描述，为空时视为配置文件
+	TokenHost   string                `json:"-"`            // GitHubToken适用的主机，为空时只用于github.com；GitLab和Gitea主机只有是该主机时才使用TokenFile
+	TokenFile   string                `json:"token_file"`
+	Credentials CredentialChain       `json:"-"`       // 显式令牌之后尝试的来源链，为nil时使用DefaultCredentialChain
+	Verbose     bool                  `json:"verbose"` // 输出进度和令牌来源等详细信息
+	Output      OutputConfig          `json:"output"`
+	Parser      ParserConfig          `json:"parser"`
+	Cache       CacheConfig           `json:"cache"`
+	Hosts       map[string]HostConfig `json:"hosts"` // GitHub Enterprise Server、自托管GitLab和Gitea主机，键为主机名
+	App         AppConfig             `json:"app"`   // 访问github.com时使用的GitHub App，配置后代替GitHubToken；企业主机见HostConfig.App
+	Cassette    CassetteConfig        `json:"cassette"`
 }
 
 // OutputConfig 输出配置
@@ -30,6 +42,46 @@ type ParserConfig struct {
 	IncludeUserLinks   bool `json:"include_user_links"`
 	EmojisEnabled      bool `json:"emojis_enabled"`
 	PreserveLineBreaks bool `json:"preserve_line_breaks"`
+	EnableReactions    bool `json:"enable_reactions"`
+
+	// MinimizedComments 被折叠评论的处理方式: include、collapse或drop
+	MinimizedComments string `json:"minimized_comments"`
+}
+
+// HostConfig GitHub Enterprise Server、GitLab或Gitea/Forgejo主机配置
+type HostConfig struct {
+	Type      string `json:"type"`       // github（默认）、gitlab或gitea（包括Forgejo）
+	APIURL    string `json:"api_url"`    // REST接口地址，为空时使用 https://<host>/api/v3/，GitLab为/api/v4/，Gitea为/api/v1/
+	UploadURL string `json:"upload_url"` // 上传接口地址，为空时按APIURL推导
+	Token     string `json:"token"`
+
+	// App 访问该主机时使用的GitHub App，只适用于GitHub Enterprise Server
+	App AppConfig `json:"app"`
+}
+
+// AppConfig GitHub App认证配置
+type AppConfig struct {
+	ID             int64  `json:"id"`
+	PrivateKeyPath string `json:"private_key_path"` // PEM格式的App私钥文件
+	InstallationID int64  `json:"installation_id"`  // 为0时按资源所属账号查找安装
+}
+
+// Enabled 判断是否配置了GitHub App
+func (a AppConfig) Enabled() bool {
+	return a.ID != 0
+}
+
+// CassetteConfig 录制回放配置
+type CassetteConfig struct {
+	Dir  string `json:"dir"`  // 磁带目录，为空时不录制也不回放
+	Mode string `json:"mode"` // record, replay
+}
+
+// CacheConfig 磁盘缓存配置
+type CacheConfig struct {
+	Enabled bool   `json:"enabled"`
+	Dir     string `json:"dir"`
+	Mode    string `json:"mode"` // revalidate, refresh, offline
 }
 
 // Environment 环境变量配置
@@ -37,6 +89,21 @@ type Environment struct {
 	GitHubToken string
 	Debug       bool
 	NoColor     bool
+	CacheDir    string
+	// Hosts GitHub Enterprise Server主机列表，每项为 host 或 host=apiURL
+	Hosts []string
+	// GitLabHosts 自托管GitLab主机列表，格式同Hosts
+	GitLabHosts []string
+	// GiteaHosts Gitea/Forgejo主机列表，格式同Hosts
+	GiteaHosts []string
+	// TokenFile 保存令牌的文件路径
+	TokenFile string
+	// AppID、AppPrivateKey、AppInstallationID GitHub App认证配置
+	AppID             int64
+	AppPrivateKey     string
+	AppInstallationID int64
+	// AppHost 环境变量中的GitHub App所属主机，为空时为github.com
+	AppHost string
 }
 
 // DefaultConfig 返回默认配置
@@ -56,10 +123,26 @@ func DefaultConfig() *Config {
 			IncludeUserLinks:   true,
 			EmojisEnabled:      true,
 			PreserveLineBreaks: true,
+			MinimizedComments:  "collapse",
+		},
+		Cache: CacheConfig{
+			Enabled: true,
+			Dir:     defaultCacheDir(),
+			Mode:    "revalidate",
 		},
 	}
 }
 
+// defaultCacheDir 返回默认缓存目录，即用户缓存目录下的issue2md子目录
+// 无法确定用户缓存目录时返回空字符串
+func defaultCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "issue2md")
+}
+
 // LoadFromEnv 从环境变量加载配置
 // 将环境变量中的配置值加载到当前配置对象中
 // 参数: 无
@@ -67,8 +150,25 @@ func DefaultConfig() *Config {
 func (c *Config) LoadFromEnv() {
 	env := GetEnvironment()
 
-	if token := env.GitHubToken; token != "" {
-		c.GitHubToken = token
+	if tokenFile := env.TokenFile; tokenFile != "" {
+		c.TokenFile = tokenFile
+	}
+
+	if cacheDir := env.CacheDir; cacheDir != "" {
+		c.Cache.Dir = cacheDir
+	}
+
+	c.addHosts(env.Hosts, "")
+	c.addHosts(env.GitLabHosts, "gitlab")
+	c.addHosts(env.GiteaHosts, "gitea")
+
+	if appHost := strings.ToLower(strings.TrimSpace(env.AppHost)); isPublicHost(appHost) {
+		c.App = env.mergeApp(c.App)
+	} else if env.AppID != 0 || env.AppPrivateKey != "" || env.AppInstallationID != 0 {
+		c.addHosts([]string{appHost}, "")
+		hc := c.Hosts[appHost]
+		hc.App = env.mergeApp(hc.App)
+		c.Hosts[appHost] = hc
 	}
 
 	if debug := env.Debug; debug {
@@ -76,6 +176,35 @@ func (c *Config) LoadFromEnv() {
 	}
 }
 
+// addHosts 添加环境变量中的主机，每项为 host 或 host=apiURL
+// 参数:
+//   - entries: 主机列表
+//   - hostType: 主机类型，空字符串表示GitHub Enterprise Server
+// 返回值: 无
+func (c *Config) addHosts(entries []string, hostType string) {
+	for _, entry := range entries {
+		host, apiURL := entry, ""
+		if i := strings.Index(entry, "="); i >= 0 {
+			host, apiURL = entry[:i], entry[i+1:]
+		}
+		host = strings.ToLower(strings.TrimSpace(host))
+		if host == "" {
+			continue
+		}
+		if c.Hosts == nil {
+			c.Hosts = make(map[string]HostConfig)
+		}
+		hc := c.Hosts[host]
+		if apiURL != "" {
+			hc.APIURL = strings.TrimSpace(apiURL)
+		}
+		if hostType != "" {
+			hc.Type = hostType
+		}
+		c.Hosts[host] = hc
+	}
+}
+
 // GetEnvironment 获取环境变量
 // 从系统环境变量中读取配置并返回Environment结构体
 // 参数: 无
@@ -83,13 +212,74 @@ func (c *Config) LoadFromEnv() {
 // 异常: 无，如果环境变量解析失败会使用默认值
 func GetEnvironment() *Environment {
 	env := &Environment{
-		GitHubToken: os.Getenv("GITHUB_TOKEN"),
+		GitHubToken: firstEnv("GITHUB_TOKEN", "GH_TOKEN"),
 		Debug:       getBoolEnv("DEBUG", false),
 		NoColor:     getBoolEnv("NO_COLOR", false),
+		CacheDir:    os.Getenv("ISSUE2MD_CACHE_DIR"),
+		Hosts:       splitList(os.Getenv("ISSUE2MD_HOSTS")),
+		GitLabHosts: splitList(os.Getenv("ISSUE2MD_GITLAB_HOSTS")),
+		GiteaHosts:  splitList(os.Getenv("ISSUE2MD_GITEA_HOSTS")),
+		TokenFile:         os.Getenv("ISSUE2MD_TOKEN_FILE"),
+		AppID:             getInt64Env("ISSUE2MD_APP_ID", 0),
+		AppPrivateKey:     os.Getenv("ISSUE2MD_APP_PRIVATE_KEY"),
+		AppInstallationID: getInt64Env("ISSUE2MD_APP_INSTALLATION_ID", 0),
+		AppHost:           os.Getenv("ISSUE2MD_APP_HOST"),
 	}
 	return env
 }
 
+// splitList 按逗号分割列表，忽略空项
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// firstEnv 返回第一个非空的环境变量值
+// mergeApp 用环境变量中已设置的App字段覆盖app
+func (env *Environment) mergeApp(app AppConfig) AppConfig {
+	if env.AppID != 0 {
+		app.ID = env.AppID
+	}
+	if env.AppPrivateKey != "" {
+		app.PrivateKeyPath = env.AppPrivateKey
+	}
+	if env.AppInstallationID != 0 {
+		app.InstallationID = env.AppInstallationID
+	}
+	return app
+}
+
+func firstEnv(keys ...string) string {
+	for _, key := range keys {
+		if value := os.Getenv(key); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// getInt64Env 获取整数环境变量
+// 从环境变量中读取整数，如果解析失败则输出警告并返回默认值
+// 参数:
+//   - key: 环境变量名
+//   - defaultValue: 默认值，在未设置或解析失败时返回
+// 返回值: int64 - 解析后的整数或默认值
+func getInt64Env(key string, defaultValue int64) int64 {
+	if value := os.Getenv(key); value != "" {
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err == nil {
+			return parsed
+		}
+		fmt.Printf("warning: failed to parse integer environment variable %q: %v, using default value %v\n", key, err, defaultValue)
+	}
+	return defaultValue
+}
+
 // getBoolEnv 获取布尔环境变量
 // 从环境变量中读取布尔值，如果解析失败则记录警告并返回默认值
 // 参数:
@@ -115,10 +305,10 @@ func getBoolEnv(key string, defaultValue bool) bool {
 // 返回值: error - 如果验证失败返回ValidationError，否则返回nil
 // 异常: 可能返回ValidationError，包含错误字段和描述信息
 func (c *Config) Validate() error {
-	if c.GitHubToken == "" {
+	if c.App.Enabled() && c.App.PrivateKeyPath == "" {
 		return &ValidationError{
-			Field:   "github_token",
-			Message: "GitHub token is required",
+			Field:   "app.private_key_path",
+			Message: "GitHub App private key is required",
 		}
 	}
 
@@ -129,9 +319,165 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	if c.Cassette.Dir != "" && c.Cassette.Mode != "record" && c.Cassette.Mode != "replay" {
+		return &ValidationError{
+			Field:   "cassette.mode",
+			Message: fmt.Sprintf("Unknown cassette mode %q", c.Cassette.Mode),
+		}
+	}
+
+	switch c.Parser.MinimizedComments {
+	case "", "include", "collapse", "drop":
+	default:
+		return &ValidationError{
+			Field:   "parser.minimized_comments",
+			Message: fmt.Sprintf("Unknown minimized comments policy %q", c.Parser.MinimizedComments),
+		}
+	}
+
+	for host, hc := range c.Hosts {
+		switch hc.Type {
+		case "", "github", "gitlab", "gitea":
+		default:
+			return &ValidationError{
+				Field:   "hosts." + host + ".type",
+				Message: fmt.Sprintf("Unknown host type %q for %s", hc.Type, host),
+			}
+		}
+		if !hc.App.Enabled() {
+			continue
+		}
+		if c.HostType(host) != "github" {
+			return &ValidationError{
+				Field:   "hosts." + host + ".app",
+				Message: fmt.Sprintf("GitHub App cannot be used with %s host %s", hc.Type, host),
+			}
+		}
+		if hc.App.PrivateKeyPath == "" {
+			return &ValidationError{
+				Field:   "hosts." + host + ".app.private_key_path",
+				Message: "GitHub App private key is required",
+			}
+		}
+	}
+
+	switch c.Cache.Mode {
+	case "", "revalidate", "refresh", "offline":
+	default:
+		return &ValidationError{
+			Field:   "cache.mode",
+			Message: fmt.Sprintf("Unknown cache mode %q", c.Cache.Mode),
+		}
+	}
+
 	return nil
 }
 
+// LookupToken 查找访问指定主机使用的令牌
+// 依次尝试主机配置中的令牌、GitHubToken（如--token参数）和凭据链；
+// GitHubToken只用于TokenHost指定的主机，该主机也可以是GitLab或Gitea主机；
+// 其他GitLab和Gitea主机只使用主机配置中的令牌和各自的凭据链，令牌文件也只用于TokenHost，
+// 令牌不会发送给其他主机或平台
+// 参数:
+//   - host: 主机名，空字符串等同于github.com
+// 返回值: (*Credential, error) - 令牌及其来源，所有来源都未找到时返回 (nil, nil)
+func (c *Config) LookupToken(host string) (*Credential, error) {
+	if hc, ok := c.Hosts[strings.ToLower(host)]; ok && hc.Token != "" {
+		return &Credential{Token: hc.Token, Source: "configuration"}, nil
+	}
+	if c.GitHubToken != "" && c.tokenApplies(host) {
+		source := c.TokenSource
+		if source == "" {
+			source = "configuration"
+		}
+		return &Credential{Token: c.GitHubToken, Source: source}, nil
+	}
+
+	tokenFile := ""
+	if c.tokenApplies(host) {
+		tokenFile = c.TokenFile
+	}
+	switch c.HostType(host) {
+	case "gitlab":
+		return GitLabCredentialChain(tokenFile).Lookup(host)
+	case "gitea":
+		return GiteaCredentialChain(tokenFile).Lookup(host)
+	}
+
+	chain := c.Credentials
+	if chain == nil {
+		chain = DefaultCredentialChain(c.TokenFile)
+	}
+	return chain.Lookup(host)
+}
+
+// AppFor 返回访问指定主机时使用的GitHub App配置
+// github.com使用App字段，GitHub Enterprise Server主机使用主机配置中的App
+// 参数:
+//   - host: 主机名，空字符串等同于github.com
+// 返回值: AppConfig - App配置，未配置时Enabled()为false
+func (c *Config) AppFor(host string) AppConfig {
+	if isPublicHost(host) {
+		return c.App
+	}
+	return c.Hosts[strings.ToLower(host)].App
+}
+
+// tokenApplies 判断GitHubToken是否适用于主机，空主机名等同于github.com
+func (c *Config) tokenApplies(host string) bool {
+	tokenHost := c.TokenHost
+	if tokenHost == "" {
+		tokenHost = "github.com"
+	}
+	if host == "" {
+		host = "github.com"
+	}
+	return strings.EqualFold(host, tokenHost)
+}
+
+// EnterpriseHosts 返回已配置的GitHub Enterprise Server主机名，按字母排序
+func (c *Config) EnterpriseHosts() []string {
+	return c.hostsOfType("github")
+}
+
+// GitLabHosts 返回已配置的GitLab主机名，按字母排序，不包含未配置的gitlab.com
+func (c *Config) GitLabHosts() []string {
+	return c.hostsOfType("gitlab")
+}
+
+// GiteaHosts 返回已配置的Gitea/Forgejo主机名，按字母排序
+func (c *Config) GiteaHosts() []string {
+	return c.hostsOfType("gitea")
+}
+
+// HostType 返回主机所属的平台: github、gitlab或gitea
+// 未配置的主机中gitlab.com为gitlab，其余为github
+func (c *Config) HostType(host string) string {
+	host = strings.ToLower(host)
+	if hc, ok := c.Hosts[host]; ok {
+		if hc.Type == "" {
+			return "github"
+		}
+		return hc.Type
+	}
+	if host == "gitlab.com" {
+		return "gitlab"
+	}
+	return "github"
+}
+
+// hostsOfType 返回已配置的指定平台主机名，按字母排序
+func (c *Config) hostsOfType(hostType string) []string {
+	hosts := make([]string, 0, len(c.Hosts))
+	for host := range c.Hosts {
+		if c.HostType(host) == hostType {
+			hosts = append(hosts, host)
+		}
+	}
+	sort.Strings(hosts)
+	return hosts
+}
+
 // ValidationError 配置验证错误
 type ValidationError struct {
 	Field   string
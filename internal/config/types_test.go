@@ -23,6 +23,10 @@ func TestDefaultConfig(t *testing.T) {
 	if cfg.Output.Overwrite != false {
 		t.Errorf("Expected overwrite false, got %v", cfg.Output.Overwrite)
 	}
+
+	if !cfg.Cache.Enabled || cfg.Cache.Mode != "revalidate" {
+		t.Errorf("Expected cache enabled in revalidate mode, got %+v", cfg.Cache)
+	}
 }
 
 func TestGetEnvironment(t *testing.T) {
@@ -142,12 +146,69 @@ func TestConfigValidate(t *testing.T) {
 			wantErr: false,
 		},
 		{
+			// 令牌可能来自凭据链，验证时不再要求显式配置
 			name: "missing github token",
 			cfg: &Config{
 				Output: OutputConfig{
 					Format: "markdown",
 				},
 			},
+			wantErr: false,
+		},
+		{
+			name: "github app instead of token",
+			cfg: &Config{
+				Output: OutputConfig{
+					Format: "markdown",
+				},
+				App: AppConfig{ID: 1234, PrivateKeyPath: "app.pem"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "github app without private key",
+			cfg: &Config{
+				Output: OutputConfig{
+					Format: "markdown",
+				},
+				App: AppConfig{ID: 1234},
+			},
+			wantErr: true,
+		},
+		{
+			name: "github app on enterprise host",
+			cfg: &Config{
+				Output: OutputConfig{
+					Format: "markdown",
+				},
+				Hosts: map[string]HostConfig{
+					"github.example.com": {App: AppConfig{ID: 1234, PrivateKeyPath: "app.pem"}},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "enterprise github app without private key",
+			cfg: &Config{
+				Output: OutputConfig{
+					Format: "markdown",
+				},
+				Hosts: map[string]HostConfig{
+					"github.example.com": {App: AppConfig{ID: 1234}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "github app on gitlab host",
+			cfg: &Config{
+				Output: OutputConfig{
+					Format: "markdown",
+				},
+				Hosts: map[string]HostConfig{
+					"gitlab.example.com": {Type: "gitlab", App: AppConfig{ID: 1234, PrivateKeyPath: "app.pem"}},
+				},
+			},
 			wantErr: true,
 		},
 		{
@@ -160,6 +221,44 @@ func TestConfigValidate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "unknown cache mode",
+			cfg: &Config{
+				GitHubToken: "test-token",
+				Output: OutputConfig{
+					Format: "markdown",
+				},
+				Cache: CacheConfig{
+					Mode: "sometimes",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown cassette mode",
+			cfg: &Config{
+				Output: OutputConfig{
+					Format: "markdown",
+				},
+				Cassette: CassetteConfig{
+					Dir:  "testdata/cassette",
+					Mode: "rewind",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown minimized comments policy",
+			cfg: &Config{
+				Output: OutputConfig{
+					Format: "markdown",
+				},
+				Parser: ParserConfig{
+					MinimizedComments: "hide",
+				},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -172,6 +271,279 @@ func TestConfigValidate(t *testing.T) {
 	}
 }
 
+func TestLoadFromEnvHosts(t *testing.T) {
+	for _, key := range []string{"GITHUB_TOKEN", "ISSUE2MD_HOSTS", "GH_ENTERPRISE_TOKEN", "GITHUB_ENTERPRISE_TOKEN"} {
+		original, ok := os.LookupEnv(key)
+		defer func(key, original string, ok bool) {
+			if ok {
+				os.Setenv(key, original)
+			} else {
+				os.Unsetenv(key)
+			}
+		}(key, original, ok)
+		os.Unsetenv(key)
+	}
+
+	os.Setenv("GITHUB_TOKEN", "public-token")
+	os.Setenv("ISSUE2MD_HOSTS", "Git.Example.com, ghe.internal=https://api.ghe.internal/ ,")
+	os.Setenv("GITHUB_ENTERPRISE_TOKEN", "ghe-token")
+
+	cfg := DefaultConfig()
+	cfg.Hosts = map[string]HostConfig{
+		"ghe.internal": {Token: "own-token"},
+	}
+	cfg.Credentials = CredentialChain{EnvCredentials{}}
+	cfg.LoadFromEnv()
+
+	if got := cfg.EnterpriseHosts(); len(got) != 2 || got[0] != "ghe.internal" || got[1] != "git.example.com" {
+		t.Fatalf("EnterpriseHosts() = %v", got)
+	}
+	if got := cfg.Hosts["ghe.internal"].APIURL; got != "https://api.ghe.internal/" {
+		t.Errorf("ghe.internal APIURL = %q", got)
+	}
+
+	tokens := map[string]Credential{
+		"":                {Token: "public-token", Source: "GITHUB_TOKEN"},
+		"github.com":      {Token: "public-token", Source: "GITHUB_TOKEN"},
+		"git.example.com": {Token: "ghe-token", Source: "GITHUB_ENTERPRISE_TOKEN"},
+		"GIT.EXAMPLE.COM": {Token: "ghe-token", Source: "GITHUB_ENTERPRISE_TOKEN"},
+		"ghe.internal":    {Token: "own-token", Source: "configuration"},
+	}
+	for host, want := range tokens {
+		got, err := cfg.LookupToken(host)
+		if err != nil || got == nil || *got != want {
+			t.Errorf("LookupToken(%q) = %+v, %v, want %+v", host, got, err, want)
+		}
+	}
+}
+
+func TestLoadFromEnvGitLabHosts(t *testing.T) {
+	setEnv(t, map[string]string{
+		"ISSUE2MD_HOSTS":        "git.example.com",
+		"ISSUE2MD_GITLAB_HOSTS": "GitLab.Corp.example=https://gitlab.corp.example/api/v4/, code.internal",
+		"GITHUB_TOKEN":          "github-token",
+		"GITLAB_TOKEN":          "gitlab-token",
+	})
+
+	cfg := DefaultConfig()
+	cfg.GitHubToken = "flag-token"
+	cfg.Hosts = map[string]HostConfig{
+		"code.internal": {Token: "own-token"},
+	}
+	cfg.LoadFromEnv()
+
+	if got := cfg.EnterpriseHosts(); len(got) != 1 || got[0] != "git.example.com" {
+		t.Errorf("EnterpriseHosts() = %v", got)
+	}
+	if got := cfg.GitLabHosts(); len(got) != 2 || got[0] != "code.internal" || got[1] != "gitlab.corp.example" {
+		t.Errorf("GitLabHosts() = %v", got)
+	}
+	if got := cfg.Hosts["gitlab.corp.example"].APIURL; got != "https://gitlab.corp.example/api/v4/" {
+		t.Errorf("gitlab.corp.example APIURL = %q", got)
+	}
+	for host, want := range map[string]string{"gitlab.com": "gitlab", "GITLAB.CORP.EXAMPLE": "gitlab", "git.example.com": "github", "github.com": "github"} {
+		if got := cfg.HostType(host); got != want {
+			t.Errorf("HostType(%q) = %q, want %q", host, got, want)
+		}
+	}
+
+	// GitHub令牌不会发送给GitLab主机
+	tokens := map[string]Credential{
+		"gitlab.com":          {Token: "gitlab-token", Source: "GITLAB_TOKEN"},
+		"gitlab.corp.example": {Token: "gitlab-token", Source: "GITLAB_TOKEN"},
+		"code.internal":       {Token: "own-token", Source: "configuration"},
+		"github.com":          {Token: "flag-token", Source: "configuration"},
+	}
+	for host, want := range tokens {
+		got, err := cfg.LookupToken(host)
+		if err != nil || got == nil || *got != want {
+			t.Errorf("LookupToken(%q) = %+v, %v, want %+v", host, got, err, want)
+		}
+	}
+
+	// 指定了TokenHost时GitHubToken只用于该主机，其他GitHub主机按凭据链查找
+	cfg.TokenHost = "git.example.com"
+	if got, err := cfg.LookupToken("git.example.com"); err != nil || got == nil || got.Token != "flag-token" {
+		t.Errorf("LookupToken(git.example.com) = %+v, %v, want flag-token", got, err)
+	}
+	cfg.Credentials = CredentialChain{}
+	if got, err := cfg.LookupToken("github.com"); err != nil || got != nil {
+		t.Errorf("LookupToken(github.com) = %+v, %v, want no token", got, err)
+	}
+
+	cfg.Hosts["code.internal"] = HostConfig{Type: "bitbucket"}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() with unknown host type expected error, but got nil")
+	}
+}
+
+func TestLookupTokenHost(t *testing.T) {
+	setEnv(t, map[string]string{
+		"GITHUB_TOKEN":  "github-token",
+		"GH_TOKEN":      "",
+		"GITLAB_TOKEN":  "",
+		"FORGEJO_TOKEN": "",
+		"GITEA_TOKEN":   "",
+		"HOME":          t.TempDir(),
+	})
+	tokenFile := writeFile(t, "token", "file-token\n")
+	hosts := map[string]HostConfig{
+		"git.example.com":    {},
+		"gitlab.example.com": {Type: "gitlab"},
+		"forge.example.com":  {Type: "gitea"},
+	}
+
+	tests := []struct {
+		name string
+		cfg  *Config
+		host string
+		want string // 期望的令牌，空字符串表示未找到
+	}{
+		{
+			name: "token for github.com",
+			cfg:  &Config{GitHubToken: "flag-token", Hosts: hosts},
+			host: "github.com",
+			want: "flag-token",
+		},
+		{
+			name: "token for enterprise host",
+			cfg:  &Config{GitHubToken: "flag-token", TokenHost: "git.example.com", Hosts: hosts},
+			host: "git.example.com",
+			want: "flag-token",
+		},
+		{
+			name: "token for gitlab host",
+			cfg:  &Config{GitHubToken: "flag-token", TokenHost: "gitlab.example.com", Hosts: hosts},
+			host: "GitLab.example.com",
+			want: "flag-token",
+		},
+		{
+			name: "token for gitea host",
+			cfg:  &Config{GitHubToken: "flag-token", TokenHost: "forge.example.com", Hosts: hosts},
+			host: "forge.example.com",
+			want: "flag-token",
+		},
+		{
+			name: "github.com token not sent to gitlab",
+			cfg:  &Config{GitHubToken: "flag-token", Hosts: hosts},
+			host: "gitlab.example.com",
+			want: "",
+		},
+		{
+			name: "token file for gitlab host",
+			cfg:  &Config{TokenFile: tokenFile, TokenHost: "gitlab.example.com", Hosts: hosts},
+			host: "gitlab.example.com",
+			want: "file-token",
+		},
+		{
+			name: "token file not sent to other gitea host",
+			cfg:  &Config{TokenFile: tokenFile, TokenHost: "gitlab.example.com", Hosts: hosts},
+			host: "forge.example.com",
+			want: "",
+		},
+		{
+			name: "token file after environment on github.com",
+			cfg:  &Config{TokenFile: tokenFile, Hosts: hosts},
+			host: "github.com",
+			want: "github-token",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.cfg.LookupToken(tt.host)
+			if err != nil {
+				t.Fatalf("LookupToken(%q) unexpected error = %v", tt.host, err)
+			}
+			token := ""
+			if got != nil {
+				token = got.Token
+			}
+			if token != tt.want {
+				t.Errorf("LookupToken(%q) = %q, want %q", tt.host, token, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadFromEnvGiteaHosts(t *testing.T) {
+	setEnv(t, map[string]string{
+		"ISSUE2MD_GITEA_HOSTS": "forge.example.com, git.internal=https://git.internal/forgejo/",
+		"GITHUB_TOKEN":         "github-token",
+		"GITEA_TOKEN":          "gitea-token",
+		"FORGEJO_TOKEN":        "",
+	})
+
+	cfg := DefaultConfig()
+	cfg.LoadFromEnv()
+
+	if got := cfg.GiteaHosts(); len(got) != 2 || got[0] != "forge.example.com" || got[1] != "git.internal" {
+		t.Errorf("GiteaHosts() = %v", got)
+	}
+	if got := cfg.EnterpriseHosts(); len(got) != 0 {
+		t.Errorf("EnterpriseHosts() = %v, want none", got)
+	}
+	if got := cfg.HostType("Forge.Example.com"); got != "gitea" {
+		t.Errorf("HostType() = %q, want gitea", got)
+	}
+
+	got, err := cfg.LookupToken("git.internal")
+	if want := (Credential{Token: "gitea-token", Source: "GITEA_TOKEN"}); err != nil || got == nil || *got != want {
+		t.Errorf("LookupToken() = %+v, %v, want %+v", got, err, want)
+	}
+	setEnv(t, map[string]string{"FORGEJO_TOKEN": "forgejo-token"})
+	if got, _ := cfg.LookupToken("forge.example.com"); got == nil || got.Source != "FORGEJO_TOKEN" {
+		t.Errorf("LookupToken() = %+v, want FORGEJO_TOKEN first", got)
+	}
+}
+
+func TestLoadFromEnvApp(t *testing.T) {
+	for _, key := range []string{"ISSUE2MD_APP_ID", "ISSUE2MD_APP_PRIVATE_KEY", "ISSUE2MD_APP_INSTALLATION_ID", "ISSUE2MD_APP_HOST"} {
+		original, ok := os.LookupEnv(key)
+		defer func(key, original string, ok bool) {
+			if ok {
+				os.Setenv(key, original)
+			} else {
+				os.Unsetenv(key)
+			}
+		}(key, original, ok)
+	}
+
+	os.Setenv("ISSUE2MD_APP_ID", "1234")
+	os.Setenv("ISSUE2MD_APP_PRIVATE_KEY", "/etc/issue2md/app.pem")
+	os.Setenv("ISSUE2MD_APP_INSTALLATION_ID", "not-a-number")
+	os.Unsetenv("ISSUE2MD_APP_HOST")
+
+	cfg := DefaultConfig()
+	cfg.LoadFromEnv()
+
+	want := AppConfig{ID: 1234, PrivateKeyPath: "/etc/issue2md/app.pem"}
+	if cfg.App != want {
+		t.Errorf("App = %+v, want %+v", cfg.App, want)
+	}
+	if !cfg.App.Enabled() {
+		t.Error("App.Enabled() = false, want true")
+	}
+
+	os.Setenv("ISSUE2MD_APP_HOST", "GitHub.Example.com")
+
+	cfg = DefaultConfig()
+	cfg.LoadFromEnv()
+
+	if cfg.App.Enabled() {
+		t.Errorf("App = %+v, want github.com app unset", cfg.App)
+	}
+	if got := cfg.AppFor("github.example.com"); got != want {
+		t.Errorf("AppFor(github.example.com) = %+v, want %+v", got, want)
+	}
+	if got := cfg.HostType("github.example.com"); got != "github" {
+		t.Errorf("HostType(github.example.com) = %q, want github", got)
+	}
+	if got := cfg.AppFor("other.example.com"); got.Enabled() {
+		t.Errorf("AppFor(other.example.com) = %+v, want disabled", got)
+	}
+}
+
 func TestValidationError(t *testing.T) {
 	err := &ValidationError{
 		Field:   "test_field",
@@ -214,6 +214,21 @@ func (jc *JSONConverter) Convert(doc *parser.MarkdownDocument) ([]byte, error) {
 		jsonDoc["metadata"] = doc.Metadata
 	}
 
+	if len(doc.Projects) > 0 {
+		jsonDoc["projects"] = doc.Projects
+	}
+
+	// 写出渲染所用的数据，render子命令据此重新渲染
+	switch {
+	case doc.PullRequest != nil:
+		jsonDoc["pull_request"] = doc.PullRequest
+	case doc.Issue != nil:
+		jsonDoc["issue"] = doc.Issue
+		if len(doc.Comments) > 0 {
+			jsonDoc["comments"] = doc.Comments
+		}
+	}
+
 	result, err := json.MarshalIndent(jsonDoc, "", "  ")
 	if err != nil {
 		return nil, &ConversionError{
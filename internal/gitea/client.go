@@ -0,0 +1,75 @@
+package gitea
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/bigwhite/issue2md/internal/rest"
+)
+
+// defaultPageSize Gitea默认配置下单页允许的最大条目数（MAX_RESPONSE_ITEMS）
+const defaultPageSize = 50
+
+// Client Gitea/Forgejo客户端
+// 实现source.Client和source.AssetClient接口，Issue和Pull Request转换为与GitHub相同的数据模型；
+// Gitea接口不提供的增强数据（时间线、子Issue、修改历史等）和Discussion、搜索等GitHub独有的资源不提供相应的可选接口
+type Client struct {
+	*rest.Client
+}
+
+// NewClient 创建Gitea客户端
+// baseURL为REST接口地址，只给出主机地址（如 https://git.example.com）时自动补全 /api/v1/；
+// httpClient的Transport会依次被包装为RetryTransport和CacheTransport（默认禁用）；
+// token为访问令牌，为空时只能访问公开仓库
+func NewClient(httpClient *http.Client, baseURL, token string) (*Client, error) {
+	authorization := ""
+	if token != "" {
+		authorization = "token " + token
+	}
+	client, err := rest.NewClient(httpClient, baseURL, "api/v1/", authorization)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Gitea base URL %q", baseURL)
+	}
+	return &Client{Client: client}, nil
+}
+
+// get 请求接口并将JSON响应解码到out，返回是否还有下一页
+// path相对BaseURL，不以/开头；204响应视为空结果
+func (c *Client) get(ctx context.Context, path string, query url.Values, out interface{}) (bool, error) {
+	header, err := c.Get(ctx, path, query, out)
+	if err != nil {
+		return false, err
+	}
+	return strings.Contains(header.Get("Link"), `rel="next"`), nil
+}
+
+// pageQuery 返回分页查询参数
+func pageQuery(page int) url.Values {
+	return url.Values{
+		"page":  {strconv.Itoa(page)},
+		"limit": {strconv.Itoa(defaultPageSize)},
+	}
+}
+
+// repoPath 返回接口路径中的仓库部分
+func repoPath(owner, repo string) string {
+	return "repos/" + url.PathEscape(owner) + "/" + url.PathEscape(repo)
+}
+
+// webURL 返回Gitea网页的根地址，如 https://git.example.com
+// 接口位于子路径时（如 https://example.com/gitea/api/v1/）网页也位于该子路径
+func (c *Client) webURL() string {
+	root := *c.BaseURL
+	root.Path = webPath(root.Path)
+	root.RawQuery = ""
+	return root.String()
+}
+
+// webPath 返回接口路径对应的网页子路径，如 /gitea/api/v1/ 返回 /gitea，安装在根路径时返回空字符串
+func webPath(apiPath string) string {
+	return strings.TrimSuffix(strings.TrimSuffix(apiPath, "api/v1/"), "/")
+}
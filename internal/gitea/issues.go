@@ -0,0 +1,261 @@
+package gitea
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/bigwhite/issue2md/internal/model"
+)
+
+// apiUser Gitea接口返回的用户
+type apiUser struct {
+	ID        int64  `json:"id"`
+	Login     string `json:"login"`
+	AvatarURL string `json:"avatar_url"`
+	HTMLURL   string `json:"html_url"`
+}
+
+// apiLabel Gitea接口返回的标签，颜色不带#号
+type apiLabel struct {
+	Name        string `json:"name"`
+	Color       string `json:"color"`
+	Description string `json:"description"`
+}
+
+// apiMilestone Gitea接口返回的里程碑
+type apiMilestone struct {
+	ID          int64      `json:"id"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	State       string     `json:"state"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	DueOn       *time.Time `json:"due_on"`
+	ClosedAt    *time.Time `json:"closed_at"`
+}
+
+// apiIssue Gitea接口返回的Issue，Pull Request共用其中的字段
+type apiIssue struct {
+	Number    int           `json:"number"`
+	Title     string        `json:"title"`
+	Body      string        `json:"body"`
+	State     string        `json:"state"`
+	User      *apiUser      `json:"user"`
+	Labels    []*apiLabel   `json:"labels"`
+	Assignees []*apiUser    `json:"assignees"`
+	Milestone *apiMilestone `json:"milestone"`
+	CreatedAt time.Time     `json:"created_at"`
+	UpdatedAt time.Time     `json:"updated_at"`
+	ClosedAt  *time.Time    `json:"closed_at"`
+	URL       string        `json:"url"`
+	HTMLURL   string        `json:"html_url"`
+}
+
+// apiComment Gitea接口返回的评论
+type apiComment struct {
+	ID        int64     `json:"id"`
+	Body      string    `json:"body"`
+	User      *apiUser  `json:"user"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	HTMLURL   string    `json:"html_url"`
+}
+
+// apiReaction Gitea接口返回的一个表情回应
+type apiReaction struct {
+	Content string `json:"content"` // +1、-1、laugh、hooray、confused、heart、rocket、eyes
+}
+
+// attachmentLinkPattern 匹配Markdown和HTML中相对站点根的附件链接，如 ](/attachments/<uuid>)
+var attachmentLinkPattern = regexp.MustCompile(`(\]\(|src=["'])(/attachments/)`)
+
+// GetIssue 获取仓库的Issue及其表情回应
+func (c *Client) GetIssue(ctx context.Context, owner, repo string, issueNumber int) (*model.Issue, error) {
+	var issue apiIssue
+	path := fmt.Sprintf("%s/issues/%d", repoPath(owner, repo), issueNumber)
+	if _, err := c.get(ctx, path, nil, &issue); err != nil {
+		return nil, fmt.Errorf("failed to get issue #%d from %s/%s: %w", issueNumber, owner, repo, err)
+	}
+
+	result := c.convertIssue(&issue)
+	result.Repository = owner + "/" + repo
+
+	reactions, err := c.reactions(ctx, path+"/reactions")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reactions for issue #%d from %s/%s: %w", issueNumber, owner, repo, err)
+	}
+	result.Reactions = reactions
+	return result, nil
+}
+
+// GetIssueComments 获取Issue或Pull Request的全部普通评论
+func (c *Client) GetIssueComments(ctx context.Context, owner, repo string, issueNumber int) ([]*model.Comment, error) {
+	var comments []*model.Comment
+	it := c.IssueComments(owner, repo, issueNumber)
+	for it.Next(ctx) {
+		comments = append(comments, it.Comment())
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+// reactionConcurrency 并发获取评论表情回应的最大数量
+const reactionConcurrency = 4
+
+// IssueComments 返回Issue评论的迭代器，评论按创建时间升序排列
+// Gitea的评论接口不分页，迭代器只请求一次；每条评论的表情回应单独请求，最多reactionConcurrency个并发
+func (c *Client) IssueComments(owner, repo string, issueNumber int) *model.CommentIterator {
+	return model.NewCommentIterator(func(ctx context.Context, page int) ([]*model.Comment, int, error) {
+		var apiComments []*apiComment
+		path := fmt.Sprintf("%s/issues/%d/comments", repoPath(owner, repo), issueNumber)
+		if _, err := c.get(ctx, path, nil, &apiComments); err != nil {
+			return nil, 0, fmt.Errorf("failed to get comments for issue #%d from %s/%s: %w", issueNumber, owner, repo, err)
+		}
+
+		comments := make([]*model.Comment, 0, len(apiComments))
+		// 第一个失败的请求取消其余请求，errs只保留该错误
+		groupCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		var wg sync.WaitGroup
+		sem := make(chan struct{}, reactionConcurrency)
+		errs := make(chan error, 1)
+		for _, apiComment := range apiComments {
+			if apiComment == nil {
+				continue
+			}
+			if groupCtx.Err() != nil {
+				break
+			}
+			comment := c.convertComment(apiComment)
+			comments = append(comments, comment)
+
+			id := apiComment.ID
+			sem <- struct{}{}
+			wg.Add(1)
+			go func() {
+				defer func() {
+					<-sem
+					wg.Done()
+				}()
+				reactionsPath := fmt.Sprintf("%s/issues/comments/%d/reactions", repoPath(owner, repo), id)
+				reactions, err := c.reactions(groupCtx, reactionsPath)
+				if err != nil {
+					select {
+					case errs <- fmt.Errorf("failed to get reactions for comment %d from %s/%s: %w", id, owner, repo, err):
+						cancel()
+					default:
+					}
+					return
+				}
+				comment.Reactions = reactions
+			}()
+		}
+		wg.Wait()
+		select {
+		case err := <-errs:
+			return nil, 0, err
+		default:
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, 0, err
+		}
+		return comments, 0, nil
+	})
+}
+
+// reactions 分页获取表情回应并按类型计数
+func (c *Client) reactions(ctx context.Context, path string) (model.Reactions, error) {
+	var counts model.Reactions
+	for page := 1; ; page++ {
+		var reactions []*apiReaction
+		more, err := c.get(ctx, path, pageQuery(page), &reactions)
+		if err != nil {
+			return model.Reactions{}, err
+		}
+
+		for _, reaction := range reactions {
+			if reaction != nil {
+				counts.Add(reaction.Content, 1)
+			}
+		}
+
+		if !more {
+			return counts, nil
+		}
+	}
+}
+
+// convertIssue 把Gitea Issue转换为model.Issue
+func (c *Client) convertIssue(issue *apiIssue) *model.Issue {
+	result := &model.Issue{
+		Number:    issue.Number,
+		Title:     issue.Title,
+		Body:      c.absolutizeAttachments(issue.Body),
+		State:     issue.State,
+		User:      convertUser(issue.User),
+		CreatedAt: issue.CreatedAt,
+		UpdatedAt: issue.UpdatedAt,
+		ClosedAt:  issue.ClosedAt,
+		URL:       issue.URL,
+		HTMLURL:   issue.HTMLURL,
+	}
+	for _, label := range issue.Labels {
+		if label != nil {
+			result.Labels = append(result.Labels, model.Label{Name: label.Name, Color: label.Color, Description: label.Description})
+		}
+	}
+	for _, assignee := range issue.Assignees {
+		if assignee != nil {
+			result.Assignees = append(result.Assignees, convertUser(assignee))
+		}
+	}
+	if m := issue.Milestone; m != nil {
+		result.Milestone = &model.Milestone{
+			Title:       m.Title,
+			Number:      int(m.ID),
+			State:       m.State,
+			Description: m.Description,
+			CreatedAt:   m.CreatedAt,
+			UpdatedAt:   m.UpdatedAt,
+			DueDate:     m.DueOn,
+			ClosedAt:    m.ClosedAt,
+		}
+	}
+	return result
+}
+
+// convertComment 把Gitea评论转换为model.Comment
+func (c *Client) convertComment(comment *apiComment) *model.Comment {
+	return &model.Comment{
+		ID:        comment.ID,
+		Body:      c.absolutizeAttachments(comment.Body),
+		User:      convertUser(comment.User),
+		CreatedAt: comment.CreatedAt,
+		UpdatedAt: comment.UpdatedAt,
+		HTMLURL:   comment.HTMLURL,
+	}
+}
+
+// convertUser 把Gitea用户转换为model.User，已删除的用户为nil
+func convertUser(user *apiUser) model.User {
+	if user == nil {
+		return model.User{}
+	}
+	return model.User{
+		Login:     user.Login,
+		ID:        user.ID,
+		AvatarURL: user.AvatarURL,
+		HTMLURL:   user.HTMLURL,
+		Type:      "User",
+	}
+}
+
+// absolutizeAttachments 把相对站点根的附件链接改写为绝对地址，使导出的文档脱离Gitea后仍能访问附件
+func (c *Client) absolutizeAttachments(body string) string {
+	return attachmentLinkPattern.ReplaceAllString(body, "${1}"+c.webURL()+"${2}")
+}
@@ -0,0 +1,164 @@
+package gitea
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// mockIssueJSON 模拟Gitea Issue API响应
+const mockIssueJSON = `{
+	"id": 901,
+	"number": 12,
+	"title": "Crash on startup",
+	"body": "See ![log](/attachments/0b2c9a3e-1111-2222-3333-444455556666)",
+	"state": "closed",
+	"user": {"id": 7, "login": "alice", "avatar_url": "https://git.example.com/avatars/7", "html_url": "https://git.example.com/alice"},
+	"labels": [{"name": "bug", "color": "ee0701", "description": "Something is broken"}],
+	"assignees": [{"id": 8, "login": "bob"}],
+	"milestone": {"id": 3, "title": "v1.0", "state": "open", "due_on": "2024-02-01T00:00:00Z"},
+	"created_at": "2024-01-05T09:00:00Z",
+	"updated_at": "2024-01-06T14:00:00Z",
+	"closed_at": "2024-01-06T14:00:00Z",
+	"html_url": "https://git.example.com/team/app/issues/12"
+}`
+
+// newTestClient 创建访问测试服务器的客户端，禁用重试
+func newTestClient(t *testing.T, server *httptest.Server) *Client {
+	t.Helper()
+	client, err := NewClient(server.Client(), server.URL, "test-token")
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	client.Retrier.Policy.MaxAttempts = 1
+	return client
+}
+
+func TestNewClient(t *testing.T) {
+	tests := []struct {
+		name    string
+		baseURL string
+		want    string
+		wantWeb string
+		wantErr bool
+	}{
+		{name: "host only", baseURL: "https://git.example.com", want: "https://git.example.com/api/v1/", wantWeb: "https://git.example.com"},
+		{name: "full API URL", baseURL: "https://git.example.com/api/v1", want: "https://git.example.com/api/v1/", wantWeb: "https://git.example.com"},
+		{name: "sub path", baseURL: "https://example.com/forgejo/", want: "https://example.com/forgejo/api/v1/", wantWeb: "https://example.com/forgejo"},
+		{name: "missing host", baseURL: "forgejo", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewClient(nil, tt.baseURL, "")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewClient() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if client.BaseURL.String() != tt.want {
+				t.Errorf("BaseURL = %q, want %q", client.BaseURL, tt.want)
+			}
+			if got := client.webURL(); got != tt.wantWeb {
+				t.Errorf("webURL() = %q, want %q", got, tt.wantWeb)
+			}
+		})
+	}
+}
+
+func TestClientGetIssue(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "token test-token" {
+			t.Errorf("Authorization = %q", got)
+		}
+		switch r.URL.Path {
+		case "/api/v1/repos/team/app/issues/12":
+			w.Write([]byte(mockIssueJSON))
+		case "/api/v1/repos/team/app/issues/12/reactions":
+			if r.URL.Query().Get("page") == "1" {
+				w.Header().Set("Link", `<`+"http://"+r.Host+r.URL.Path+`?page=2&limit=50>; rel="next"`)
+				w.Write([]byte(`[{"content": "+1"}, {"content": "heart"}]`))
+				return
+			}
+			w.Write([]byte(`[{"content": "+1"}, {"content": "unknown"}]`))
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	issue, err := newTestClient(t, server).GetIssue(context.Background(), "team", "app", 12)
+	if err != nil {
+		t.Fatalf("GetIssue() error = %v", err)
+	}
+
+	if issue.Number != 12 || issue.State != "closed" || issue.ClosedAt == nil || issue.Repository != "team/app" {
+		t.Errorf("issue = %+v", issue)
+	}
+	if issue.User.Login != "alice" || len(issue.Assignees) != 1 || issue.Assignees[0].Login != "bob" {
+		t.Errorf("User = %+v, Assignees = %+v", issue.User, issue.Assignees)
+	}
+	if len(issue.Labels) != 1 || issue.Labels[0].Color != "ee0701" || issue.Labels[0].Description != "Something is broken" {
+		t.Errorf("Labels = %+v", issue.Labels)
+	}
+	if issue.Milestone == nil || issue.Milestone.Title != "v1.0" || issue.Milestone.DueDate == nil {
+		t.Errorf("Milestone = %+v", issue.Milestone)
+	}
+	if r := issue.Reactions; r.TotalCount != 3 || r.ThumbsUp != 2 || r.Heart != 1 {
+		t.Errorf("Reactions = %+v", r)
+	}
+	if want := "See ![log](" + server.URL + "/attachments/0b2c9a3e-1111-2222-3333-444455556666)"; issue.Body != want {
+		t.Errorf("Body = %q, want %q", issue.Body, want)
+	}
+}
+
+func TestClientGetIssueComments(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/repos/team/app/issues/12/comments":
+			w.Write([]byte(`[
+				{"id": 1, "body": "First", "user": {"login": "bob"}, "created_at": "2024-01-05T10:00:00Z", "html_url": "https://git.example.com/team/app/issues/12#issuecomment-1"},
+				{"id": 2, "body": "Second", "user": null, "created_at": "2024-01-05T11:00:00Z"}
+			]`))
+		case "/api/v1/repos/team/app/issues/comments/1/reactions":
+			w.Write([]byte(`[{"content": "rocket"}]`))
+		case "/api/v1/repos/team/app/issues/comments/2/reactions":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	comments, err := newTestClient(t, server).GetIssueComments(context.Background(), "team", "app", 12)
+	if err != nil {
+		t.Fatalf("GetIssueComments() error = %v", err)
+	}
+
+	if len(comments) != 2 {
+		t.Fatalf("got %d comments, want 2", len(comments))
+	}
+	if comments[0].Body != "First" || comments[0].Reactions.Rocket != 1 || comments[0].HTMLURL == "" {
+		t.Errorf("first comment = %+v", comments[0])
+	}
+	if comments[1].User.Login != "" || comments[1].Reactions.TotalCount != 0 {
+		t.Errorf("second comment = %+v", comments[1])
+	}
+}
+
+func TestClientGetIssueNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message": "issue does not exist"}`))
+	}))
+	defer server.Close()
+
+	_, err := newTestClient(t, server).GetIssue(context.Background(), "team", "app", 1)
+	if err == nil {
+		t.Fatal("GetIssue() error = nil, want error")
+	}
+}
@@ -0,0 +1,184 @@
+package gitea
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/bigwhite/issue2md/internal/model"
+)
+
+// apiBranch Gitea接口返回的Pull Request分支
+type apiBranch struct {
+	Label string `json:"label"`
+	Ref   string `json:"ref"`
+	SHA   string `json:"sha"`
+	Repo  *struct {
+		FullName string `json:"full_name"`
+	} `json:"repo"`
+}
+
+// apiPullRequest Gitea接口返回的Pull Request
+type apiPullRequest struct {
+	apiIssue
+	Draft              bool       `json:"draft"`
+	Merged             bool       `json:"merged"`
+	MergedAt           *time.Time `json:"merged_at"`
+	MergedBy           *apiUser   `json:"merged_by"`
+	MergeCommitSHA     string     `json:"merge_commit_sha"`
+	Head               apiBranch  `json:"head"`
+	Base               apiBranch  `json:"base"`
+	RequestedReviewers []*apiUser `json:"requested_reviewers"`
+}
+
+// apiReview Gitea接口返回的评审
+type apiReview struct {
+	ID          int64      `json:"id"`
+	User        *apiUser   `json:"user"`
+	Body        string     `json:"body"`
+	State       string     `json:"state"` // APPROVED、REQUEST_CHANGES、COMMENT、PENDING、REQUEST_REVIEW
+	SubmittedAt *time.Time `json:"submitted_at"`
+	CommitID    string     `json:"commit_id"`
+	HTMLURL     string     `json:"html_url"`
+}
+
+// apiReviewComment Gitea接口返回的代码评审评论
+type apiReviewComment struct {
+	apiComment
+	Path             string `json:"path"`
+	Position         int    `json:"position"`
+	OriginalPosition int    `json:"original_position"`
+	DiffHunk         string `json:"diff_hunk"`
+	ReviewID         int64  `json:"pull_request_review_id"`
+}
+
+// reviewStates Gitea评审状态到GitHub评审状态的映射，未列出的状态（待提交、请求评审）被跳过
+var reviewStates = map[string]string{
+	"APPROVED":        "APPROVED",
+	"REQUEST_CHANGES": "CHANGES_REQUESTED",
+	"COMMENT":         "COMMENTED",
+}
+
+// GetPullRequest 获取Pull Request信息
+// 包括合并/草稿状态、分支信息、评审结论，以及按时间排序的普通评论和代码评审评论
+func (c *Client) GetPullRequest(ctx context.Context, owner, repo string, number int) (*model.PullRequest, error) {
+	var apiPR apiPullRequest
+	if _, err := c.get(ctx, fmt.Sprintf("%s/pulls/%d", repoPath(owner, repo), number), nil, &apiPR); err != nil {
+		return nil, fmt.Errorf("failed to get pull request %d from %s/%s: %w", number, owner, repo, err)
+	}
+
+	issue := c.convertIssue(&apiPR.apiIssue)
+	issue.Repository = owner + "/" + repo
+	pr := &model.PullRequest{
+		Issue:          *issue,
+		Draft:          apiPR.Draft,
+		Merged:         apiPR.Merged || apiPR.MergedAt != nil,
+		MergedAt:       apiPR.MergedAt,
+		MergeCommitSHA: apiPR.MergeCommitSHA,
+		Head:           convertBranch(apiPR.Head),
+		Base:           convertBranch(apiPR.Base),
+	}
+	if apiPR.MergedBy != nil {
+		mergedBy := convertUser(apiPR.MergedBy)
+		pr.MergedBy = &mergedBy
+	}
+	for _, reviewer := range apiPR.RequestedReviewers {
+		if reviewer != nil {
+			pr.RequestedReviewers = append(pr.RequestedReviewers, convertUser(reviewer))
+		}
+	}
+
+	// PR接口不返回reactions，需要从同编号的Issue接口读取
+	reactions, err := c.reactions(ctx, fmt.Sprintf("%s/issues/%d/reactions", repoPath(owner, repo), number))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reactions for pull request %d from %s/%s: %w", number, owner, repo, err)
+	}
+	pr.Reactions = reactions
+
+	// PR的普通评论走Issue评论接口
+	comments, err := c.GetIssueComments(ctx, owner, repo, number)
+	if err != nil {
+		return nil, err
+	}
+
+	reviewComments, err := c.getReviews(ctx, owner, repo, number, pr)
+	if err != nil {
+		return nil, err
+	}
+
+	pr.Comments = append(comments, reviewComments...)
+	sort.SliceStable(pr.Comments, func(i, j int) bool {
+		return pr.Comments[i].CreatedAt.Before(pr.Comments[j].CreatedAt)
+	})
+	return pr, nil
+}
+
+// getReviews 分页获取PR的全部评审结论填充到pr.Reviews，并返回各评审的代码评审评论
+func (c *Client) getReviews(ctx context.Context, owner, repo string, number int, pr *model.PullRequest) ([]*model.Comment, error) {
+	path := fmt.Sprintf("%s/pulls/%d/reviews", repoPath(owner, repo), number)
+	var comments []*model.Comment
+	for page := 1; ; page++ {
+		var reviews []*apiReview
+		more, err := c.get(ctx, path, pageQuery(page), &reviews)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get reviews for pull request %d from %s/%s: %w", number, owner, repo, err)
+		}
+
+		for _, review := range reviews {
+			if review == nil {
+				continue
+			}
+			state, ok := reviewStates[review.State]
+			if !ok {
+				continue
+			}
+			pr.Reviews = append(pr.Reviews, &model.Review{
+				ID:          review.ID,
+				User:        convertUser(review.User),
+				Body:        review.Body,
+				State:       state,
+				SubmittedAt: review.SubmittedAt,
+				CommitID:    review.CommitID,
+				HTMLURL:     review.HTMLURL,
+			})
+
+			var reviewComments []*apiReviewComment
+			if _, err := c.get(ctx, fmt.Sprintf("%s/%d/comments", path, review.ID), nil, &reviewComments); err != nil {
+				return nil, fmt.Errorf("failed to get review comments for pull request %d from %s/%s: %w", number, owner, repo, err)
+			}
+			for _, reviewComment := range reviewComments {
+				if reviewComment != nil {
+					comments = append(comments, c.convertReviewComment(reviewComment))
+				}
+			}
+		}
+
+		if !more {
+			return comments, nil
+		}
+	}
+}
+
+// convertReviewComment 把Gitea代码评审评论转换为model.Comment
+// 行号优先取新文件一侧，评论所在行已被后续提交改动时取原始行号
+func (c *Client) convertReviewComment(comment *apiReviewComment) *model.Comment {
+	result := c.convertComment(&comment.apiComment)
+	result.Path = comment.Path
+	result.Line = comment.Position
+	if result.Line == 0 {
+		result.Line = comment.OriginalPosition
+	}
+	result.DiffHunk = comment.DiffHunk
+	result.ReviewID = comment.ReviewID
+	return result
+}
+
+// convertBranch 把Gitea分支转换为model.Branch
+func convertBranch(branch apiBranch) model.Branch {
+	result := model.Branch{Label: branch.Label, Ref: branch.Ref, SHA: branch.SHA}
+	if branch.Repo != nil {
+		result.Repo = branch.Repo.FullName
+	}
+	return result
+}
@@ -0,0 +1,88 @@
+package gitea
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// mockPullRequestJSON 模拟Gitea Pull Request API响应
+const mockPullRequestJSON = `{
+	"number": 7,
+	"title": "Add retry support",
+	"body": "Fixes #12",
+	"state": "closed",
+	"user": {"id": 7, "login": "alice"},
+	"labels": [{"name": "feature", "color": "84b6eb"}],
+	"merged": true,
+	"merged_at": "2024-01-06T14:00:00Z",
+	"merged_by": {"id": 9, "login": "maintainer"},
+	"merge_commit_sha": "abc123",
+	"head": {"label": "retry", "ref": "retry", "sha": "def456", "repo": {"full_name": "alice/app"}},
+	"base": {"label": "main", "ref": "main", "sha": "789abc", "repo": {"full_name": "team/app"}},
+	"requested_reviewers": [{"id": 8, "login": "bob"}],
+	"created_at": "2024-01-05T09:00:00Z",
+	"updated_at": "2024-01-06T14:00:00Z",
+	"closed_at": "2024-01-06T14:00:00Z",
+	"html_url": "https://git.example.com/team/app/pulls/7"
+}`
+
+func TestClientGetPullRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/repos/team/app/pulls/7":
+			w.Write([]byte(mockPullRequestJSON))
+		case "/api/v1/repos/team/app/issues/7/reactions":
+			w.Write([]byte(`[{"content": "hooray"}]`))
+		case "/api/v1/repos/team/app/issues/7/comments":
+			w.Write([]byte(`[{"id": 1, "body": "Looks good", "user": {"login": "bob"}, "created_at": "2024-01-05T12:00:00Z"}]`))
+		case "/api/v1/repos/team/app/issues/comments/1/reactions":
+			w.Write([]byte(`[]`))
+		case "/api/v1/repos/team/app/pulls/7/reviews":
+			w.Write([]byte(`[
+				{"id": 21, "user": {"login": "bob"}, "body": "Please rename", "state": "REQUEST_CHANGES", "submitted_at": "2024-01-05T11:00:00Z", "commit_id": "def456"},
+				{"id": 22, "user": {"login": "bob"}, "state": "PENDING"},
+				{"id": 23, "user": {"login": "bob"}, "state": "APPROVED", "submitted_at": "2024-01-06T10:00:00Z"}
+			]`))
+		case "/api/v1/repos/team/app/pulls/7/reviews/21/comments":
+			w.Write([]byte(`[{"id": 31, "body": "This name is confusing.", "user": {"login": "bob"}, "path": "retry.go", "position": 0, "original_position": 12,
+				"diff_hunk": "@@ -10,3 +10,4 @@", "pull_request_review_id": 21, "created_at": "2024-01-05T11:00:00Z"}]`))
+		case "/api/v1/repos/team/app/pulls/7/reviews/23/comments":
+			w.Write([]byte(`[]`))
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	pr, err := newTestClient(t, server).GetPullRequest(context.Background(), "team", "app", 7)
+	if err != nil {
+		t.Fatalf("GetPullRequest() error = %v", err)
+	}
+
+	if pr.Status() != "merged" || pr.MergedBy == nil || pr.MergedBy.Login != "maintainer" || pr.MergeCommitSHA != "abc123" {
+		t.Errorf("pr = %+v", pr)
+	}
+	if pr.Head.Repo != "alice/app" || pr.Head.Ref != "retry" || pr.Base.SHA != "789abc" {
+		t.Errorf("Head = %+v, Base = %+v", pr.Head, pr.Base)
+	}
+	if len(pr.RequestedReviewers) != 1 || pr.Reactions.Hooray != 1 {
+		t.Errorf("RequestedReviewers = %+v, Reactions = %+v", pr.RequestedReviewers, pr.Reactions)
+	}
+	if len(pr.Reviews) != 2 || pr.Reviews[0].State != "CHANGES_REQUESTED" || pr.Reviews[1].State != "APPROVED" {
+		t.Errorf("Reviews = %+v", pr.Reviews)
+	}
+
+	if len(pr.Comments) != 2 {
+		t.Fatalf("got %d comments, want 2", len(pr.Comments))
+	}
+	review, general := pr.Comments[0], pr.Comments[1]
+	if review.Path != "retry.go" || review.Line != 12 || review.ReviewID != 21 || review.DiffHunk == "" {
+		t.Errorf("review comment = %+v", review)
+	}
+	if general.Body != "Looks good" || general.IsReviewComment() {
+		t.Errorf("general comment = %+v", general)
+	}
+}
@@ -0,0 +1,115 @@
+package gitea
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/bigwhite/issue2md/internal/parser"
+)
+
+// URLParser Gitea/Forgejo URL解析器，实现parser.URLParser接口
+// 支持的URL:
+//   - Issue: https://git.example.com/owner/repo/issues/12
+//   - Pull Request: https://git.example.com/owner/repo/pulls/7，类型为"pull"
+//
+// Gitea没有公共实例，只接受配置的主机；安装在子路径下的实例（如 https://example.com/gitea/owner/repo/issues/1）
+// 需要通过SetAPIURL设置接口地址，解析时先去掉子路径
+type URLParser struct {
+	hosts map[string]string // 主机名到安装子路径，如 /gitea，安装在根路径时为空
+}
+
+// NewURLParser 创建Gitea URL解析器，只接受hosts中的主机
+func NewURLParser(hosts ...string) *URLParser {
+	accepted := make(map[string]string)
+	for _, host := range hosts {
+		accepted[strings.ToLower(host)] = ""
+	}
+	return &URLParser{hosts: accepted}
+}
+
+// SetAPIURL 按REST接口地址设置主机上Gitea的安装子路径并接受该主机
+// 如 https://example.com/gitea/api/v1/ 对应子路径 /gitea；地址无法解析时按安装在根路径处理
+func (p *URLParser) SetAPIURL(host, apiURL string) {
+	basePath := ""
+	if u, err := url.Parse(apiURL); err == nil {
+		path := u.Path
+		if !strings.HasSuffix(path, "/") {
+			path += "/"
+		}
+		basePath = webPath(path)
+	}
+	p.hosts[strings.ToLower(host)] = basePath
+}
+
+// Parse 解析Gitea Issue或Pull Request的URL
+func (p *URLParser) Parse(rawURL string) (*parser.ResourceURL, error) {
+	u, err := p.parseURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	path := u.Path
+	if basePath := p.hosts[strings.ToLower(u.Host)]; basePath != "" {
+		if path != basePath && !strings.HasPrefix(path, basePath+"/") {
+			return nil, fmt.Errorf("invalid URL: not under the Gitea base path %s", basePath)
+		}
+		path = strings.TrimPrefix(path, basePath)
+	}
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) != 4 {
+		return nil, fmt.Errorf("invalid URL: not a Gitea issue or pull request URL")
+	}
+
+	res := &parser.ResourceURL{Host: strings.ToLower(u.Host), Owner: parts[0], Repo: parts[1], URL: rawURL}
+	switch parts[2] {
+	case "issues":
+		res.Type = "issue"
+	case "pulls":
+		res.Type = "pull"
+	default:
+		return nil, fmt.Errorf("invalid URL: unsupported URL type: %s", parts[2])
+	}
+
+	number, err := strconv.Atoi(parts[3])
+	if err != nil || number <= 0 {
+		return nil, fmt.Errorf("invalid URL: invalid number format '%s'", parts[3])
+	}
+	res.Number = number
+	return res, nil
+}
+
+// ParseRepository 实现parser.URLParser接口，Gitea仓库的批量导出尚未支持
+func (p *URLParser) ParseRepository(rawURL string) (*parser.ResourceURL, error) {
+	if _, err := p.parseURL(rawURL); err != nil {
+		return nil, err
+	}
+	return nil, fmt.Errorf("invalid URL: exporting a whole Gitea repository is not supported")
+}
+
+// Validate 验证URL格式
+func (p *URLParser) Validate(rawURL string) error {
+	_, err := p.Parse(rawURL)
+	return err
+}
+
+// SupportedTypes 返回支持的资源类型
+func (p *URLParser) SupportedTypes() []string {
+	return []string{"issue", "pull"}
+}
+
+// parseURL 解析URL并检查主机
+func (p *URLParser) parseURL(rawURL string) (*url.URL, error) {
+	if rawURL == "" {
+		return nil, fmt.Errorf("empty URL")
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid URL: %w", fmt.Errorf("not a Gitea URL"))
+	}
+	if _, ok := p.hosts[strings.ToLower(u.Host)]; !ok {
+		return nil, fmt.Errorf("invalid URL: %w", fmt.Errorf("not a Gitea URL"))
+	}
+	return u, nil
+}
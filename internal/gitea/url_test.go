@@ -0,0 +1,98 @@
+package gitea
+
+import (
+	"testing"
+
+	"github.com/bigwhite/issue2md/internal/parser"
+)
+
+func TestURLParserParse(t *testing.T) {
+	p := NewURLParser("git.example.com")
+
+	tests := []struct {
+		name    string
+		url     string
+		want    parser.ResourceURL
+		wantErr bool
+	}{
+		{
+			name: "issue",
+			url:  "https://git.example.com/team/app/issues/12",
+			want: parser.ResourceURL{Host: "git.example.com", Owner: "team", Repo: "app", Number: 12, Type: "issue"},
+		},
+		{
+			name: "pull request",
+			url:  "https://Git.Example.com/team/app/pulls/7",
+			want: parser.ResourceURL{Host: "git.example.com", Owner: "team", Repo: "app", Number: 7, Type: "pull"},
+		},
+		{name: "unconfigured host", url: "https://codeberg.org/team/app/issues/1", wantErr: true},
+		{name: "GitHub pull path", url: "https://git.example.com/team/app/pull/7", wantErr: true},
+		{name: "http scheme", url: "http://git.example.com/team/app/issues/1", wantErr: true},
+		{name: "extra path", url: "https://git.example.com/team/app/issues/1/files", wantErr: true},
+		{name: "invalid number", url: "https://git.example.com/team/app/issues/abc", wantErr: true},
+		{name: "empty", url: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := p.Parse(tt.url)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			tt.want.URL = tt.url
+			if *got != tt.want {
+				t.Errorf("Parse() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestURLParserParseSubPath(t *testing.T) {
+	p := NewURLParser("example.com", "git.example.com")
+	p.SetAPIURL("Example.com", "https://example.com/gitea/api/v1/")
+	p.SetAPIURL("git.example.com", "https://git.example.com")
+
+	tests := []struct {
+		name    string
+		url     string
+		want    parser.ResourceURL
+		wantErr bool
+	}{
+		{
+			name: "issue under sub path",
+			url:  "https://example.com/gitea/team/app/issues/12",
+			want: parser.ResourceURL{Host: "example.com", Owner: "team", Repo: "app", Number: 12, Type: "issue"},
+		},
+		{
+			name: "pull request under sub path",
+			url:  "https://example.com/gitea/team/app/pulls/7/",
+			want: parser.ResourceURL{Host: "example.com", Owner: "team", Repo: "app", Number: 7, Type: "pull"},
+		},
+		{
+			name: "root install",
+			url:  "https://git.example.com/team/app/issues/3",
+			want: parser.ResourceURL{Host: "git.example.com", Owner: "team", Repo: "app", Number: 3, Type: "issue"},
+		},
+		{name: "outside sub path", url: "https://example.com/team/app/issues/12", wantErr: true},
+		{name: "sub path prefix only", url: "https://example.com/gitea-old/team/app/issues/12", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := p.Parse(tt.url)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			tt.want.URL = tt.url
+			if *got != tt.want {
+				t.Errorf("Parse() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
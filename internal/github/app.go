@@ -0,0 +1,386 @@
+package github
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultAPIURL 公共GitHub的REST接口地址
+	defaultAPIURL = "https://api.github.com/"
+	// appJWTLifetime App JWT的有效期，GitHub允许的上限为10分钟
+	appJWTLifetime = 9 * time.Minute
+	// appJWTClockSkew 签发时间向前调整的量，抵消本地与服务端的时钟误差
+	appJWTClockSkew = time.Minute
+	// installationTokenRefreshSlack 安装令牌在过期前多久刷新
+	installationTokenRefreshSlack = 5 * time.Minute
+)
+
+// installationToken 缓存的安装令牌
+type installationToken struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// appFlight 一次进行中的App接口请求，同时需要相同结果的请求等待它结束并共享结果
+type appFlight struct {
+	done chan struct{}
+	err  error
+}
+
+// installation GitHub App的一个安装
+type installation struct {
+	ID      int64 `json:"id"`
+	Account struct {
+		Login string `json:"login"`
+	} `json:"account"`
+}
+
+// AppTransport 以GitHub App身份认证的http.RoundTripper
+// 它用App私钥签发JWT，换取请求所属账号（组织或用户）下安装的安装令牌，并在令牌过期前自动刷新。
+// 账号从请求路径（/repos/{owner}/...）或GraphQL变量owner中识别，识别不到时使用Owner。
+// 使用方式: NewClientWithHTTPClient(&http.Client{Transport: appTransport}, "")
+type AppTransport struct {
+	// Base 实际发送请求的RoundTripper，为nil时使用http.DefaultTransport
+	Base http.RoundTripper
+	// AppID GitHub App的ID
+	AppID int64
+	// InstallationID 固定使用的安装，为0时按账号查找
+	InstallationID int64
+	// Owner 无法从请求中识别账号时使用的默认账号
+	Owner string
+	// BaseURL 换取令牌使用的REST接口地址，为空时使用 https://api.github.com/
+	BaseURL string
+
+	key *rsa.PrivateKey
+	// mu 保护下面的字段，换取令牌和列出安装的网络请求不持有mu
+	mu            sync.Mutex
+	installations map[string]int64
+	tokens        map[int64]installationToken
+	flights       map[string]*appFlight
+	now           func() time.Time
+}
+
+// NewAppTransport 创建以GitHub App身份认证的RoundTripper
+// privateKeyPEM为App私钥，支持PKCS#1和PKCS#8格式的PEM
+func NewAppTransport(base http.RoundTripper, appID int64, privateKeyPEM []byte) (*AppTransport, error) {
+	key, err := parseAppPrivateKey(privateKeyPEM)
+	if err != nil {
+		return nil, err
+	}
+	return &AppTransport{
+		Base:    base,
+		AppID:   appID,
+		key:     key,
+		tokens:  make(map[int64]installationToken),
+		flights: make(map[string]*appFlight),
+		now:     time.Now,
+	}, nil
+}
+
+// NewAppTransportFromFile 从私钥文件创建以GitHub App身份认证的RoundTripper
+func NewAppTransportFromFile(base http.RoundTripper, appID int64, privateKeyPath string) (*AppTransport, error) {
+	data, err := os.ReadFile(privateKeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read app private key: %w", err)
+	}
+	return NewAppTransport(base, appID, data)
+}
+
+// RoundTrip 实现http.RoundTripper接口
+func (t *AppTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.token(req)
+	if err != nil {
+		return nil, err
+	}
+
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "token "+token)
+	return t.base().RoundTrip(req)
+}
+
+// base 返回实际发送请求的RoundTripper
+func (t *AppTransport) base() http.RoundTripper {
+	if t.Base != nil {
+		return t.Base
+	}
+	return http.DefaultTransport
+}
+
+// token 返回请求所属安装的有效令牌，必要时换取新令牌
+// 同一安装同时只换取一次，其余请求等待换取结束后使用新令牌，其他安装的请求不受影响
+func (t *AppTransport) token(req *http.Request) (string, error) {
+	owner := requestOwner(req)
+	if owner == "" {
+		owner = t.Owner
+	}
+
+	id, err := t.installationID(req, owner)
+	if err != nil {
+		return "", err
+	}
+
+	var token string
+	fresh := func() bool {
+		cached, ok := t.tokens[id]
+		token = cached.Token
+		return ok && t.now().Add(installationTokenRefreshSlack).Before(cached.ExpiresAt)
+	}
+	err = t.share(req, fmt.Sprintf("token/%d", id), fresh, func() error {
+		var created installationToken
+		if err := t.appRequest(req, http.MethodPost, fmt.Sprintf("app/installations/%d/access_tokens", id), &created); err != nil {
+			return fmt.Errorf("failed to create installation token: %w", err)
+		}
+		t.mu.Lock()
+		t.tokens[id] = created
+		t.mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	fresh()
+	return token, nil
+}
+
+// installationID 返回账号对应的安装ID，首次调用时列出App的全部安装
+func (t *AppTransport) installationID(req *http.Request, owner string) (int64, error) {
+	if t.InstallationID != 0 {
+		return t.InstallationID, nil
+	}
+
+	listed := func() bool { return t.installations != nil }
+	err := t.share(req, "installations", listed, func() error {
+		installations := make(map[string]int64)
+		for page := 1; ; page++ {
+			var batch []installation
+			if err := t.appRequest(req, http.MethodGet, fmt.Sprintf("app/installations?per_page=100&page=%d", page), &batch); err != nil {
+				return fmt.Errorf("failed to list app installations: %w", err)
+			}
+			for _, inst := range batch {
+				installations[strings.ToLower(inst.Account.Login)] = inst.ID
+			}
+			if len(batch) < 100 {
+				break
+			}
+		}
+		t.mu.Lock()
+		t.installations = installations
+		t.mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if owner == "" {
+		// 只有一个安装时无需指定账号
+		if len(t.installations) == 1 {
+			for _, id := range t.installations {
+				return id, nil
+			}
+		}
+		return 0, errors.New("cannot determine which app installation to use: owner unknown")
+	}
+
+	id, ok := t.installations[strings.ToLower(owner)]
+	if !ok {
+		return 0, fmt.Errorf("github app %d is not installed on %s", t.AppID, owner)
+	}
+	return id, nil
+}
+
+// share 在不持有t.mu的情况下执行fetch，同一key同时只执行一次
+// ready在持有t.mu时调用，返回true表示结果已经可用、无需请求；fetch自行在持有t.mu时保存结果。
+// 已有相同key的请求在进行时等待它结束，它失败时返回同样的错误，请求被取消时返回上下文的错误
+func (t *AppTransport) share(req *http.Request, key string, ready func() bool, fetch func() error) error {
+	for {
+		t.mu.Lock()
+		if ready() {
+			t.mu.Unlock()
+			return nil
+		}
+		flight, ok := t.flights[key]
+		if !ok {
+			flight = &appFlight{done: make(chan struct{})}
+			t.flights[key] = flight
+			t.mu.Unlock()
+
+			flight.err = fetch()
+			t.mu.Lock()
+			delete(t.flights, key)
+			t.mu.Unlock()
+			close(flight.done)
+			return flight.err
+		}
+		t.mu.Unlock()
+
+		select {
+		case <-flight.done:
+			if flight.err != nil {
+				return flight.err
+			}
+		case <-req.Context().Done():
+			return req.Context().Err()
+		}
+	}
+}
+
+// appRequest 以App JWT身份调用REST接口
+func (t *AppTransport) appRequest(orig *http.Request, method, path string, out interface{}) error {
+	jwt, err := t.jwt()
+	if err != nil {
+		return err
+	}
+
+	baseURL := t.BaseURL
+	if baseURL == "" {
+		baseURL = defaultAPIURL
+	}
+	if !strings.HasSuffix(baseURL, "/") {
+		baseURL += "/"
+	}
+
+	req, err := http.NewRequestWithContext(orig.Context(), method, baseURL+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+jwt)
+
+	resp, err := t.base().RoundTrip(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		json.Unmarshal(body, &apiErr)
+		return fmt.Errorf("%s %s: %s %s", method, req.URL.Path, resp.Status, apiErr.Message)
+	}
+	return json.Unmarshal(body, out)
+}
+
+// jwt 签发用于App身份认证的RS256 JWT
+func (t *AppTransport) jwt() (string, error) {
+	now := t.now()
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RS256","typ":"JWT"}`))
+	claims, err := json.Marshal(map[string]interface{}{
+		"iat": now.Add(-appJWTClockSkew).Unix(),
+		"exp": now.Add(appJWTLifetime).Unix(),
+		"iss": strconv.FormatInt(t.AppID, 10),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	unsigned := header + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, t.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign app jwt: %w", err)
+	}
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// parseAppPrivateKey 解析PEM格式的RSA私钥
+func parseAppPrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("invalid app private key: no PEM block found")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid app private key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("invalid app private key: not an RSA key")
+	}
+	return key, nil
+}
+
+// requestOwner 识别请求所属的账号
+// REST请求取 /repos/{owner}、/orgs/{owner}、/users/{owner} 路径段，搜索请求取查询中的 repo:、org:、user: 限定，
+// GraphQL请求取变量owner；无法识别时返回空字符串
+func requestOwner(req *http.Request) string {
+	segments := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+	for i, segment := range segments {
+		switch segment {
+		case "repos", "orgs", "users":
+			if i+1 < len(segments) {
+				return segments[i+1]
+			}
+		case "search":
+			return searchOwner(req.URL.Query().Get("q"))
+		case "graphql":
+			return graphQLOwner(req)
+		}
+	}
+	return ""
+}
+
+// searchOwner 从搜索查询中提取账号
+func searchOwner(query string) string {
+	for _, term := range strings.Fields(query) {
+		for _, prefix := range []string{"repo:", "org:", "user:"} {
+			if strings.HasPrefix(term, prefix) {
+				return strings.SplitN(strings.TrimPrefix(term, prefix), "/", 2)[0]
+			}
+		}
+	}
+	return ""
+}
+
+// graphQLOwner 从GraphQL请求正文的变量中提取账号
+func graphQLOwner(req *http.Request) string {
+	if req.GetBody == nil {
+		return ""
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return ""
+	}
+	defer body.Close()
+
+	var payload struct {
+		Variables struct {
+			Owner string `json:"owner"`
+		} `json:"variables"`
+	}
+	if err := json.NewDecoder(body).Decode(&payload); err != nil {
+		return ""
+	}
+	return payload.Variables.Owner
+}
@@ -0,0 +1,310 @@
+package github
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// appMockServer 模拟GitHub App相关接口的服务器
+type appMockServer struct {
+	*httptest.Server
+	mu            sync.Mutex
+	tokensIssued  map[int64]int
+	listCalls     int
+	authorization []string
+}
+
+// createAppMockServer 创建App接口模拟服务器
+// 安装42属于octo-org，安装7属于alice；资源接口记录收到的Authorization头
+func createAppMockServer(t *testing.T, key *rsa.PrivateKey, expiresAt time.Time) *appMockServer {
+	s := &appMockServer{tokensIssued: make(map[int64]int)}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		if strings.HasPrefix(r.URL.Path, "/app/") {
+			if err := verifyAppJWT(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "), &key.PublicKey); err != nil {
+				t.Errorf("invalid app jwt: %v", err)
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+		}
+
+		switch {
+		case r.URL.Path == "/app/installations":
+			s.listCalls++
+			if r.URL.Query().Get("page") != "1" {
+				w.Write([]byte(`[]`))
+				return
+			}
+			w.Write([]byte(`[{"id": 42, "account": {"login": "octo-org"}}, {"id": 7, "account": {"login": "alice"}}]`))
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/access_tokens"):
+			var id int64
+			fmt.Sscanf(r.URL.Path, "/app/installations/%d/access_tokens", &id)
+			if id != 42 && id != 7 {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"message": "Not Found"}`))
+				return
+			}
+			s.tokensIssued[id]++
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"token":      fmt.Sprintf("ghs_%d_%d", id, s.tokensIssued[id]),
+				"expires_at": expiresAt,
+			})
+		default:
+			s.authorization = append(s.authorization, r.Header.Get("Authorization"))
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"number": 1, "title": "App issue", "state": "open", "user": {"login": "bot"}}`))
+		}
+	}))
+	return s
+}
+
+// verifyAppJWT 校验App JWT的签名和声明
+func verifyAppJWT(token string, pub *rsa.PublicKey) error {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return fmt.Errorf("malformed jwt %q", token)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return err
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest[:], signature); err != nil {
+		return err
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return err
+	}
+	var claims struct {
+		Iat int64  `json:"iat"`
+		Exp int64  `json:"exp"`
+		Iss string `json:"iss"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return err
+	}
+	if claims.Iss != "1234" || claims.Exp-claims.Iat > 600 {
+		return fmt.Errorf("unexpected claims %+v", claims)
+	}
+	return nil
+}
+
+func TestAppTransport(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkcs1 := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	pkcs8Bytes, _ := x509.MarshalPKCS8PrivateKey(key)
+	pkcs8 := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8Bytes})
+
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		pem       []byte
+		owners    []string
+		advance   time.Duration
+		wantAuth  []string
+		wantLists int
+		wantErr   string
+	}{
+		{
+			name:      "Installation selected by owner",
+			pem:       pkcs1,
+			owners:    []string{"octo-org", "Alice", "octo-org"},
+			wantAuth:  []string{"token ghs_42_1", "token ghs_7_1", "token ghs_42_1"},
+			wantLists: 1,
+		},
+		{
+			name:      "PKCS8 key",
+			pem:       pkcs8,
+			owners:    []string{"alice"},
+			wantAuth:  []string{"token ghs_7_1"},
+			wantLists: 1,
+		},
+		{
+			name:      "Token refreshed before expiry",
+			pem:       pkcs1,
+			owners:    []string{"octo-org", "octo-org"},
+			advance:   56 * time.Minute,
+			wantAuth:  []string{"token ghs_42_1", "token ghs_42_2"},
+			wantLists: 1,
+		},
+		{
+			name:    "Not installed",
+			pem:     pkcs1,
+			owners:  []string{"stranger"},
+			wantErr: "not installed on stranger",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := createAppMockServer(t, key, start.Add(time.Hour))
+			defer server.Close()
+
+			transport, err := NewAppTransport(server.Client().Transport, 1234, tt.pem)
+			if err != nil {
+				t.Fatalf("NewAppTransport() unexpected error = %v", err)
+			}
+			transport.BaseURL = server.URL
+			now := start
+			transport.now = func() time.Time { return now }
+
+			client := NewClientWithHTTPClient(&http.Client{Transport: transport}, "")
+			client.Client.BaseURL, _ = url.Parse(server.URL + "/")
+
+			for i, owner := range tt.owners {
+				if i > 0 {
+					now = now.Add(tt.advance)
+				}
+				_, err := client.GetIssue(context.Background(), owner, "repo", 1)
+				if tt.wantErr != "" {
+					if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+						t.Fatalf("GetIssue() error = %v, want containing %q", err, tt.wantErr)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatalf("GetIssue(%s) unexpected error = %v", owner, err)
+				}
+			}
+
+			if strings.Join(server.authorization, ",") != strings.Join(tt.wantAuth, ",") {
+				t.Errorf("Authorization headers = %v, want %v", server.authorization, tt.wantAuth)
+			}
+			if server.listCalls != tt.wantLists {
+				t.Errorf("installation list calls = %d, want %d", server.listCalls, tt.wantLists)
+			}
+		})
+	}
+}
+
+func TestAppTransportConcurrentExchange(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkcs1 := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	// 安装42的令牌换取阻塞到release关闭，安装7立即返回
+	var exchanges sync.Map
+	exchanging, release := make(chan struct{}), make(chan struct{})
+	var once sync.Once
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/app/installations":
+			w.Write([]byte(`[{"id": 42, "account": {"login": "octo-org"}}, {"id": 7, "account": {"login": "alice"}}]`))
+		case strings.HasSuffix(r.URL.Path, "/access_tokens"):
+			var id int64
+			fmt.Sscanf(r.URL.Path, "/app/installations/%d/access_tokens", &id)
+			count, _ := exchanges.LoadOrStore(id, new(int32))
+			atomic.AddInt32(count.(*int32), 1)
+			if id == 42 {
+				once.Do(func() { close(exchanging) })
+				<-release
+			}
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(map[string]interface{}{"token": fmt.Sprintf("ghs_%d", id), "expires_at": time.Now().Add(time.Hour)})
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"number": 1, "title": "App issue", "state": "open"}`))
+		}
+	}))
+	defer server.Close()
+
+	transport, err := NewAppTransport(server.Client().Transport, 1234, pkcs1)
+	if err != nil {
+		t.Fatalf("NewAppTransport() unexpected error = %v", err)
+	}
+	transport.BaseURL = server.URL
+	client := NewClientWithHTTPClient(&http.Client{Transport: transport}, "")
+	client.Client.BaseURL, _ = url.Parse(server.URL + "/")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.GetIssue(context.Background(), "octo-org", "repo", 1); err != nil {
+				t.Errorf("GetIssue(octo-org) unexpected error = %v", err)
+			}
+		}()
+	}
+	<-exchanging
+
+	// 安装42的换取进行中时，其他安装的请求不被阻塞
+	done := make(chan error, 1)
+	go func() {
+		_, err := client.GetIssue(context.Background(), "alice", "repo", 1)
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("GetIssue(alice) unexpected error = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("GetIssue(alice) blocked behind another installation's token exchange")
+	}
+
+	close(release)
+	wg.Wait()
+	if count, _ := exchanges.Load(int64(42)); count == nil || atomic.LoadInt32(count.(*int32)) != 1 {
+		t.Errorf("installation 42 token exchanges = %v, want 1", count)
+	}
+}
+
+func TestParseAppPrivateKeyInvalid(t *testing.T) {
+	if _, err := NewAppTransport(nil, 1, []byte("not a key")); err == nil {
+		t.Error("NewAppTransport() expected error for invalid PEM, but got nil")
+	}
+}
+
+func TestRequestOwner(t *testing.T) {
+	newRequest := func(method, rawURL, body string) *http.Request {
+		req, _ := http.NewRequest(method, rawURL, strings.NewReader(body))
+		return req
+	}
+
+	tests := []struct {
+		name string
+		req  *http.Request
+		want string
+	}{
+		{name: "Repository path", req: newRequest(http.MethodGet, "https://api.github.com/repos/octo-org/repo/issues/1", ""), want: "octo-org"},
+		{name: "Enterprise path", req: newRequest(http.MethodGet, "https://ghe.example.com/api/v3/repos/team/app/pulls/2", ""), want: "team"},
+		{name: "Search query", req: newRequest(http.MethodGet, "https://api.github.com/search/issues?q=is%3Aopen+repo%3Aalice%2Frepo", ""), want: "alice"},
+		{name: "GraphQL variables", req: newRequest(http.MethodPost, "https://api.github.com/graphql", `{"query": "q", "variables": {"owner": "bob"}}`), want: "bob"},
+		{name: "Unknown", req: newRequest(http.MethodGet, "https://api.github.com/rate_limit", ""), want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := requestOwner(tt.req); got != tt.want {
+				t.Errorf("requestOwner() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
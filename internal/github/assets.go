@@ -0,0 +1,83 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/bigwhite/issue2md/internal/model"
+)
+
+// DownloadAsset 下载Issue、Pull Request正文或评论中引用的附件和图片
+// 只有发往GitHub主机（github.com或Enterprise Server主机）的请求附带令牌；
+// 附件地址通常重定向到带签名的存储地址，跟随重定向时不再附带令牌
+func (c *GitHubClient) DownloadAsset(ctx context.Context, rawURL string) (*model.Asset, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid asset URL %q: %w", rawURL, err)
+	}
+
+	// c.Cache是认证层之下的传输链，经过它的请求不带令牌
+	anonymous := &http.Client{Transport: c.Cache}
+	httpClient := anonymous
+	if strings.EqualFold(u.Host, c.webHost()) {
+		httpClient = c.Client.Client()
+		httpClient.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+	}
+
+	resp, err := getAsset(ctx, httpClient, rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if location := resp.Header.Get("Location"); resp.StatusCode >= 300 && resp.StatusCode < 400 && location != "" {
+		resp.Body.Close()
+		next, err := u.Parse(location)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redirect for asset %s: %w", rawURL, err)
+		}
+		if resp, err = getAsset(ctx, anonymous, next.String()); err != nil {
+			return nil, err
+		}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download asset %s: %s", rawURL, resp.Status)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download asset %s: %w", rawURL, err)
+	}
+	return &model.Asset{Data: data, ContentType: resp.Header.Get("Content-Type")}, nil
+}
+
+// getAsset 发送下载附件的GET请求
+func getAsset(ctx context.Context, httpClient *http.Client, rawURL string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create asset request: %w", err)
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download asset %s: %w", rawURL, err)
+	}
+	return resp, nil
+}
+
+// webHost 返回客户端对应的网页主机，附件地址位于该主机上
+// github.com的接口主机为api.github.com，Enterprise Server的接口与网页在同一主机
+func (c *GitHubClient) webHost() string {
+	if c.Client.BaseURL == nil {
+		return "github.com"
+	}
+	host := c.Client.BaseURL.Host
+	if strings.EqualFold(host, "api.github.com") {
+		return "github.com"
+	}
+	return host
+}
@@ -0,0 +1,66 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestDownloadAsset(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user-attachments/assets/abc", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			t.Errorf("attachment Authorization = %q, want token", r.Header.Get("Authorization"))
+		}
+		http.Redirect(w, r, "/storage/abc.png?jwt=signed", http.StatusFound)
+	})
+	mux.HandleFunc("/storage/abc.png", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "" {
+			t.Errorf("signed storage request carries Authorization %q", r.Header.Get("Authorization"))
+		}
+		w.Header().Set("Content-Type", "image/png")
+		w.Write([]byte("PNG"))
+	})
+	mux.HandleFunc("/storage/gone.png", func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := NewClientWithHTTPClient(server.Client(), "test-token")
+	client.Client.BaseURL, _ = url.Parse(server.URL + "/")
+	client.Retrier.Policy.MaxAttempts = 1
+
+	asset, err := client.DownloadAsset(context.Background(), server.URL+"/user-attachments/assets/abc")
+	if err != nil {
+		t.Fatalf("DownloadAsset() unexpected error = %v", err)
+	}
+	if string(asset.Data) != "PNG" || asset.ContentType != "image/png" {
+		t.Errorf("DownloadAsset() = %q %q, want PNG image/png", asset.Data, asset.ContentType)
+	}
+
+	if _, err := client.DownloadAsset(context.Background(), server.URL+"/storage/gone.png"); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("DownloadAsset() error = %v, want 404", err)
+	}
+}
+
+func TestWebHost(t *testing.T) {
+	tests := []struct {
+		baseURL string
+		want    string
+	}{
+		{baseURL: "https://api.github.com/", want: "github.com"},
+		{baseURL: "https://git.example.com/api/v3/", want: "git.example.com"},
+	}
+
+	for _, tt := range tests {
+		client := NewClient("")
+		client.Client.BaseURL, _ = url.Parse(tt.baseURL)
+		if got := client.webHost(); got != tt.want {
+			t.Errorf("webHost(%q) = %q, want %q", tt.baseURL, got, tt.want)
+		}
+	}
+}
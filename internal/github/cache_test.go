@@ -0,0 +1,41 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/bigwhite/issue2md/internal/transport"
+)
+
+func TestGetDiscussionOffline(t *testing.T) {
+	server := createGraphQLMockServer(t)
+
+	online := NewClientWithHTTPClient(server.Client(), "test-token")
+	online.Client.BaseURL, _ = url.Parse(server.URL + "/")
+	online.Cache.Dir = t.TempDir()
+
+	if _, err := online.GetDiscussion(context.Background(), "testowner", "testrepo", 543); err != nil {
+		t.Fatalf("GetDiscussion() online unexpected error = %v", err)
+	}
+
+	// 关闭服务器后，不带令牌的离线客户端仍能从缓存读取GraphQL查询结果
+	server.Close()
+	client := NewClientWithHTTPClient(server.Client(), "")
+	client.Client.BaseURL = online.Client.BaseURL
+	client.Cache.Dir = online.Cache.Dir
+	client.Cache.Mode = transport.CacheOffline
+	got, err := client.GetDiscussion(context.Background(), "testowner", "testrepo", 543)
+	if err != nil {
+		t.Fatalf("GetDiscussion() offline unexpected error = %v", err)
+	}
+	if len(got.Comments) != 2 || got.Answer == nil {
+		t.Errorf("GetDiscussion() offline = %+v, want 2 comments and an answer", got)
+	}
+
+	_, err = client.GetDiscussion(context.Background(), "testowner", "testrepo", 1)
+	if !errors.Is(err, transport.ErrNotCached) {
+		t.Errorf("GetDiscussion() offline miss error = %v, want ErrNotCached", err)
+	}
+}
@@ -0,0 +1,87 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bigwhite/issue2md/internal/transport"
+)
+
+// newCassetteClient 创建经过磁带的客户端，baseURL为录制时的服务器地址
+func newCassetteClient(t *testing.T, base http.RoundTripper, baseURL, dir string, mode transport.CassetteMode) *GitHubClient {
+	transport, err := transport.NewCassetteTransport(base, dir, mode)
+	if err != nil {
+		t.Fatalf("NewCassetteTransport() unexpected error = %v", err)
+	}
+	client := NewClientWithHTTPClient(&http.Client{Transport: transport}, "secret-token")
+	client.Client.BaseURL, _ = url.Parse(baseURL + "/")
+	return client
+}
+
+func TestCassetteRecordReplay(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "cassette")
+	server := createMockServer()
+	gqlServer := createGraphQLMockServer(t)
+
+	// 录制: REST分页请求和GraphQL请求
+	recorder := newCassetteClient(t, server.Client().Transport, server.URL, dir, transport.CassetteRecord)
+	wantComments, err := recorder.GetIssueComments(context.Background(), "testowner", "testrepo", 456)
+	if err != nil {
+		t.Fatalf("GetIssueComments() record unexpected error = %v", err)
+	}
+	if _, err := recorder.GetIssue(context.Background(), "errorowner", "errorrepo", 999); err == nil {
+		t.Fatal("GetIssue() record expected 404 error, but got nil")
+	}
+	gqlRecorder := newCassetteClient(t, gqlServer.Client().Transport, gqlServer.URL, dir, transport.CassetteRecord)
+	wantDiscussion, err := gqlRecorder.GetDiscussion(context.Background(), "testowner", "testrepo", 543)
+	if err != nil {
+		t.Fatalf("GetDiscussion() record unexpected error = %v", err)
+	}
+	server.Close()
+	gqlServer.Close()
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if len(files) < 5 {
+		t.Fatalf("recorded %d interactions, want at least 5", len(files))
+	}
+	for _, file := range files {
+		data, _ := os.ReadFile(file)
+		if strings.Contains(string(data), "secret-token") {
+			t.Errorf("%s contains the token", filepath.Base(file))
+		}
+	}
+
+	// 回放: 服务器已关闭，结果与录制时一致
+	player := newCassetteClient(t, nil, server.URL, dir, transport.CassetteReplay)
+	gotComments, err := player.GetIssueComments(context.Background(), "testowner", "testrepo", 456)
+	if err != nil {
+		t.Fatalf("GetIssueComments() replay unexpected error = %v", err)
+	}
+	if len(gotComments) != len(wantComments) {
+		t.Errorf("GetIssueComments() replay returned %d comments, want %d", len(gotComments), len(wantComments))
+	}
+	if _, err := player.GetIssue(context.Background(), "errorowner", "errorrepo", 999); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("GetIssue() replay error = %v, want recorded 404", err)
+	}
+
+	gqlPlayer := newCassetteClient(t, nil, gqlServer.URL, dir, transport.CassetteReplay)
+	gotDiscussion, err := gqlPlayer.GetDiscussion(context.Background(), "testowner", "testrepo", 543)
+	if err != nil {
+		t.Fatalf("GetDiscussion() replay unexpected error = %v", err)
+	}
+	if gotDiscussion.Title != wantDiscussion.Title || len(gotDiscussion.Comments) != len(wantDiscussion.Comments) {
+		t.Errorf("GetDiscussion() replay = %+v, want %+v", gotDiscussion, wantDiscussion)
+	}
+
+	// 未录制的请求直接失败
+	_, err = player.GetIssue(context.Background(), "testowner", "testrepo", 1)
+	if !errors.Is(err, transport.ErrNotRecorded) {
+		t.Errorf("GetIssue() unrecorded error = %v, want ErrNotRecorded", err)
+	}
+}
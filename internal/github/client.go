@@ -3,13 +3,16 @@ package github
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/go-github/v56/github"
+
+	"github.com/bigwhite/issue2md/internal/model"
 )
 
 // GetIssue 获取Issue信息
-func (c *GitHubClient) GetIssue(ctx context.Context, owner, repo string, issueNumber int) (*Issue, error) {
+func (c *GitHubClient) GetIssue(ctx context.Context, owner, repo string, issueNumber int) (*model.Issue, error) {
 	// 调用 GitHub API 获取 Issue
 	gitHubIssue, _, err := c.Client.Issues.Get(ctx, owner, repo, issueNumber)
 	if err != nil {
@@ -21,95 +24,99 @@ func (c *GitHubClient) GetIssue(ctx context.Context, owner, repo string, issueNu
 	return issue, nil
 }
 
-// GetIssueComments 获取Issue评论
-func (c *GitHubClient) GetIssueComments(ctx context.Context, owner, repo string, issueNumber int) ([]*Comment, error) {
-	// 调用 GitHub API 获取 Issue 评论列表
-	gitHubComments, _, err := c.Client.Issues.ListComments(ctx, owner, repo, issueNumber, nil)
+// defaultPerPage GitHub REST API 单页允许的最大条目数
+const defaultPerPage = 100
+
+// commentPageConcurrency 并发获取评论页的最大数量
+const commentPageConcurrency = 4
+
+// GetIssueComments 获取Issue的全部评论
+// 第一页的响应给出总页数后，其余页最多commentPageConcurrency个并发获取并按页序拼接，
+// 任一页失败会取消其余请求；响应不带总页数时逐页获取。评论较多时建议使用 IssueComments 流式遍历
+func (c *GitHubClient) GetIssueComments(ctx context.Context, owner, repo string, issueNumber int) ([]*model.Comment, error) {
+	comments, resp, err := c.issueCommentsPage(ctx, owner, repo, issueNumber, 1)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get comments for issue %d from %s/%s: %w", issueNumber, owner, repo, err)
+		return nil, err
 	}
 
-	// 转换为内部结构
-	var comments []*Comment
-	if gitHubComments != nil {
-		for _, gitHubComment := range gitHubComments {
-			if gitHubComment != nil {
-				comment := convertGitHubComment(gitHubComment)
-				comments = append(comments, comment)
+	if resp.NextPage == 0 {
+		return comments, nil
+	}
+	if resp.LastPage == 0 {
+		for page := resp.NextPage; page != 0; page = resp.NextPage {
+			var more []*model.Comment
+			if more, resp, err = c.issueCommentsPage(ctx, owner, repo, issueNumber, page); err != nil {
+				return nil, err
 			}
+			comments = append(comments, more...)
 		}
+		return comments, nil
 	}
 
-	return comments, nil
-}
-
-// convertGitHubIssue 将GitHub API的Issue转换为内部Issue结构
-func convertGitHubIssue(gitHubIssue *github.Issue) *Issue {
-	if gitHubIssue == nil {
-		return nil
+	pages := make([][]*model.Comment, resp.LastPage-resp.NextPage+1)
+	g, groupCtx := NewGroup(ctx, commentPageConcurrency)
+	for i := range pages {
+		if groupCtx.Err() != nil {
+			break
+		}
+		i := i
+		g.Go(func() error {
+			page, _, err := c.issueCommentsPage(groupCtx, owner, repo, issueNumber, resp.NextPage+i)
+			pages[i] = page
+			return err
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
 	}
 
-	var labels []Label
-	if gitHubIssue.Labels != nil {
-		for _, label := range gitHubIssue.Labels {
-			if label != nil {
-				l := Label{
-					Name:        label.GetName(),
-					Color:       label.GetColor(),
-					Description: label.GetDescription(),
-				}
-				labels = append(labels, l)
-			}
-		}
+	for _, page := range pages {
+		comments = append(comments, page...)
 	}
+	return comments, nil
+}
 
-	var assignees []User
-	if gitHubIssue.Assignees != nil {
-		for _, assignee := range gitHubIssue.Assignees {
-			if assignee != nil {
-				a := User{
-					Login:     assignee.GetLogin(),
-					ID:        assignee.GetID(),
-					AvatarURL: assignee.GetAvatarURL(),
-					HTMLURL:   assignee.GetHTMLURL(),
-					Type:      assignee.GetType(),
-				}
-				assignees = append(assignees, a)
-			}
+// IssueComments 返回Issue评论的流式迭代器
+// 迭代器按页向GitHub请求评论，内存中只保留当前页
+func (c *GitHubClient) IssueComments(owner, repo string, issueNumber int) *model.CommentIterator {
+	return model.NewCommentIterator(func(ctx context.Context, page int) ([]*model.Comment, int, error) {
+		comments, resp, err := c.issueCommentsPage(ctx, owner, repo, issueNumber, page)
+		if err != nil {
+			return nil, 0, err
 		}
+		return comments, resp.NextPage, nil
+	})
+}
+
+// issueCommentsPage 获取一页Issue评论
+func (c *GitHubClient) issueCommentsPage(ctx context.Context, owner, repo string, issueNumber, page int) ([]*model.Comment, *github.Response, error) {
+	opts := &github.IssueListCommentsOptions{
+		ListOptions: github.ListOptions{Page: page, PerPage: defaultPerPage},
 	}
 
-	var milestone *Milestone
-	if gitHubIssue.Milestone != nil {
-		var dueDate, closedAt *time.Time
-		if gitHubIssue.Milestone.DueOn != nil {
-			dueDate = &gitHubIssue.Milestone.DueOn.Time
-		}
-		if gitHubIssue.Milestone.ClosedAt != nil {
-			closedAt = &gitHubIssue.Milestone.ClosedAt.Time
-		}
+	// 调用 GitHub API 获取一页 Issue 评论
+	gitHubComments, resp, err := c.Client.Issues.ListComments(ctx, owner, repo, issueNumber, opts)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get comments for issue %d from %s/%s: %w", issueNumber, owner, repo, err)
+	}
 
-		milestone = &Milestone{
-			Title:       gitHubIssue.Milestone.GetTitle(),
-			Number:      gitHubIssue.Milestone.GetNumber(),
-			State:       gitHubIssue.Milestone.GetState(),
-			Description: gitHubIssue.Milestone.GetDescription(),
-			CreatedAt:   gitHubIssue.Milestone.GetCreatedAt().Time,
-			UpdatedAt:   gitHubIssue.Milestone.GetUpdatedAt().Time,
-			DueDate:     dueDate,
-			ClosedAt:    closedAt,
+	// 转换为内部结构
+	var comments []*model.Comment
+	for _, gitHubComment := range gitHubComments {
+		if gitHubComment != nil {
+			comments = append(comments, convertGitHubComment(gitHubComment))
 		}
 	}
+	return comments, resp, nil
+}
 
-	var user User
-	if gitHubIssue.User != nil {
-		user = User{
-			Login:     gitHubIssue.User.GetLogin(),
-			ID:        gitHubIssue.User.GetID(),
-			AvatarURL: gitHubIssue.User.GetAvatarURL(),
-			HTMLURL:   gitHubIssue.User.GetHTMLURL(),
-			Type:      gitHubIssue.User.GetType(),
-		}
+// convertGitHubIssue 将GitHub API的Issue转换为内部Issue结构
+func convertGitHubIssue(gitHubIssue *github.Issue) *model.Issue {
+	if gitHubIssue == nil {
+		return nil
 	}
 
 	var closedAt *time.Time
@@ -117,47 +124,136 @@ func convertGitHubIssue(gitHubIssue *github.Issue) *Issue {
 		closedAt = &gitHubIssue.ClosedAt.Time
 	}
 
-	return &Issue{
-		Number:    gitHubIssue.GetNumber(),
-		Title:     gitHubIssue.GetTitle(),
-		Body:      gitHubIssue.GetBody(),
-		State:     gitHubIssue.GetState(),
-		User:      user,
-		Labels:    labels,
-		Assignees: assignees,
-		Milestone: milestone,
-		CreatedAt: gitHubIssue.GetCreatedAt().Time,
-		UpdatedAt: gitHubIssue.GetUpdatedAt().Time,
-		ClosedAt:  closedAt,
-		URL:       gitHubIssue.GetURL(),
-		HTMLURL:   gitHubIssue.GetHTMLURL(),
+	return &model.Issue{
+		Number:        gitHubIssue.GetNumber(),
+		Title:         gitHubIssue.GetTitle(),
+		Body:          gitHubIssue.GetBody(),
+		State:         gitHubIssue.GetState(),
+		User:          convertGitHubUser(gitHubIssue.User),
+		Labels:        convertGitHubLabels(gitHubIssue.Labels),
+		Assignees:     convertGitHubUsers(gitHubIssue.Assignees),
+		Milestone:     convertGitHubMilestone(gitHubIssue.Milestone),
+		CreatedAt:     gitHubIssue.GetCreatedAt().Time,
+		UpdatedAt:     gitHubIssue.GetUpdatedAt().Time,
+		ClosedAt:      closedAt,
+		URL:           gitHubIssue.GetURL(),
+		HTMLURL:       gitHubIssue.GetHTMLURL(),
+		Reactions:     convertGitHubReactions(gitHubIssue.Reactions),
+		IsPullRequest: gitHubIssue.IsPullRequest(),
+		Repository:    repositoryFromURL(gitHubIssue.GetRepositoryURL()),
+	}
+}
+
+// repositoryFromURL 从仓库的API地址中取出 owner/repo
+// 如 https://api.github.com/repos/owner/repo，GitHub Enterprise Server的地址带有 /api/v3 前缀
+func repositoryFromURL(apiURL string) string {
+	i := strings.LastIndex(apiURL, "/repos/")
+	if i < 0 {
+		return ""
 	}
+	return strings.Trim(apiURL[i+len("/repos/"):], "/")
 }
 
 // convertGitHubComment 将GitHub API的Comment转换为内部Comment结构
-func convertGitHubComment(gitHubComment *github.IssueComment) *Comment {
+func convertGitHubComment(gitHubComment *github.IssueComment) *model.Comment {
 	if gitHubComment == nil {
 		return nil
 	}
 
-	var user User
-	if gitHubComment.User != nil {
-		user = User{
-			Login:     gitHubComment.User.GetLogin(),
-			ID:        gitHubComment.User.GetID(),
-			AvatarURL: gitHubComment.User.GetAvatarURL(),
-			HTMLURL:   gitHubComment.User.GetHTMLURL(),
-			Type:      gitHubComment.User.GetType(),
-		}
-	}
-
-	return &Comment{
+	return &model.Comment{
 		ID:        gitHubComment.GetID(),
 		Body:      gitHubComment.GetBody(),
-		User:      user,
+		User:      convertGitHubUser(gitHubComment.User),
 		CreatedAt: gitHubComment.GetCreatedAt().Time,
 		UpdatedAt: gitHubComment.GetUpdatedAt().Time,
 		URL:       gitHubComment.GetURL(),
 		HTMLURL:   gitHubComment.GetHTMLURL(),
+		Reactions: convertGitHubReactions(gitHubComment.Reactions),
+	}
+}
+
+// convertGitHubReactions 将GitHub API的Reactions转换为内部Reactions结构
+func convertGitHubReactions(gitHubReactions *github.Reactions) model.Reactions {
+	if gitHubReactions == nil {
+		return model.Reactions{}
+	}
+
+	return model.Reactions{
+		TotalCount: gitHubReactions.GetTotalCount(),
+		ThumbsUp:   gitHubReactions.GetPlusOne(),
+		ThumbsDown: gitHubReactions.GetMinusOne(),
+		Laugh:      gitHubReactions.GetLaugh(),
+		Hooray:     gitHubReactions.GetHooray(),
+		Confused:   gitHubReactions.GetConfused(),
+		Heart:      gitHubReactions.GetHeart(),
+		Rocket:     gitHubReactions.GetRocket(),
+		Eyes:       gitHubReactions.GetEyes(),
+	}
+}
+
+// convertGitHubUser 将GitHub API的User转换为内部User结构
+func convertGitHubUser(gitHubUser *github.User) model.User {
+	if gitHubUser == nil {
+		return model.User{}
+	}
+
+	return model.User{
+		Login:     gitHubUser.GetLogin(),
+		ID:        gitHubUser.GetID(),
+		AvatarURL: gitHubUser.GetAvatarURL(),
+		HTMLURL:   gitHubUser.GetHTMLURL(),
+		Type:      gitHubUser.GetType(),
+	}
+}
+
+// convertGitHubUsers 转换用户列表，忽略空元素
+func convertGitHubUsers(gitHubUsers []*github.User) []model.User {
+	var users []model.User
+	for _, gitHubUser := range gitHubUsers {
+		if gitHubUser != nil {
+			users = append(users, convertGitHubUser(gitHubUser))
+		}
+	}
+	return users
+}
+
+// convertGitHubLabels 转换标签列表，忽略空元素
+func convertGitHubLabels(gitHubLabels []*github.Label) []model.Label {
+	var labels []model.Label
+	for _, label := range gitHubLabels {
+		if label != nil {
+			labels = append(labels, model.Label{
+				Name:        label.GetName(),
+				Color:       label.GetColor(),
+				Description: label.GetDescription(),
+			})
+		}
+	}
+	return labels
+}
+
+// convertGitHubMilestone 将GitHub API的Milestone转换为内部Milestone结构
+func convertGitHubMilestone(gitHubMilestone *github.Milestone) *model.Milestone {
+	if gitHubMilestone == nil {
+		return nil
+	}
+
+	var dueDate, closedAt *time.Time
+	if gitHubMilestone.DueOn != nil {
+		dueDate = &gitHubMilestone.DueOn.Time
+	}
+	if gitHubMilestone.ClosedAt != nil {
+		closedAt = &gitHubMilestone.ClosedAt.Time
+	}
+
+	return &model.Milestone{
+		Title:       gitHubMilestone.GetTitle(),
+		Number:      gitHubMilestone.GetNumber(),
+		State:       gitHubMilestone.GetState(),
+		Description: gitHubMilestone.GetDescription(),
+		CreatedAt:   gitHubMilestone.GetCreatedAt().Time,
+		UpdatedAt:   gitHubMilestone.GetUpdatedAt().Time,
+		DueDate:     dueDate,
+		ClosedAt:    closedAt,
 	}
 }
@@ -2,14 +2,19 @@ package github
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/google/go-github/v56/github"
+
+	"github.com/bigwhite/issue2md/internal/model"
 )
 
 // mockIssueJSON 模拟GitHub Issue API响应
@@ -60,6 +65,17 @@ const mockIssueJSON = `{
 		"due_on": "2023-03-01T00:00:00Z"
 	},
 	"comments": 5,
+	"reactions": {
+		"total_count": 6,
+		"+1": 3,
+		"-1": 0,
+		"laugh": 0,
+		"hooray": 1,
+		"confused": 0,
+		"heart": 2,
+		"rocket": 0,
+		"eyes": 0
+	},
 	"created_at": "2023-01-10T10:00:00Z",
 	"updated_at": "2023-01-20T15:30:00Z",
 	"closed_at": null,
@@ -79,6 +95,7 @@ const mockCommentsJSON = `[
 			"html_url": "https://github.com/commenter1",
 			"type": "User"
 		},
+		"reactions": {"total_count": 1, "rocket": 1},
 		"created_at": "2023-01-10T11:00:00Z",
 		"updated_at": "2023-01-10T11:00:00Z",
 		"url": "https://api.github.com/repos/testowner/testrepo/issues/comments/111111111",
@@ -119,6 +136,11 @@ func createMockServer() *httptest.Server {
 		w.Write([]byte(mockCommentsJSON))
 	})
 
+	// Mock paginated Comments API endpoint
+	mux.HandleFunc("/repos/testowner/testrepo/issues/456/comments", func(w http.ResponseWriter, r *http.Request) {
+		writePagedComments(w, r, 250)
+	})
+
 	// Mock error endpoint
 	mux.HandleFunc("/repos/errorowner/errorrepo/issues/999", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
@@ -132,6 +154,41 @@ func createMockServer() *httptest.Server {
 	return httptest.NewServer(mux)
 }
 
+// writePagedComments 按GitHub分页约定输出评论列表，并设置Link头指向下一页
+func writePagedComments(w http.ResponseWriter, r *http.Request, total int) {
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+	if perPage < 1 {
+		perPage = 30
+	}
+
+	start := (page - 1) * perPage
+	end := start + perPage
+	if end > total {
+		end = total
+	}
+
+	var items []string
+	for i := start; i < end; i++ {
+		items = append(items, fmt.Sprintf(`{"id": %d, "body": "comment %d", "user": {"login": "user%d"}}`, i+1, i+1, i+1))
+	}
+
+	if end < total {
+		next := *r.URL
+		query := next.Query()
+		query.Set("page", strconv.Itoa(page+1))
+		next.RawQuery = query.Encode()
+		w.Header().Set("Link", fmt.Sprintf(`<http://%s%s>; rel="next"`, r.Host, next.RequestURI()))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("[" + strings.Join(items, ",") + "]"))
+}
+
 func TestGetIssue(t *testing.T) {
 	// 创建模拟服务器
 	server := createMockServer()
@@ -246,6 +303,12 @@ func TestGetIssue(t *testing.T) {
 				}
 			}
 
+			// 验证Reactions
+			wantReactions := model.Reactions{TotalCount: 6, ThumbsUp: 3, Hooray: 1, Heart: 2}
+			if got.Reactions != wantReactions {
+				t.Errorf("GetIssue().Reactions = %+v, want %+v", got.Reactions, wantReactions)
+			}
+
 			// 验证Milestone
 			if got.Milestone == nil {
 				t.Error("GetIssue().Milestone = nil, expected Milestone")
@@ -332,6 +395,9 @@ func TestGetIssueComments(t *testing.T) {
 			if got[0].User.Login != "commenter1" {
 				t.Errorf("GetIssueComments()[0].User.Login = %v, want %v", got[0].User.Login, "commenter1")
 			}
+			if got[0].Reactions.Rocket != 1 || got[0].Reactions.TotalCount != 1 {
+				t.Errorf("GetIssueComments()[0].Reactions = %+v, want 1 rocket", got[0].Reactions)
+			}
 
 			// 验证第二条评论
 			if got[1].ID != 222222222 {
@@ -344,12 +410,194 @@ func TestGetIssueComments(t *testing.T) {
 	}
 }
 
+func TestGetIssueCommentsPagination(t *testing.T) {
+	server := createMockServer()
+	defer server.Close()
+
+	client := NewClientWithHTTPClient(server.Client(), "test-token")
+	client.Client.BaseURL, _ = url.Parse(server.URL + "/")
+
+	got, err := client.GetIssueComments(context.Background(), "testowner", "testrepo", 456)
+	if err != nil {
+		t.Fatalf("GetIssueComments() unexpected error = %v", err)
+	}
+
+	// 250条评论分布在3页中，必须全部返回
+	if len(got) != 250 {
+		t.Fatalf("GetIssueComments() length = %v, want %v", len(got), 250)
+	}
+	for i, comment := range got {
+		if comment.ID != int64(i+1) {
+			t.Errorf("GetIssueComments()[%d].ID = %v, want %v", i, comment.ID, i+1)
+			break
+		}
+	}
+}
+
+func TestGetIssueCommentsConcurrentPages(t *testing.T) {
+	const pages = 6
+	var inFlight, peak int32
+	var failPage int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page < 1 {
+			page = 1
+		}
+		if int32(page) == atomic.LoadInt32(&failPage) {
+			http.Error(w, `{"message": "Server Error"}`, http.StatusInternalServerError)
+			return
+		}
+
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		// 前面的页响应更慢，结果仍须按页序排列
+		time.Sleep(time.Duration(pages-page) * 5 * time.Millisecond)
+
+		if page < pages {
+			link := func(p int) string {
+				return fmt.Sprintf(`<http://%s%s?page=%d&per_page=100>`, r.Host, r.URL.Path, p)
+			}
+			w.Header().Set("Link", link(page+1)+`; rel="next", `+link(pages)+`; rel="last"`)
+		}
+		fmt.Fprintf(w, `[{"id": %d, "body": "page %d"}]`, page, page)
+	}))
+	defer server.Close()
+
+	client := NewClientWithHTTPClient(server.Client(), "test-token")
+	client.Client.BaseURL, _ = url.Parse(server.URL + "/")
+	client.Retrier.Policy.MaxAttempts = 1
+
+	got, err := client.GetIssueComments(context.Background(), "owner", "repo", 1)
+	if err != nil {
+		t.Fatalf("GetIssueComments() unexpected error = %v", err)
+	}
+	if len(got) != pages {
+		t.Fatalf("GetIssueComments() length = %d, want %d", len(got), pages)
+	}
+	for i, comment := range got {
+		if comment.ID != int64(i+1) {
+			t.Errorf("GetIssueComments()[%d].ID = %d, want %d", i, comment.ID, i+1)
+		}
+	}
+	if peak < 2 || peak > commentPageConcurrency {
+		t.Errorf("peak concurrent page requests = %d, want between 2 and %d", peak, commentPageConcurrency)
+	}
+
+	atomic.StoreInt32(&failPage, 4)
+	if _, err := client.GetIssueComments(context.Background(), "owner", "repo", 1); err == nil {
+		t.Error("GetIssueComments() with a failing page error = nil, want error")
+	}
+}
+
+func TestIssueCommentsIterator(t *testing.T) {
+	server := createMockServer()
+	defer server.Close()
+
+	client := NewClientWithHTTPClient(server.Client(), "test-token")
+	client.Client.BaseURL, _ = url.Parse(server.URL + "/")
+
+	tests := []struct {
+		name        string
+		owner       string
+		repo        string
+		issueNumber int
+		wantCount   int
+		wantErr     bool
+	}{
+		{
+			name:        "Single page",
+			owner:       "testowner",
+			repo:        "testrepo",
+			issueNumber: 123,
+			wantCount:   2,
+		},
+		{
+			name:        "Multiple pages",
+			owner:       "testowner",
+			repo:        "testrepo",
+			issueNumber: 456,
+			wantCount:   250,
+		},
+		{
+			name:        "Not found",
+			owner:       "errorowner",
+			repo:        "errorrepo",
+			issueNumber: 999,
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			it := client.IssueComments(tt.owner, tt.repo, tt.issueNumber)
+
+			count := 0
+			for it.Next(context.Background()) {
+				if it.Comment() == nil {
+					t.Fatal("Comment() returned nil after Next() returned true")
+				}
+				count++
+			}
+
+			if tt.wantErr {
+				if it.Err() == nil {
+					t.Error("Err() expected error, but got nil")
+				}
+				return
+			}
+
+			if it.Err() != nil {
+				t.Fatalf("Err() unexpected error = %v", it.Err())
+			}
+			if count != tt.wantCount {
+				t.Errorf("iterated %d comments, want %d", count, tt.wantCount)
+			}
+			if it.Next(context.Background()) {
+				t.Error("Next() returned true after iteration finished")
+			}
+		})
+	}
+}
+
+func TestIssueCommentsIteratorCanceled(t *testing.T) {
+	server := createMockServer()
+	defer server.Close()
+
+	client := NewClientWithHTTPClient(server.Client(), "test-token")
+	client.Client.BaseURL, _ = url.Parse(server.URL + "/")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	it := client.IssueComments("testowner", "testrepo", 456)
+
+	// 读完第一页后取消，迭代器不应再请求下一页
+	for i := 0; i < defaultPerPage; i++ {
+		if !it.Next(ctx) {
+			t.Fatalf("Next() returned false at %d, err = %v", i, it.Err())
+		}
+	}
+	cancel()
+
+	if it.Next(ctx) {
+		t.Error("Next() returned true after context was canceled")
+	}
+	if it.Err() != context.Canceled {
+		t.Errorf("Err() = %v, want %v", it.Err(), context.Canceled)
+	}
+}
+
 func TestConvertGitHubIssue(t *testing.T) {
 	// 测试转换函数的基本功能
 	tests := []struct {
 		name     string
 		input    *github.Issue
-		expected *Issue
+		expected *model.Issue
 	}{
 		{
 			name:     "Nil Input",
@@ -374,12 +622,12 @@ func TestConvertGitHubIssue(t *testing.T) {
 				CreatedAt: &github.Timestamp{Time: time.Date(2023, 1, 10, 10, 0, 0, 0, time.UTC)},
 				UpdatedAt: &github.Timestamp{Time: time.Date(2023, 1, 20, 15, 30, 0, 0, time.UTC)},
 			},
-			expected: &Issue{
+			expected: &model.Issue{
 				Number:    123,
 				Title:     "Test Issue Title",
 				Body:      "Test body",
 				State:     "open",
-				User: User{
+				User: model.User{
 					Login:     "testuser",
 					ID:        987654321,
 					AvatarURL: "https://avatars.githubusercontent.com/u/987654321?v=4",
@@ -426,7 +674,7 @@ func TestConvertGitHubComment(t *testing.T) {
 	tests := []struct {
 		name     string
 		input    *github.IssueComment
-		expected *Comment
+		expected *model.Comment
 	}{
 		{
 			name:     "Nil Input",
@@ -448,10 +696,10 @@ func TestConvertGitHubComment(t *testing.T) {
 				CreatedAt: &github.Timestamp{Time: time.Date(2023, 1, 10, 11, 0, 0, 0, time.UTC)},
 				UpdatedAt: &github.Timestamp{Time: time.Date(2023, 1, 10, 11, 0, 0, 0, time.UTC)},
 			},
-			expected: &Comment{
+			expected: &model.Comment{
 				ID:   111111111,
 				Body: "First comment on this issue.",
-				User: User{
+				User: model.User{
 					Login:     "commenter1",
 					ID:        111111111,
 					AvatarURL: "https://avatars.githubusercontent.com/u/111111111?v=4",
@@ -0,0 +1,264 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/bigwhite/issue2md/internal/model"
+)
+
+// discussionCommentsPerPage 每次查询的讨论评论数
+const discussionCommentsPerPage = 50
+
+// discussionQuery 查询Discussion及其一页评论和回复
+const discussionQuery = `query($owner: String!, $repo: String!, $number: Int!, $first: Int!, $cursor: String) {
+  repository(owner: $owner, name: $repo) {
+    discussion(number: $number) {
+      number
+      title
+      body
+      url
+      closed
+      closedAt
+      isAnswered
+      answerChosenAt
+      answerChosenBy { ` + graphQLActorFields + ` }
+      createdAt
+      updatedAt
+      author { ` + graphQLActorFields + ` }
+      category { name emoji slug isAnswerable }
+      ` + graphQLReactionFields + `
+      labels(first: 100) { nodes { name color description } }
+      comments(first: $first, after: $cursor) {
+        pageInfo { hasNextPage endCursor }
+        nodes {
+          ` + discussionCommentFields + `
+          replies(first: 100) {
+            pageInfo { hasNextPage endCursor }
+            nodes { ` + discussionCommentFields + ` }
+          }
+        }
+      }
+    }
+  }
+}`
+
+// discussionRepliesQuery 查询单条讨论评论的后续回复页
+const discussionRepliesQuery = `query($id: ID!, $cursor: String) {
+  node(id: $id) {
+    ... on DiscussionComment {
+      replies(first: 100, after: $cursor) {
+        pageInfo { hasNextPage endCursor }
+        nodes { ` + discussionCommentFields + ` }
+      }
+    }
+  }
+}`
+
+// discussionCommentFields 讨论评论与回复共用的字段
+const discussionCommentFields = `id databaseId body url createdAt updatedAt isAnswer isMinimized minimizedReason author { ` + graphQLActorFields + ` } ` + graphQLReactionFields
+
+// graphQLDiscussionComment GraphQL中的讨论评论
+type graphQLDiscussionComment struct {
+	ID              string                 `json:"id"`
+	DatabaseID      int64                  `json:"databaseId"`
+	Body            string                 `json:"body"`
+	URL             string                 `json:"url"`
+	CreatedAt       time.Time              `json:"createdAt"`
+	UpdatedAt       time.Time              `json:"updatedAt"`
+	IsAnswer        bool                   `json:"isAnswer"`
+	IsMinimized     bool                   `json:"isMinimized"`
+	MinimizedReason string                 `json:"minimizedReason"`
+	Author          *graphQLActor          `json:"author"`
+	Reactions       []graphQLReactionGroup `json:"reactionGroups"`
+	Replies         struct {
+		PageInfo graphQLPageInfo             `json:"pageInfo"`
+		Nodes    []*graphQLDiscussionComment `json:"nodes"`
+	} `json:"replies"`
+}
+
+// graphQLDiscussion GraphQL中的Discussion
+type graphQLDiscussion struct {
+	Number         int                    `json:"number"`
+	Title          string                 `json:"title"`
+	Body           string                 `json:"body"`
+	URL            string                 `json:"url"`
+	Closed         bool                   `json:"closed"`
+	ClosedAt       *time.Time             `json:"closedAt"`
+	IsAnswered     bool                   `json:"isAnswered"`
+	AnswerChosenAt *time.Time             `json:"answerChosenAt"`
+	AnswerChosenBy *graphQLActor          `json:"answerChosenBy"`
+	CreatedAt      time.Time              `json:"createdAt"`
+	UpdatedAt      time.Time              `json:"updatedAt"`
+	Author         *graphQLActor          `json:"author"`
+	Reactions      []graphQLReactionGroup `json:"reactionGroups"`
+	Category       struct {
+		Name         string `json:"name"`
+		Emoji        string `json:"emoji"`
+		Slug         string `json:"slug"`
+		IsAnswerable bool   `json:"isAnswerable"`
+	} `json:"category"`
+	Labels struct {
+		Nodes []struct {
+			Name        string `json:"name"`
+			Color       string `json:"color"`
+			Description string `json:"description"`
+		} `json:"nodes"`
+	} `json:"labels"`
+	Comments struct {
+		PageInfo graphQLPageInfo             `json:"pageInfo"`
+		Nodes    []*graphQLDiscussionComment `json:"nodes"`
+	} `json:"comments"`
+}
+
+// GetDiscussion 获取Discussion信息
+// Discussion仅能通过GraphQL API获取，评论及其嵌套回复会自动分页读取
+func (c *GitHubClient) GetDiscussion(ctx context.Context, owner, repo string, number int) (*model.Discussion, error) {
+	var discussion *model.Discussion
+	var cursor interface{}
+	for {
+		var data struct {
+			Repository struct {
+				Discussion *graphQLDiscussion `json:"discussion"`
+			} `json:"repository"`
+		}
+
+		err := c.graphQL(ctx, discussionQuery, map[string]interface{}{
+			"owner":  owner,
+			"repo":   repo,
+			"number": number,
+			"first":  discussionCommentsPerPage,
+			"cursor": cursor,
+		}, &data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get discussion %d from %s/%s: %w", number, owner, repo, err)
+		}
+
+		gqlDiscussion := data.Repository.Discussion
+		if gqlDiscussion == nil {
+			return nil, fmt.Errorf("failed to get discussion %d from %s/%s: not found", number, owner, repo)
+		}
+		if discussion == nil {
+			discussion = convertGraphQLDiscussion(gqlDiscussion)
+		}
+
+		for _, node := range gqlDiscussion.Comments.Nodes {
+			comment, err := c.convertDiscussionCommentWithReplies(ctx, node)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get replies for discussion %d from %s/%s: %w", number, owner, repo, err)
+			}
+			if answer := findAnswer(comment); answer != nil {
+				discussion.Answer = answer
+			}
+			discussion.Comments = append(discussion.Comments, comment)
+		}
+
+		if !gqlDiscussion.Comments.PageInfo.HasNextPage {
+			return discussion, nil
+		}
+		cursor = gqlDiscussion.Comments.PageInfo.EndCursor
+	}
+}
+
+// convertDiscussionCommentWithReplies 转换讨论评论，并补齐首页之外的回复
+func (c *GitHubClient) convertDiscussionCommentWithReplies(ctx context.Context, node *graphQLDiscussionComment) (*model.Comment, error) {
+	comment := convertGraphQLDiscussionComment(node)
+	for _, reply := range node.Replies.Nodes {
+		comment.Replies = append(comment.Replies, convertGraphQLDiscussionComment(reply))
+	}
+
+	pageInfo := node.Replies.PageInfo
+	for pageInfo.HasNextPage {
+		var data struct {
+			Node *graphQLDiscussionComment `json:"node"`
+		}
+		err := c.graphQL(ctx, discussionRepliesQuery, map[string]interface{}{
+			"id":     node.ID,
+			"cursor": pageInfo.EndCursor,
+		}, &data)
+		if err != nil {
+			return nil, err
+		}
+		if data.Node == nil {
+			break
+		}
+
+		for _, reply := range data.Node.Replies.Nodes {
+			comment.Replies = append(comment.Replies, convertGraphQLDiscussionComment(reply))
+		}
+		pageInfo = data.Node.Replies.PageInfo
+	}
+
+	return comment, nil
+}
+
+// findAnswer 在评论及其回复中查找被采纳的答案
+func findAnswer(comment *model.Comment) *model.Comment {
+	if comment.IsAnswer {
+		return comment
+	}
+	for _, reply := range comment.Replies {
+		if reply.IsAnswer {
+			return reply
+		}
+	}
+	return nil
+}
+
+// convertGraphQLDiscussion 将GraphQL的Discussion转换为内部Discussion结构（不含评论）
+func convertGraphQLDiscussion(gqlDiscussion *graphQLDiscussion) *model.Discussion {
+	var labels []model.Label
+	for _, label := range gqlDiscussion.Labels.Nodes {
+		labels = append(labels, model.Label{
+			Name:        label.Name,
+			Color:       label.Color,
+			Description: label.Description,
+		})
+	}
+
+	var answerChosenBy *model.User
+	if gqlDiscussion.AnswerChosenBy != nil {
+		user := gqlDiscussion.AnswerChosenBy.toUser()
+		answerChosenBy = &user
+	}
+
+	return &model.Discussion{
+		Number: gqlDiscussion.Number,
+		Title:  gqlDiscussion.Title,
+		Body:   gqlDiscussion.Body,
+		User:   gqlDiscussion.Author.toUser(),
+		Category: model.DiscussionCategory{
+			Name:         gqlDiscussion.Category.Name,
+			Emoji:        gqlDiscussion.Category.Emoji,
+			Slug:         gqlDiscussion.Category.Slug,
+			IsAnswerable: gqlDiscussion.Category.IsAnswerable,
+		},
+		Labels:         labels,
+		Closed:         gqlDiscussion.Closed,
+		Answered:       gqlDiscussion.IsAnswered,
+		AnswerChosenAt: gqlDiscussion.AnswerChosenAt,
+		AnswerChosenBy: answerChosenBy,
+		CreatedAt:      gqlDiscussion.CreatedAt,
+		UpdatedAt:      gqlDiscussion.UpdatedAt,
+		ClosedAt:       gqlDiscussion.ClosedAt,
+		HTMLURL:        gqlDiscussion.URL,
+		Reactions:      convertGraphQLReactions(gqlDiscussion.Reactions),
+	}
+}
+
+// convertGraphQLDiscussionComment 将GraphQL的讨论评论转换为内部Comment结构（不含回复）
+func convertGraphQLDiscussionComment(node *graphQLDiscussionComment) *model.Comment {
+	return &model.Comment{
+		ID:              node.DatabaseID,
+		Body:            node.Body,
+		User:            node.Author.toUser(),
+		CreatedAt:       node.CreatedAt,
+		UpdatedAt:       node.UpdatedAt,
+		HTMLURL:         node.URL,
+		IsAnswer:        node.IsAnswer,
+		Reactions:       convertGraphQLReactions(node.Reactions),
+		Minimized:       node.IsMinimized,
+		MinimizedReason: normalizeMinimizedReason(node.MinimizedReason),
+	}
+}
@@ -0,0 +1,246 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/bigwhite/issue2md/internal/model"
+)
+
+// mockDiscussionPage1JSON 模拟Discussion查询的第一页评论
+const mockDiscussionPage1JSON = `{"data": {"repository": {"discussion": {
+	"number": 543,
+	"title": "How do I export discussions?",
+	"body": "Is there a way to export a discussion?",
+	"url": "https://github.com/testowner/testrepo/discussions/543",
+	"closed": false,
+	"isAnswered": true,
+	"answerChosenAt": "2024-01-03T09:00:00Z",
+	"answerChosenBy": {"login": "asker", "url": "https://github.com/asker", "__typename": "User"},
+	"createdAt": "2024-01-01T10:00:00Z",
+	"updatedAt": "2024-01-03T09:00:00Z",
+	"author": {"login": "asker", "url": "https://github.com/asker", "__typename": "User"},
+	"category": {"name": "Q&A", "emoji": ":pray:", "slug": "q-a", "isAnswerable": true},
+	"reactionGroups": [
+		{"content": "THUMBS_UP", "reactors": {"totalCount": 4}},
+		{"content": "HEART", "reactors": {"totalCount": 1}}
+	],
+	"labels": {"nodes": [{"name": "question", "color": "d876e3"}]},
+	"comments": {
+		"pageInfo": {"hasNextPage": true, "endCursor": "c1"},
+		"nodes": [{
+			"id": "DC_1",
+			"databaseId": 101,
+			"body": "Try the CLI.",
+			"createdAt": "2024-01-01T11:00:00Z",
+			"updatedAt": "2024-01-01T11:00:00Z",
+			"isAnswer": false,
+			"author": {"login": "helper", "__typename": "User"},
+			"replies": {
+				"pageInfo": {"hasNextPage": true, "endCursor": "r1"},
+				"nodes": [{"id": "DC_2", "databaseId": 102, "body": "Which flag?", "createdAt": "2024-01-01T12:00:00Z", "author": {"login": "asker"}}]
+			}
+		}]
+	}
+}}}}`
+
+// mockDiscussionPage2JSON 模拟Discussion查询的第二页评论
+const mockDiscussionPage2JSON = `{"data": {"repository": {"discussion": {
+	"number": 543,
+	"title": "How do I export discussions?",
+	"comments": {
+		"pageInfo": {"hasNextPage": false, "endCursor": "c2"},
+		"nodes": [{
+			"id": "DC_4",
+			"databaseId": 104,
+			"body": "Use issue2md with the discussion URL.",
+			"createdAt": "2024-01-02T10:00:00Z",
+			"isAnswer": true,
+			"author": null,
+			"reactionGroups": [{"content": "HOORAY", "reactors": {"totalCount": 2}}],
+			"replies": {"pageInfo": {"hasNextPage": false}, "nodes": []}
+		}]
+	}
+}}}}`
+
+// mockDiscussionRepliesJSON 模拟讨论评论的第二页回复
+const mockDiscussionRepliesJSON = `{"data": {"node": {"replies": {
+	"pageInfo": {"hasNextPage": false, "endCursor": "r2"},
+	"nodes": [{"id": "DC_3", "databaseId": 103, "body": "The default one.", "createdAt": "2024-01-01T13:00:00Z", "author": {"login": "helper"}}]
+}}}}`
+
+// createGraphQLMockServer 创建模拟GitHub GraphQL API的服务器
+func createGraphQLMockServer(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/graphql", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("graphql request method = %s, want POST", r.Method)
+		}
+
+		var req graphQLRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode graphql request: %v", err)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.Contains(req.Query, "node(id: $id)"):
+			w.Write([]byte(mockDiscussionRepliesJSON))
+		case req.Variables["number"] == float64(404):
+			w.Write([]byte(`{"data": {"repository": {"discussion": null}}, "errors": [{"type": "NOT_FOUND", "message": "Could not resolve to a Discussion with the number of 404."}]}`))
+		case req.Variables["cursor"] == "c1":
+			w.Write([]byte(mockDiscussionPage2JSON))
+		default:
+			w.Write([]byte(mockDiscussionPage1JSON))
+		}
+	})
+
+	return httptest.NewServer(mux)
+}
+
+func TestGetDiscussion(t *testing.T) {
+	server := createGraphQLMockServer(t)
+	defer server.Close()
+
+	tests := []struct {
+		name          string
+		number        int
+		wantErr       bool
+		errorContains string
+	}{
+		{
+			name:   "Successful Discussion Retrieval",
+			number: 543,
+		},
+		{
+			name:          "Discussion Not Found",
+			number:        404,
+			wantErr:       true,
+			errorContains: "Could not resolve",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClientWithHTTPClient(server.Client(), "test-token")
+			client.Client.BaseURL, _ = url.Parse(server.URL + "/")
+
+			got, err := client.GetDiscussion(context.Background(), "testowner", "testrepo", tt.number)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("GetDiscussion() expected error, but got nil")
+				}
+				if !strings.Contains(err.Error(), tt.errorContains) {
+					t.Errorf("GetDiscussion() error = %v, expected to contain '%s'", err, tt.errorContains)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetDiscussion() unexpected error = %v", err)
+			}
+
+			if got.Title != "How do I export discussions?" {
+				t.Errorf("GetDiscussion().Title = %v", got.Title)
+			}
+			if got.Category.Name != "Q&A" || !got.Category.IsAnswerable {
+				t.Errorf("GetDiscussion().Category = %+v", got.Category)
+			}
+			if got.Status() != "answered" {
+				t.Errorf("GetDiscussion().Status() = %v, want answered", got.Status())
+			}
+			if got.AnswerChosenBy == nil || got.AnswerChosenBy.Login != "asker" {
+				t.Errorf("GetDiscussion().AnswerChosenBy = %v, want asker", got.AnswerChosenBy)
+			}
+
+			// 两页评论都应被读取
+			if len(got.Comments) != 2 {
+				t.Fatalf("GetDiscussion().Comments length = %d, want 2", len(got.Comments))
+			}
+
+			// 第一条评论的回复跨两页
+			replies := got.Comments[0].Replies
+			if len(replies) != 2 || replies[0].ID != 102 || replies[1].ID != 103 {
+				t.Errorf("GetDiscussion().Comments[0].Replies = %v, want IDs 102, 103", replies)
+			}
+
+			if got.Answer == nil || got.Answer.ID != 104 {
+				t.Fatalf("GetDiscussion().Answer = %v, want comment 104", got.Answer)
+			}
+			if got.Answer.Reactions.Hooray != 2 {
+				t.Errorf("GetDiscussion().Answer.Reactions = %+v, want 2 hooray", got.Answer.Reactions)
+			}
+			if got.Reactions != (model.Reactions{TotalCount: 5, ThumbsUp: 4, Heart: 1}) {
+				t.Errorf("GetDiscussion().Reactions = %+v", got.Reactions)
+			}
+			if got.Answer.User.Login != "" {
+				t.Errorf("GetDiscussion().Answer.User.Login = %v, want empty for deleted author", got.Answer.User.Login)
+			}
+		})
+	}
+}
+
+func TestDiscussionStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		discussion *model.Discussion
+		want       string
+	}{
+		{name: "Open", discussion: &model.Discussion{}, want: "open"},
+		{name: "Closed", discussion: &model.Discussion{Closed: true}, want: "closed"},
+		{name: "Answered", discussion: &model.Discussion{Answered: true}, want: "answered"},
+		{name: "Answered and closed", discussion: &model.Discussion{Answered: true, Closed: true}, want: "answered"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.discussion.Status(); got != tt.want {
+				t.Errorf("Discussion.Status() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConvertGraphQLReactions(t *testing.T) {
+	group := func(content string, count int) graphQLReactionGroup {
+		g := graphQLReactionGroup{Content: content}
+		g.Reactors.TotalCount = count
+		return g
+	}
+
+	tests := []struct {
+		name   string
+		groups []graphQLReactionGroup
+		want   model.Reactions
+	}{
+		{
+			name: "Empty",
+			want: model.Reactions{},
+		},
+		{
+			name: "All kinds",
+			groups: []graphQLReactionGroup{
+				group("THUMBS_UP", 1), group("THUMBS_DOWN", 2), group("LAUGH", 3), group("HOORAY", 4),
+				group("CONFUSED", 5), group("HEART", 6), group("ROCKET", 7), group("EYES", 8),
+			},
+			want: model.Reactions{TotalCount: 36, ThumbsUp: 1, ThumbsDown: 2, Laugh: 3, Hooray: 4, Confused: 5, Heart: 6, Rocket: 7, Eyes: 8},
+		},
+		{
+			name:   "Unknown content ignored",
+			groups: []graphQLReactionGroup{group("PARTY_PARROT", 9), group("EYES", 1)},
+			want:   model.Reactions{TotalCount: 1, Eyes: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := convertGraphQLReactions(tt.groups); got != tt.want {
+				t.Errorf("convertGraphQLReactions() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/bigwhite/issue2md/internal/model"
+)
+
+// editHistoryQuery 分页查询Issue或Pull Request的正文和评论的修改历史
+// 每条内容最多读取最近100个版本
+const editHistoryQuery = `query($owner: String!, $repo: String!, $number: Int!, $cursor: String) {
+  repository(owner: $owner, name: $repo) {
+    issueOrPullRequest(number: $number) {
+      ... on Issue { ` + graphQLEditHistoryFields + ` }
+      ... on PullRequest { ` + graphQLEditHistoryFields + ` }
+    }
+  }
+}`
+
+// graphQLEditHistoryFields Issue和Pull Request共用的修改历史字段
+const graphQLEditHistoryFields = `userContentEdits(first: 100) { nodes { ` + graphQLEditFields + ` } }
+      comments(first: 50, after: $cursor) {
+        pageInfo { hasNextPage endCursor }
+        nodes { databaseId userContentEdits(first: 100) { nodes { ` + graphQLEditFields + ` } } }
+      }`
+
+// graphQLEditFields 查询单个版本时使用的字段
+const graphQLEditFields = `editedAt diff deletedAt editor { ` + graphQLActorFields + ` }`
+
+// graphQLUserContentEdits GraphQL中的修改历史，按时间降序排列
+type graphQLUserContentEdits struct {
+	Nodes []*struct {
+		EditedAt  time.Time     `json:"editedAt"`
+		Diff      string        `json:"diff"`
+		DeletedAt *time.Time    `json:"deletedAt"`
+		Editor    *graphQLActor `json:"editor"`
+	} `json:"nodes"`
+}
+
+// toEdits 转换为按时间升序排列的内部Edit列表
+func (e *graphQLUserContentEdits) toEdits() []*model.Edit {
+	var edits []*model.Edit
+	for _, node := range e.Nodes {
+		if node == nil {
+			continue
+		}
+		edits = append(edits, &model.Edit{
+			Editor:   node.Editor.toUser(),
+			EditedAt: node.EditedAt,
+			Body:     node.Diff,
+			Deleted:  node.DeletedAt != nil,
+		})
+	}
+	sort.SliceStable(edits, func(i, j int) bool {
+		return edits[i].EditedAt.Before(edits[j].EditedAt)
+	})
+	return edits
+}
+
+// GetEditHistory 获取Issue或Pull Request正文及其评论的修改历史
+// 只包含普通评论，不包含代码评审评论
+func (c *GitHubClient) GetEditHistory(ctx context.Context, owner, repo string, number int) (*model.EditHistory, error) {
+	history := &model.EditHistory{Comments: make(map[int64][]*model.Edit)}
+	var cursor interface{}
+	for {
+		var data struct {
+			Repository struct {
+				IssueOrPullRequest *struct {
+					UserContentEdits graphQLUserContentEdits `json:"userContentEdits"`
+					Comments         struct {
+						PageInfo graphQLPageInfo `json:"pageInfo"`
+						Nodes    []*struct {
+							DatabaseID       int64                   `json:"databaseId"`
+							UserContentEdits graphQLUserContentEdits `json:"userContentEdits"`
+						} `json:"nodes"`
+					} `json:"comments"`
+				} `json:"issueOrPullRequest"`
+			} `json:"repository"`
+		}
+
+		err := c.graphQL(ctx, editHistoryQuery, map[string]interface{}{
+			"owner":  owner,
+			"repo":   repo,
+			"number": number,
+			"cursor": cursor,
+		}, &data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get edit history for #%d from %s/%s: %w", number, owner, repo, err)
+		}
+
+		item := data.Repository.IssueOrPullRequest
+		if item == nil {
+			return nil, fmt.Errorf("failed to get edit history for #%d from %s/%s: not found", number, owner, repo)
+		}
+
+		if cursor == nil {
+			history.Body = item.UserContentEdits.toEdits()
+		}
+		for _, node := range item.Comments.Nodes {
+			if node == nil {
+				continue
+			}
+			if edits := node.UserContentEdits.toEdits(); len(edits) > 0 {
+				history.Comments[node.DatabaseID] = edits
+			}
+		}
+
+		if !item.Comments.PageInfo.HasNextPage {
+			return history, nil
+		}
+		cursor = item.Comments.PageInfo.EndCursor
+	}
+}
@@ -0,0 +1,85 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+// mockEditHistoryPage1JSON 模拟修改历史查询的第一页，版本按时间降序返回
+const mockEditHistoryPage1JSON = `{"data": {"repository": {"issueOrPullRequest": {
+	"userContentEdits": {"nodes": [
+		{"editedAt": "2024-01-03T10:00:00Z", "diff": "Crash on startup\n\nSteps: run it", "deletedAt": null, "editor": {"login": "bob"}},
+		{"editedAt": "2024-01-01T10:00:00Z", "diff": "Crash", "deletedAt": null, "editor": {"login": "alice"}}
+	]},
+	"comments": {
+		"pageInfo": {"hasNextPage": true, "endCursor": "c1"},
+		"nodes": [
+			{"databaseId": 11, "userContentEdits": {"nodes": []}},
+			{"databaseId": 12, "userContentEdits": {"nodes": [
+				{"editedAt": "2024-01-02T12:00:00Z", "diff": "", "deletedAt": "2024-01-04T00:00:00Z", "editor": {"login": "carol"}},
+				{"editedAt": "2024-01-02T11:00:00Z", "diff": "LGTM", "deletedAt": null, "editor": {"login": "carol"}}
+			]}}
+		]
+	}
+}}}}`
+
+// mockEditHistoryPage2JSON 模拟修改历史查询的第二页
+const mockEditHistoryPage2JSON = `{"data": {"repository": {"issueOrPullRequest": {
+	"userContentEdits": {"nodes": []},
+	"comments": {
+		"pageInfo": {"hasNextPage": false, "endCursor": "c2"},
+		"nodes": [
+			{"databaseId": 13, "userContentEdits": {"nodes": [
+				{"editedAt": "2024-01-05T11:00:00Z", "diff": "Fixed", "deletedAt": null, "editor": null}
+			]}}
+		]
+	}
+}}}}`
+
+func TestGetEditHistory(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req graphQLRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode graphql request: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case req.Variables["number"] == float64(404):
+			w.Write([]byte(`{"data": {"repository": {"issueOrPullRequest": null}}}`))
+		case req.Variables["cursor"] == "c1":
+			w.Write([]byte(mockEditHistoryPage2JSON))
+		default:
+			w.Write([]byte(mockEditHistoryPage1JSON))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClientWithHTTPClient(server.Client(), "test-token")
+	client.Client.BaseURL, _ = url.Parse(server.URL + "/")
+
+	history, err := client.GetEditHistory(context.Background(), "o", "r", 1)
+	if err != nil {
+		t.Fatalf("GetEditHistory() unexpected error = %v", err)
+	}
+
+	if len(history.Body) != 2 || history.Body[0].Body != "Crash" || history.Body[1].Editor.Login != "bob" {
+		t.Errorf("Body edits = %+v, want oldest first", history.Body)
+	}
+	if len(history.Comments) != 2 {
+		t.Fatalf("Comments = %d entries, want 2", len(history.Comments))
+	}
+	if edits := history.Comments[12]; len(edits) != 2 || edits[0].Body != "LGTM" || !edits[1].Deleted {
+		t.Errorf("comment 12 edits = %+v", edits)
+	}
+	if edits := history.Comments[13]; len(edits) != 1 || edits[0].Body != "Fixed" {
+		t.Errorf("comment 13 edits = %+v", edits)
+	}
+
+	if _, err := client.GetEditHistory(context.Background(), "o", "r", 404); err == nil {
+		t.Error("GetEditHistory() expected error for missing issue, but got nil")
+	}
+}
@@ -0,0 +1,166 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/bigwhite/issue2md/internal/model"
+
+	"github.com/bigwhite/issue2md/internal/transport"
+)
+
+// graphQLEndpoint GraphQL接口相对于REST BaseURL的路径
+const graphQLEndpoint = "graphql"
+
+// graphQLURL 返回GraphQL接口地址
+// GitHub Enterprise Server的REST接口位于/api/v3/，GraphQL接口则位于/api/graphql
+func (c *GitHubClient) graphQLURL() string {
+	base := c.Client.BaseURL
+	if base == nil || !strings.HasSuffix(base.Path, "/api/v3/") {
+		return graphQLEndpoint
+	}
+
+	u := *base
+	u.Path = strings.TrimSuffix(base.Path, "v3/") + "graphql"
+	return u.String()
+}
+
+// graphQLRequest GraphQL请求体
+type graphQLRequest struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables,omitempty"`
+}
+
+// graphQLResponse GraphQL响应体
+type graphQLResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Type       string `json:"type"`
+		Message    string `json:"message"`
+		Extensions struct {
+			Code string `json:"code"`
+		} `json:"extensions"`
+	} `json:"errors"`
+}
+
+// GraphQLError GraphQL接口返回的业务错误
+// GraphQL即使查询失败也可能返回200，错误信息位于响应体的errors字段中
+type GraphQLError struct {
+	Type     string // 执行错误的类型，如 NOT_FOUND、FORBIDDEN
+	Code     string // 查询校验错误的代码，如 undefinedField
+	Messages []string
+}
+
+// Error 实现error接口
+func (e *GraphQLError) Error() string {
+	return "graphql: " + strings.Join(e.Messages, "; ")
+}
+
+// isUnsupported 判断错误是否因服务器不支持查询中的字段、类型或参数（如较旧的GitHub Enterprise Server）
+// 附加信息遇到这类错误时按没有数据处理；令牌权限不足等其他错误仍然返回给调用方
+func isUnsupported(err error) bool {
+	var gqlErr *GraphQLError
+	if !errors.As(err, &gqlErr) {
+		return false
+	}
+	switch gqlErr.Code {
+	case "undefinedField", "undefinedType", "argumentNotAccepted", "argumentLiteralsIncompatible":
+		return true
+	}
+	return false
+}
+
+// IsPermissionError 判断错误是否因令牌权限不足，如缺少read:project权限或App没有访问权限
+// 调用方可以据此跳过可选的附加信息并提示用户，而不是让整个导出失败
+func IsPermissionError(err error) bool {
+	var gqlErr *GraphQLError
+	if !errors.As(err, &gqlErr) {
+		return false
+	}
+	return gqlErr.Type == "FORBIDDEN" || gqlErr.Type == "INSUFFICIENT_SCOPES"
+}
+
+// graphQL 执行GraphQL查询并将data字段解码到out
+func (c *GitHubClient) graphQL(ctx context.Context, query string, variables map[string]interface{}, out interface{}) error {
+	req, err := c.Client.NewRequest(http.MethodPost, c.graphQLURL(), &graphQLRequest{
+		Query:     query,
+		Variables: variables,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create graphql request: %w", err)
+	}
+
+	// 查询是只读的，虽然使用POST也可以安全重试
+	var resp graphQLResponse
+	if _, err := c.Client.Do(transport.WithIdempotent(ctx), req, &resp); err != nil {
+		return fmt.Errorf("graphql request failed: %w", err)
+	}
+
+	if len(resp.Errors) > 0 {
+		gqlErr := &GraphQLError{Type: resp.Errors[0].Type, Code: resp.Errors[0].Extensions.Code}
+		for _, e := range resp.Errors {
+			gqlErr.Messages = append(gqlErr.Messages, e.Message)
+		}
+		return gqlErr
+	}
+
+	if err := json.Unmarshal(resp.Data, out); err != nil {
+		return fmt.Errorf("failed to decode graphql response: %w", err)
+	}
+	return nil
+}
+
+// graphQLActor GraphQL中的Actor（用户、Bot等）
+// 账号已删除时为null
+type graphQLActor struct {
+	Login     string `json:"login"`
+	AvatarURL string `json:"avatarUrl"`
+	URL       string `json:"url"`
+	Typename  string `json:"__typename"`
+}
+
+// toUser 转换为内部User结构
+func (a *graphQLActor) toUser() model.User {
+	if a == nil {
+		return model.User{}
+	}
+	return model.User{
+		Login:     a.Login,
+		AvatarURL: a.AvatarURL,
+		HTMLURL:   a.URL,
+		Type:      a.Typename,
+	}
+}
+
+// graphQLPageInfo GraphQL分页信息
+type graphQLPageInfo struct {
+	HasNextPage bool   `json:"hasNextPage"`
+	EndCursor   string `json:"endCursor"`
+}
+
+// graphQLActorFields 查询Actor时使用的字段
+const graphQLActorFields = `login avatarUrl url __typename`
+
+// graphQLReactionFields 查询reactions统计时使用的字段
+const graphQLReactionFields = `reactionGroups { content reactors { totalCount } }`
+
+// graphQLReactionGroup GraphQL中按表情分组的reactions统计
+type graphQLReactionGroup struct {
+	Content  string `json:"content"`
+	Reactors struct {
+		TotalCount int `json:"totalCount"`
+	} `json:"reactors"`
+}
+
+// convertGraphQLReactions 将GraphQL的reactions分组汇总为内部Reactions结构
+func convertGraphQLReactions(groups []graphQLReactionGroup) model.Reactions {
+	var reactions model.Reactions
+	for _, group := range groups {
+		reactions.Add(group.Content, group.Reactors.TotalCount)
+	}
+	return reactions
+}
@@ -0,0 +1,89 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestGraphQLUnsupportedSchema(t *testing.T) {
+	// fetchers 查询附加信息的方法，返回取得的数据条数
+	fetchers := map[string]func(ctx context.Context, client *GitHubClient) (int, error){
+		"GetIssueTimeline": func(ctx context.Context, client *GitHubClient) (int, error) {
+			events, err := client.GetIssueTimeline(ctx, "o", "r", 1)
+			return len(events), err
+		},
+		"GetIssueResolution": func(ctx context.Context, client *GitHubClient) (int, error) {
+			resolution, err := client.GetIssueResolution(ctx, "o", "r", 1)
+			if resolution == nil {
+				return 0, err
+			}
+			return 1, err
+		},
+		"GetMinimizedComments": func(ctx context.Context, client *GitHubClient) (int, error) {
+			minimized, err := client.GetMinimizedComments(ctx, "o", "r", 1)
+			return len(minimized), err
+		},
+		"GetProjectItems": func(ctx context.Context, client *GitHubClient) (int, error) {
+			items, err := client.GetProjectItems(ctx, "o", "r", 1)
+			return len(items), err
+		},
+	}
+
+	tests := []struct {
+		name       string
+		response   string
+		wantErr    bool
+		permission bool
+	}{
+		{
+			name:     "Field missing on older server",
+			response: `{"errors": [{"message": "Field 'closedByPullRequestsReferences' doesn't exist on type 'Issue'", "extensions": {"code": "undefinedField", "typeName": "Issue", "fieldName": "closedByPullRequestsReferences"}}]}`,
+		},
+		{
+			name:     "Argument not accepted",
+			response: `{"errors": [{"message": "Field 'closedByPullRequestsReferences' doesn't accept argument 'includeClosedPrs'", "extensions": {"code": "argumentNotAccepted"}}]}`,
+		},
+		{
+			name:       "Forbidden",
+			response:   `{"data": {"repository": null}, "errors": [{"type": "FORBIDDEN", "message": "Resource not accessible by integration"}]}`,
+			wantErr:    true,
+			permission: true,
+		},
+		{
+			name:       "Missing scope",
+			response:   `{"data": {"repository": null}, "errors": [{"type": "INSUFFICIENT_SCOPES", "message": "read:project"}]}`,
+			wantErr:    true,
+			permission: true,
+		},
+		{
+			name:     "Other error",
+			response: `{"errors": [{"type": "NOT_FOUND", "message": "Could not resolve to a Repository"}]}`,
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+
+			client := NewClientWithHTTPClient(server.Client(), "test-token")
+			client.Client.BaseURL, _ = url.Parse(server.URL + "/")
+			for name, fetch := range fetchers {
+				n, err := fetch(context.Background(), client)
+				if (err != nil) != tt.wantErr || n != 0 {
+					t.Errorf("%s() = %d items, error %v, wantErr %v", name, n, err, tt.wantErr)
+				}
+				if got := IsPermissionError(err); got != tt.permission {
+					t.Errorf("%s() IsPermissionError = %v, want %v", name, got, tt.permission)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package github
+
+import (
+	"context"
+	"sync"
+)
+
+// Group 并发执行一组互相独立的请求，最多同时运行limit个
+// 第一个返回错误的任务会取消共享的上下文，Wait返回该错误；用法与errgroup相同
+type Group struct {
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	sem     chan struct{}
+	errOnce sync.Once
+	err     error
+}
+
+// NewGroup 创建并发任务组，返回的上下文在任一任务出错或Wait返回后被取消
+// limit小于等于0时不限制并发数
+func NewGroup(ctx context.Context, limit int) (*Group, context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	g := &Group{cancel: cancel}
+	if limit > 0 {
+		g.sem = make(chan struct{}, limit)
+	}
+	return g, ctx
+}
+
+// Go 在新的goroutine中运行f，达到并发上限时阻塞直到有任务结束
+func (g *Group) Go(f func() error) {
+	if g.sem != nil {
+		g.sem <- struct{}{}
+	}
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		if g.sem != nil {
+			defer func() { <-g.sem }()
+		}
+		if err := f(); err != nil {
+			g.errOnce.Do(func() {
+				g.err = err
+				g.cancel()
+			})
+		}
+	}()
+}
+
+// Wait 等待全部任务结束，返回第一个错误
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	g.cancel()
+	return g.err
+}
@@ -0,0 +1,59 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGroupLimitsConcurrency(t *testing.T) {
+	g, _ := NewGroup(context.Background(), 2)
+
+	var running, peak int32
+	for i := 0; i < 8; i++ {
+		g.Go(func() error {
+			n := atomic.AddInt32(&running, 1)
+			for {
+				p := atomic.LoadInt32(&peak)
+				if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			atomic.AddInt32(&running, -1)
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+	if peak > 2 {
+		t.Errorf("peak concurrency = %d, want at most 2", peak)
+	}
+}
+
+func TestGroupFirstErrorCancels(t *testing.T) {
+	g, ctx := NewGroup(context.Background(), 0)
+	boom := errors.New("boom")
+
+	g.Go(func() error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	g.Go(func() error {
+		return boom
+	})
+
+	done := make(chan error, 1)
+	go func() { done <- g.Wait() }()
+	select {
+	case err := <-done:
+		if !errors.Is(err, boom) {
+			t.Errorf("Wait() error = %v, want %v", err, boom)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Wait() did not return, the failing task did not cancel the others")
+	}
+}
@@ -0,0 +1,88 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-github/v56/github"
+
+	"github.com/bigwhite/issue2md/internal/model"
+)
+
+// ListIssues 列出仓库中符合条件的全部Issue
+// 会自动跟随分页读取所有结果
+func (c *GitHubClient) ListIssues(ctx context.Context, owner, repo string, opts *model.IssueListOptions) ([]*model.Issue, error) {
+	if opts == nil {
+		opts = &model.IssueListOptions{}
+	}
+
+	milestone, err := c.resolveMilestone(ctx, owner, repo, opts.Milestone)
+	if err != nil {
+		return nil, err
+	}
+
+	listOpts := &github.IssueListByRepoOptions{
+		Milestone:   milestone,
+		State:       opts.State,
+		Assignee:    opts.Assignee,
+		Creator:     opts.Creator,
+		Labels:      opts.Labels,
+		Sort:        opts.Sort,
+		Direction:   opts.Direction,
+		Since:       opts.Since,
+		ListOptions: github.ListOptions{PerPage: defaultPerPage},
+	}
+
+	var issues []*model.Issue
+	for {
+		// 调用 GitHub API 获取一页 Issue
+		gitHubIssues, resp, err := c.Client.Issues.ListByRepo(ctx, owner, repo, listOpts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list issues from %s/%s: %w", owner, repo, err)
+		}
+
+		for _, gitHubIssue := range gitHubIssues {
+			if gitHubIssue == nil || (opts.ExcludePullRequests && gitHubIssue.IsPullRequest()) {
+				continue
+			}
+			issues = append(issues, convertGitHubIssue(gitHubIssue))
+		}
+
+		if resp.NextPage == 0 {
+			return issues, nil
+		}
+		listOpts.Page = resp.NextPage
+	}
+}
+
+// resolveMilestone 将里程碑标题转换为接口要求的编号，编号、"*"和"none"原样返回
+func (c *GitHubClient) resolveMilestone(ctx context.Context, owner, repo, milestone string) (string, error) {
+	if milestone == "" || milestone == "*" || milestone == "none" {
+		return milestone, nil
+	}
+	if _, err := strconv.Atoi(milestone); err == nil {
+		return milestone, nil
+	}
+
+	opts := &github.MilestoneListOptions{
+		State:       "all",
+		ListOptions: github.ListOptions{PerPage: defaultPerPage},
+	}
+	for {
+		milestones, resp, err := c.Client.Issues.ListMilestones(ctx, owner, repo, opts)
+		if err != nil {
+			return "", fmt.Errorf("failed to list milestones from %s/%s: %w", owner, repo, err)
+		}
+		for _, m := range milestones {
+			if strings.EqualFold(m.GetTitle(), milestone) {
+				return strconv.Itoa(m.GetNumber()), nil
+			}
+		}
+		if resp.NextPage == 0 {
+			return "", fmt.Errorf("milestone %q not found in %s/%s", milestone, owner, repo)
+		}
+		opts.Page = resp.NextPage
+	}
+}
@@ -0,0 +1,157 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bigwhite/issue2md/internal/model"
+)
+
+// createIssueListMockServer 创建Issue列表模拟服务器
+// 第一页包含Issue 1和Pull Request 2，第二页包含Issue 3；记录每次列表请求的查询参数
+func createIssueListMockServer(t *testing.T, queries *[]url.Values) *httptest.Server {
+	mux := http.NewServeMux()
+	var server *httptest.Server
+
+	mux.HandleFunc("/repos/o/r/issues", func(w http.ResponseWriter, r *http.Request) {
+		*queries = append(*queries, r.URL.Query())
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("page") == "2" {
+			w.Write([]byte(`[{"number": 3, "title": "Third", "state": "closed"}]`))
+			return
+		}
+		w.Header().Set("Link", fmt.Sprintf(`<%s/repos/o/r/issues?page=2>; rel="next"`, server.URL))
+		w.Write([]byte(`[
+			{"number": 1, "title": "First", "state": "open", "labels": [{"name": "bug"}]},
+			{"number": 2, "title": "Second", "state": "open", "pull_request": {"url": "https://api.github.com/repos/o/r/pulls/2"}}
+		]`))
+	})
+
+	mux.HandleFunc("/repos/o/r/milestones", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("state") != "all" {
+			t.Errorf("milestones state = %q, want all", r.URL.Query().Get("state"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"number": 4, "title": "v1.0"}, {"number": 7, "title": "v2.0"}]`))
+	})
+
+	server = httptest.NewServer(mux)
+	return server
+}
+
+func TestListIssues(t *testing.T) {
+	since := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		opts        *model.IssueListOptions
+		wantNumbers []int
+		wantQuery   map[string]string
+		wantErr     string
+	}{
+		{
+			name:        "All pages including pull requests",
+			wantNumbers: []int{1, 2, 3},
+			wantQuery:   map[string]string{"per_page": "100", "state": ""},
+		},
+		{
+			name: "Filters and pull requests skipped",
+			opts: &model.IssueListOptions{
+				State:               "all",
+				Labels:              []string{"bug", "ui"},
+				Milestone:           "V2.0",
+				Assignee:            "*",
+				Creator:             "alice",
+				Since:               since,
+				Sort:                "updated",
+				Direction:           "asc",
+				ExcludePullRequests: true,
+			},
+			wantNumbers: []int{1, 3},
+			wantQuery: map[string]string{
+				"state":     "all",
+				"labels":    "bug,ui",
+				"milestone": "7",
+				"assignee":  "*",
+				"creator":   "alice",
+				"since":     "2024-01-01T00:00:00Z",
+				"sort":      "updated",
+				"direction": "asc",
+			},
+		},
+		{
+			name:        "Milestone number passed through",
+			opts:        &model.IssueListOptions{Milestone: "4"},
+			wantNumbers: []int{1, 2, 3},
+			wantQuery:   map[string]string{"milestone": "4"},
+		},
+		{
+			name:    "Unknown milestone",
+			opts:    &model.IssueListOptions{Milestone: "v9"},
+			wantErr: `milestone "v9" not found`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var queries []url.Values
+			server := createIssueListMockServer(t, &queries)
+			defer server.Close()
+
+			client := NewClientWithHTTPClient(server.Client(), "test-token")
+			client.Client.BaseURL, _ = url.Parse(server.URL + "/")
+
+			issues, err := client.ListIssues(context.Background(), "o", "r", tt.opts)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ListIssues() error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ListIssues() unexpected error = %v", err)
+			}
+
+			var numbers []int
+			for _, issue := range issues {
+				numbers = append(numbers, issue.Number)
+			}
+			if fmt.Sprint(numbers) != fmt.Sprint(tt.wantNumbers) {
+				t.Errorf("ListIssues() numbers = %v, want %v", numbers, tt.wantNumbers)
+			}
+			if len(queries) != 2 {
+				t.Fatalf("list requests = %d, want 2", len(queries))
+			}
+			for key, want := range tt.wantQuery {
+				if got := queries[0].Get(key); got != want {
+					t.Errorf("query %s = %q, want %q", key, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestListIssuesMarksPullRequests(t *testing.T) {
+	var queries []url.Values
+	server := createIssueListMockServer(t, &queries)
+	defer server.Close()
+
+	client := NewClientWithHTTPClient(server.Client(), "test-token")
+	client.Client.BaseURL, _ = url.Parse(server.URL + "/")
+
+	issues, err := client.ListIssues(context.Background(), "o", "r", nil)
+	if err != nil {
+		t.Fatalf("ListIssues() unexpected error = %v", err)
+	}
+	for _, issue := range issues {
+		if issue.IsPullRequest != (issue.Number == 2) {
+			t.Errorf("issue %d IsPullRequest = %v", issue.Number, issue.IsPullRequest)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// minimizedCommentsQuery 分页查询Issue或Pull Request评论的折叠状态
+const minimizedCommentsQuery = `query($owner: String!, $repo: String!, $number: Int!, $cursor: String) {
+  repository(owner: $owner, name: $repo) {
+    issueOrPullRequest(number: $number) {
+      ... on Issue { ` + graphQLMinimizedCommentsFields + ` }
+      ... on PullRequest { ` + graphQLMinimizedCommentsFields + ` }
+    }
+  }
+}`
+
+// graphQLMinimizedCommentsFields Issue和Pull Request共用的评论折叠状态字段
+const graphQLMinimizedCommentsFields = `comments(first: 100, after: $cursor) {
+        pageInfo { hasNextPage endCursor }
+        nodes { databaseId isMinimized minimizedReason }
+      }`
+
+// GetMinimizedComments 获取Issue或Pull Request中被折叠的评论
+// 返回评论ID到折叠原因的映射，原因为 spam、abuse、off-topic、outdated、duplicate 或 resolved；
+// 只包含普通评论，不包含代码评审评论；服务器不支持折叠状态字段时返回空映射
+func (c *GitHubClient) GetMinimizedComments(ctx context.Context, owner, repo string, number int) (map[int64]string, error) {
+	minimized := make(map[int64]string)
+	var cursor interface{}
+	for {
+		var data struct {
+			Repository struct {
+				IssueOrPullRequest *struct {
+					Comments struct {
+						PageInfo graphQLPageInfo `json:"pageInfo"`
+						Nodes    []*struct {
+							DatabaseID      int64  `json:"databaseId"`
+							IsMinimized     bool   `json:"isMinimized"`
+							MinimizedReason string `json:"minimizedReason"`
+						} `json:"nodes"`
+					} `json:"comments"`
+				} `json:"issueOrPullRequest"`
+			} `json:"repository"`
+		}
+
+		err := c.graphQL(ctx, minimizedCommentsQuery, map[string]interface{}{
+			"owner":  owner,
+			"repo":   repo,
+			"number": number,
+			"cursor": cursor,
+		}, &data)
+		if isUnsupported(err) {
+			return minimized, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get minimized comments for #%d from %s/%s: %w", number, owner, repo, err)
+		}
+
+		item := data.Repository.IssueOrPullRequest
+		if item == nil {
+			return nil, fmt.Errorf("failed to get minimized comments for #%d from %s/%s: not found", number, owner, repo)
+		}
+
+		for _, node := range item.Comments.Nodes {
+			if node != nil && node.IsMinimized {
+				minimized[node.DatabaseID] = normalizeMinimizedReason(node.MinimizedReason)
+			}
+		}
+
+		if !item.Comments.PageInfo.HasNextPage {
+			return minimized, nil
+		}
+		cursor = item.Comments.PageInfo.EndCursor
+	}
+}
+
+// normalizeMinimizedReason 将折叠原因统一为小写、以连字符分隔的形式，如 OFF_TOPIC 转换为 off-topic
+func normalizeMinimizedReason(reason string) string {
+	return strings.ReplaceAll(strings.ToLower(reason), "_", "-")
+}
@@ -0,0 +1,59 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+// mockMinimizedPage1JSON 模拟评论折叠状态查询的第一页
+const mockMinimizedPage1JSON = `{"data": {"repository": {"issueOrPullRequest": {"comments": {
+	"pageInfo": {"hasNextPage": true, "endCursor": "m1"},
+	"nodes": [
+		{"databaseId": 11, "isMinimized": false, "minimizedReason": null},
+		{"databaseId": 12, "isMinimized": true, "minimizedReason": "spam"}
+	]
+}}}}}`
+
+// mockMinimizedPage2JSON 模拟评论折叠状态查询的第二页
+const mockMinimizedPage2JSON = `{"data": {"repository": {"issueOrPullRequest": {"comments": {
+	"pageInfo": {"hasNextPage": false, "endCursor": "m2"},
+	"nodes": [{"databaseId": 13, "isMinimized": true, "minimizedReason": "OFF_TOPIC"}]
+}}}}}`
+
+func TestGetMinimizedComments(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req graphQLRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode graphql request: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if req.Variables["cursor"] == "m1" {
+			w.Write([]byte(mockMinimizedPage2JSON))
+			return
+		}
+		w.Write([]byte(mockMinimizedPage1JSON))
+	}))
+	defer server.Close()
+
+	client := NewClientWithHTTPClient(server.Client(), "test-token")
+	client.Client.BaseURL, _ = url.Parse(server.URL + "/")
+
+	minimized, err := client.GetMinimizedComments(context.Background(), "o", "r", 1)
+	if err != nil {
+		t.Fatalf("GetMinimizedComments() unexpected error = %v", err)
+	}
+	if len(minimized) != 2 || minimized[12] != "spam" || minimized[13] != "off-topic" {
+		t.Errorf("GetMinimizedComments() = %v, want 12: spam, 13: off-topic", minimized)
+	}
+}
+
+func TestConvertGraphQLDiscussionCommentMinimized(t *testing.T) {
+	comment := convertGraphQLDiscussionComment(&graphQLDiscussionComment{DatabaseID: 7, IsMinimized: true, MinimizedReason: "OUTDATED"})
+	if !comment.Minimized || comment.MinimizedReason != "outdated" {
+		t.Errorf("comment = %+v, want minimized as outdated", comment)
+	}
+}
@@ -0,0 +1,136 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/bigwhite/issue2md/internal/model"
+)
+
+// projectItemsQuery 查询Issue或Pull Request所属项目条目及其字段值
+// 一个条目最多有50个字段，一个Issue极少属于超过100个项目，因此不分页
+const projectItemsQuery = `query($owner: String!, $repo: String!, $number: Int!) {
+  repository(owner: $owner, name: $repo) {
+    issueOrPullRequest(number: $number) {
+      ... on Issue { ` + graphQLProjectItemsFields + ` }
+      ... on PullRequest { ` + graphQLProjectItemsFields + ` }
+    }
+  }
+}`
+
+// graphQLProjectItemsFields Issue和Pull Request共用的项目条目字段
+const graphQLProjectItemsFields = `projectItems(first: 100, includeArchived: false) {
+        nodes {
+          project { title number url }
+          fieldValues(first: 100) {
+            nodes {
+              ... on ProjectV2ItemFieldSingleSelectValue { name ` + graphQLProjectFieldName + ` }
+              ... on ProjectV2ItemFieldTextValue { text ` + graphQLProjectFieldName + ` }
+              ... on ProjectV2ItemFieldNumberValue { number ` + graphQLProjectFieldName + ` }
+              ... on ProjectV2ItemFieldDateValue { date ` + graphQLProjectFieldName + ` }
+              ... on ProjectV2ItemFieldIterationValue { title ` + graphQLProjectFieldName + ` }
+            }
+          }
+        }
+      }`
+
+// graphQLProjectFieldName 字段值所属字段的名称和类型
+const graphQLProjectFieldName = `field { ... on ProjectV2FieldCommon { name dataType } }`
+
+// graphQLProjectFieldValue 项目条目的一个字段值，不同类型的值位于不同字段
+type graphQLProjectFieldValue struct {
+	Name   *string  `json:"name"`
+	Text   *string  `json:"text"`
+	Number *float64 `json:"number"`
+	Date   *string  `json:"date"`
+	Title  *string  `json:"title"`
+	Field  *struct {
+		Name     string `json:"name"`
+		DataType string `json:"dataType"`
+	} `json:"field"`
+}
+
+// value 返回字段值，没有值时返回nil
+func (v *graphQLProjectFieldValue) value() interface{} {
+	switch {
+	case v.Name != nil:
+		return *v.Name
+	case v.Text != nil:
+		return *v.Text
+	case v.Number != nil:
+		return *v.Number
+	case v.Date != nil:
+		return *v.Date
+	case v.Title != nil:
+		return *v.Title
+	}
+	return nil
+}
+
+// GetProjectItems 获取Issue或Pull Request所属的Projects (v2)条目及自定义字段值
+// 只返回未归档的条目；标签、里程碑、指派人等与Issue本身重复的字段和标题字段被跳过。
+// 服务器不支持Projects (v2)字段时返回nil；令牌缺少read:project权限时返回错误，可用IsPermissionError判断
+func (c *GitHubClient) GetProjectItems(ctx context.Context, owner, repo string, number int) ([]*model.ProjectItem, error) {
+	var data struct {
+		Repository struct {
+			IssueOrPullRequest *struct {
+				ProjectItems struct {
+					Nodes []*struct {
+						Project struct {
+							Title  string `json:"title"`
+							Number int    `json:"number"`
+							URL    string `json:"url"`
+						} `json:"project"`
+						FieldValues struct {
+							Nodes []*graphQLProjectFieldValue `json:"nodes"`
+						} `json:"fieldValues"`
+					} `json:"nodes"`
+				} `json:"projectItems"`
+			} `json:"issueOrPullRequest"`
+		} `json:"repository"`
+	}
+
+	err := c.graphQL(ctx, projectItemsQuery, map[string]interface{}{
+		"owner":  owner,
+		"repo":   repo,
+		"number": number,
+	}, &data)
+	if isUnsupported(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project items for #%d from %s/%s: %w", number, owner, repo, err)
+	}
+
+	item := data.Repository.IssueOrPullRequest
+	if item == nil {
+		return nil, fmt.Errorf("failed to get project items for #%d from %s/%s: not found", number, owner, repo)
+	}
+
+	var items []*model.ProjectItem
+	for _, node := range item.ProjectItems.Nodes {
+		if node == nil {
+			continue
+		}
+		projectItem := &model.ProjectItem{
+			Project: node.Project.Title,
+			Number:  node.Project.Number,
+			URL:     node.Project.URL,
+		}
+		for _, fieldValue := range node.FieldValues.Nodes {
+			// 不支持的值类型解码为空对象
+			if fieldValue == nil || fieldValue.Field == nil || fieldValue.Field.DataType == "TITLE" {
+				continue
+			}
+			if value := fieldValue.value(); value != nil {
+				projectItem.Fields = append(projectItem.Fields, &model.ProjectField{Name: fieldValue.Field.Name, Value: value})
+			}
+		}
+		sort.Slice(projectItem.Fields, func(i, j int) bool {
+			return projectItem.Fields[i].Name < projectItem.Fields[j].Name
+		})
+		items = append(items, projectItem)
+	}
+	return items, nil
+}
@@ -0,0 +1,72 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+// mockProjectItemsJSON 模拟项目条目查询，包含标题字段和不支持的字段值类型
+const mockProjectItemsJSON = `{"data": {"repository": {"issueOrPullRequest": {"projectItems": {"nodes": [
+	{
+		"project": {"title": "Roadmap", "number": 3, "url": "https://github.com/orgs/o/projects/3"},
+		"fieldValues": {"nodes": [
+			{"text": "Crash on startup", "field": {"name": "Title", "dataType": "TITLE"}},
+			{"name": "In Progress", "field": {"name": "Status", "dataType": "SINGLE_SELECT"}},
+			{"number": 5, "field": {"name": "Estimate", "dataType": "NUMBER"}},
+			{"title": "Iteration 7", "field": {"name": "Iteration", "dataType": "ITERATION"}},
+			{"date": "2024-05-01", "field": {"name": "Due", "dataType": "DATE"}},
+			{}
+		]}
+	}
+]}}}}}`
+
+func TestGetProjectItems(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		want     string
+	}{
+		{
+			name:     "Field values",
+			response: mockProjectItemsJSON,
+			want:     "Roadmap#3: Due=2024-05-01 Estimate=5 Iteration=Iteration 7 Status=In Progress",
+		},
+		{
+			name:     "Server without Projects (v2)",
+			response: `{"errors": [{"message": "Field 'projectItems' doesn't exist on type 'Issue'", "extensions": {"code": "undefinedField"}}]}`,
+			want:     "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+
+			client := NewClientWithHTTPClient(server.Client(), "test-token")
+			client.Client.BaseURL, _ = url.Parse(server.URL + "/")
+
+			items, err := client.GetProjectItems(context.Background(), "o", "r", 1)
+			if err != nil {
+				t.Fatalf("GetProjectItems() unexpected error = %v", err)
+			}
+			got := ""
+			for _, item := range items {
+				got += fmt.Sprintf("%s#%d:", item.Project, item.Number)
+				for _, field := range item.Fields {
+					got += fmt.Sprintf(" %s=%v", field.Name, field.Value)
+				}
+			}
+			if got != tt.want {
+				t.Errorf("GetProjectItems() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
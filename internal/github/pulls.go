@@ -0,0 +1,299 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/go-github/v56/github"
+
+	"github.com/bigwhite/issue2md/internal/model"
+)
+
+// GetPullRequest 获取Pull Request信息
+// 包括合并/草稿状态、分支信息、评审结论、reactions，以及按时间排序的普通评论和代码评审评论；
+// 这些数据来自互相独立的接口，并发获取，任一请求失败会取消其余请求
+func (c *GitHubClient) GetPullRequest(ctx context.Context, owner, repo string, number int) (*model.PullRequest, error) {
+	var (
+		gitHubPR       *github.PullRequest
+		reviews        []*model.Review
+		reactions      model.Reactions
+		comments       []*model.Comment
+		reviewComments []*model.Comment
+	)
+
+	g, groupCtx := NewGroup(ctx, 0)
+	g.Go(func() error {
+		// 调用 GitHub API 获取 PR
+		var err error
+		if gitHubPR, _, err = c.Client.PullRequests.Get(groupCtx, owner, repo, number); err != nil {
+			return fmt.Errorf("failed to get pull request %d from %s/%s: %w", number, owner, repo, err)
+		}
+		return nil
+	})
+	g.Go(func() error {
+		var err error
+		reviews, reactions, err = c.getPullRequestReviews(groupCtx, owner, repo, number)
+		return err
+	})
+	g.Go(func() error {
+		// PR的普通评论走Issue评论接口
+		var err error
+		comments, err = c.GetIssueComments(groupCtx, owner, repo, number)
+		return err
+	})
+	g.Go(func() error {
+		var err error
+		reviewComments, err = c.getPullRequestReviewComments(groupCtx, owner, repo, number)
+		return err
+	})
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	pr := convertGitHubPullRequest(gitHubPR)
+	pr.Reactions = reactions
+	pr.Reviews = reviews
+	pr.Comments = mergeCommentsByTime(comments, reviewComments)
+	return pr, nil
+}
+
+// pullRequestReviewsQuery 查询PR的reactions统计和一页评审结论
+// PR的REST接口不返回reactions，与评审结论一起查询，不需要额外请求同编号的Issue
+const pullRequestReviewsQuery = `query($owner: String!, $repo: String!, $number: Int!, $cursor: String) {
+  repository(owner: $owner, name: $repo) {
+    pullRequest(number: $number) {
+      ` + graphQLReactionFields + `
+      reviews(first: 100, after: $cursor) {
+        pageInfo { hasNextPage endCursor }
+        nodes {
+          databaseId
+          body
+          state
+          submittedAt
+          url
+          commit { oid }
+          author { ` + graphQLActorFields + ` }
+        }
+      }
+    }
+  }
+}`
+
+// graphQLReview GraphQL中的PR评审结论
+type graphQLReview struct {
+	DatabaseID  int64         `json:"databaseId"`
+	Body        string        `json:"body"`
+	State       string        `json:"state"`
+	SubmittedAt *time.Time    `json:"submittedAt"`
+	URL         string        `json:"url"`
+	Author      *graphQLActor `json:"author"`
+	Commit      *struct {
+		OID string `json:"oid"`
+	} `json:"commit"`
+}
+
+// getPullRequestReviews 分页获取PR的全部评审结论，同时返回PR的reactions统计
+func (c *GitHubClient) getPullRequestReviews(ctx context.Context, owner, repo string, number int) ([]*model.Review, model.Reactions, error) {
+	var reviews []*model.Review
+	var reactions model.Reactions
+	var cursor interface{}
+	for {
+		var data struct {
+			Repository struct {
+				PullRequest *struct {
+					Reactions []graphQLReactionGroup `json:"reactionGroups"`
+					Reviews   struct {
+						PageInfo graphQLPageInfo  `json:"pageInfo"`
+						Nodes    []*graphQLReview `json:"nodes"`
+					} `json:"reviews"`
+				} `json:"pullRequest"`
+			} `json:"repository"`
+		}
+		err := c.graphQL(ctx, pullRequestReviewsQuery, map[string]interface{}{
+			"owner":  owner,
+			"repo":   repo,
+			"number": number,
+			"cursor": cursor,
+		}, &data)
+		if err != nil {
+			return nil, model.Reactions{}, fmt.Errorf("failed to get reviews for pull request %d from %s/%s: %w", number, owner, repo, err)
+		}
+
+		pr := data.Repository.PullRequest
+		if pr == nil {
+			return nil, model.Reactions{}, fmt.Errorf("pull request %d not found in %s/%s", number, owner, repo)
+		}
+		if cursor == nil {
+			reactions = convertGraphQLReactions(pr.Reactions)
+		}
+		for _, node := range pr.Reviews.Nodes {
+			if node != nil {
+				reviews = append(reviews, node.toReview())
+			}
+		}
+
+		if !pr.Reviews.PageInfo.HasNextPage {
+			return reviews, reactions, nil
+		}
+		cursor = pr.Reviews.PageInfo.EndCursor
+	}
+}
+
+// toReview 转换为内部Review结构
+func (r *graphQLReview) toReview() *model.Review {
+	review := &model.Review{
+		ID:          r.DatabaseID,
+		User:        r.Author.toUser(),
+		Body:        r.Body,
+		State:       r.State,
+		SubmittedAt: r.SubmittedAt,
+		HTMLURL:     r.URL,
+	}
+	if r.Commit != nil {
+		review.CommitID = r.Commit.OID
+	}
+	return review
+}
+
+// getPullRequestReviewComments 分页获取PR的全部代码评审评论
+func (c *GitHubClient) getPullRequestReviewComments(ctx context.Context, owner, repo string, number int) ([]*model.Comment, error) {
+	var comments []*model.Comment
+	opts := &github.PullRequestListCommentsOptions{
+		ListOptions: github.ListOptions{PerPage: defaultPerPage},
+	}
+	for {
+		gitHubComments, resp, err := c.Client.PullRequests.ListComments(ctx, owner, repo, number, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get review comments for pull request %d from %s/%s: %w", number, owner, repo, err)
+		}
+
+		for _, gitHubComment := range gitHubComments {
+			if gitHubComment != nil {
+				comments = append(comments, convertGitHubReviewComment(gitHubComment))
+			}
+		}
+
+		if resp.NextPage == 0 {
+			return comments, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+// mergeCommentsByTime 合并多组评论并按创建时间排序
+// 使用稳定排序，创建时间相同的评论保持原有先后顺序
+func mergeCommentsByTime(groups ...[]*model.Comment) []*model.Comment {
+	var merged []*model.Comment
+	for _, group := range groups {
+		merged = append(merged, group...)
+	}
+
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].CreatedAt.Before(merged[j].CreatedAt)
+	})
+	return merged
+}
+
+// convertGitHubPullRequest 将GitHub API的PullRequest转换为内部PullRequest结构
+func convertGitHubPullRequest(gitHubPR *github.PullRequest) *model.PullRequest {
+	if gitHubPR == nil {
+		return nil
+	}
+
+	var closedAt, mergedAt *time.Time
+	if gitHubPR.ClosedAt != nil {
+		closedAt = &gitHubPR.ClosedAt.Time
+	}
+	if gitHubPR.MergedAt != nil {
+		mergedAt = &gitHubPR.MergedAt.Time
+	}
+
+	var mergedBy *model.User
+	if gitHubPR.MergedBy != nil {
+		user := convertGitHubUser(gitHubPR.MergedBy)
+		mergedBy = &user
+	}
+
+	var teams []model.Team
+	for _, team := range gitHubPR.RequestedTeams {
+		if team != nil {
+			teams = append(teams, model.Team{
+				Name:    team.GetName(),
+				Slug:    team.GetSlug(),
+				HTMLURL: team.GetHTMLURL(),
+			})
+		}
+	}
+
+	return &model.PullRequest{
+		Issue: model.Issue{
+			Number:    gitHubPR.GetNumber(),
+			Title:     gitHubPR.GetTitle(),
+			Body:      gitHubPR.GetBody(),
+			State:     gitHubPR.GetState(),
+			User:      convertGitHubUser(gitHubPR.User),
+			Labels:    convertGitHubLabels(gitHubPR.Labels),
+			Assignees: convertGitHubUsers(gitHubPR.Assignees),
+			Milestone: convertGitHubMilestone(gitHubPR.Milestone),
+			CreatedAt: gitHubPR.GetCreatedAt().Time,
+			UpdatedAt: gitHubPR.GetUpdatedAt().Time,
+			ClosedAt:  closedAt,
+			URL:       gitHubPR.GetURL(),
+			HTMLURL:   gitHubPR.GetHTMLURL(),
+		},
+		Draft:              gitHubPR.GetDraft(),
+		Merged:             gitHubPR.GetMerged() || mergedAt != nil,
+		MergedAt:           mergedAt,
+		MergedBy:           mergedBy,
+		MergeCommitSHA:     gitHubPR.GetMergeCommitSHA(),
+		Head:               convertGitHubBranch(gitHubPR.Head),
+		Base:               convertGitHubBranch(gitHubPR.Base),
+		RequestedReviewers: convertGitHubUsers(gitHubPR.RequestedReviewers),
+		RequestedTeams:     teams,
+	}
+}
+
+// convertGitHubBranch 将GitHub API的PullRequestBranch转换为内部Branch结构
+func convertGitHubBranch(gitHubBranch *github.PullRequestBranch) model.Branch {
+	if gitHubBranch == nil {
+		return model.Branch{}
+	}
+
+	return model.Branch{
+		Label: gitHubBranch.GetLabel(),
+		Ref:   gitHubBranch.GetRef(),
+		SHA:   gitHubBranch.GetSHA(),
+		Repo:  gitHubBranch.GetRepo().GetFullName(),
+	}
+}
+
+// convertGitHubReviewComment 将GitHub API的PullRequestComment转换为内部Comment结构
+func convertGitHubReviewComment(gitHubComment *github.PullRequestComment) *model.Comment {
+	if gitHubComment == nil {
+		return nil
+	}
+
+	// 评论所在代码行已过期时 line 为空，回退到 original_line
+	line := gitHubComment.GetLine()
+	if line == 0 {
+		line = gitHubComment.GetOriginalLine()
+	}
+
+	return &model.Comment{
+		ID:          gitHubComment.GetID(),
+		Body:        gitHubComment.GetBody(),
+		User:        convertGitHubUser(gitHubComment.User),
+		CreatedAt:   gitHubComment.GetCreatedAt().Time,
+		UpdatedAt:   gitHubComment.GetUpdatedAt().Time,
+		URL:         gitHubComment.GetURL(),
+		HTMLURL:     gitHubComment.GetHTMLURL(),
+		Path:        gitHubComment.GetPath(),
+		Line:        line,
+		DiffHunk:    gitHubComment.GetDiffHunk(),
+		ReviewID:    gitHubComment.GetPullRequestReviewID(),
+		InReplyToID: gitHubComment.GetInReplyTo(),
+		Reactions:   convertGitHubReactions(gitHubComment.Reactions),
+	}
+}
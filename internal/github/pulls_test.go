@@ -0,0 +1,347 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v56/github"
+
+	"github.com/bigwhite/issue2md/internal/model"
+)
+
+// mockPullRequestJSON 模拟GitHub Pull Request API响应
+const mockPullRequestJSON = `{
+	"id": 42000,
+	"number": 42,
+	"state": "closed",
+	"title": "feat: add GitHub API client",
+	"body": "This PR adds a GitHub API client.",
+	"user": {"login": "contributor", "id": 1},
+	"labels": [{"name": "enhancement", "color": "a2eeef"}],
+	"draft": false,
+	"merged": true,
+	"merged_at": "2024-01-06T14:00:00Z",
+	"merged_by": {"login": "maintainer1", "id": 2},
+	"merge_commit_sha": "abc123",
+	"created_at": "2024-01-05T09:00:00Z",
+	"updated_at": "2024-01-06T14:00:00Z",
+	"closed_at": "2024-01-06T14:00:00Z",
+	"head": {"label": "contributor:feature", "ref": "feature", "sha": "def456", "repo": {"full_name": "contributor/testrepo"}},
+	"base": {"label": "testowner:main", "ref": "main", "sha": "789abc", "repo": {"full_name": "testowner/testrepo"}},
+	"requested_reviewers": [{"login": "reviewer2", "id": 3}],
+	"requested_teams": [{"name": "Core", "slug": "core"}],
+	"html_url": "https://github.com/testowner/testrepo/pull/42"
+}`
+
+// mockReviewsGraphQLJSON 模拟查询PR reactions和评审结论的GraphQL响应
+const mockReviewsGraphQLJSON = `{"data": {"repository": {"pullRequest": {
+	"reactionGroups": [
+		{"content": "THUMBS_UP", "reactors": {"totalCount": 1}},
+		{"content": "HEART", "reactors": {"totalCount": 1}}
+	],
+	"reviews": {
+		"pageInfo": {"hasNextPage": false, "endCursor": null},
+		"nodes": [
+			{
+				"databaseId": 1001,
+				"author": {"login": "reviewer1", "__typename": "User"},
+				"body": "Please rename this.",
+				"state": "CHANGES_REQUESTED",
+				"submittedAt": "2024-01-05T11:00:00Z",
+				"commit": {"oid": "def456"}
+			},
+			{
+				"databaseId": 1002,
+				"author": {"login": "reviewer1", "__typename": "User"},
+				"body": "",
+				"state": "APPROVED",
+				"submittedAt": "2024-01-06T10:00:00Z",
+				"commit": {"oid": "def456"}
+			}
+		]
+	}
+}}}}`
+
+// mockReviewCommentsJSON 模拟GitHub PR Review Comments API响应
+const mockReviewCommentsJSON = `[
+	{
+		"id": 2001,
+		"pull_request_review_id": 1001,
+		"body": "This name is confusing.",
+		"path": "internal/github/client.go",
+		"line": 12,
+		"diff_hunk": "@@ -10,3 +10,4 @@",
+		"reactions": {"total_count": 1, "eyes": 1},
+		"user": {"login": "reviewer1", "id": 4},
+		"created_at": "2024-01-05T11:00:00Z",
+		"updated_at": "2024-01-05T11:00:00Z"
+	},
+	{
+		"id": 2002,
+		"pull_request_review_id": 1001,
+		"in_reply_to_id": 2001,
+		"body": "Renamed.",
+		"path": "internal/github/client.go",
+		"original_line": 12,
+		"user": {"login": "contributor", "id": 1},
+		"created_at": "2024-01-05T13:00:00Z",
+		"updated_at": "2024-01-05T13:00:00Z"
+	}
+]`
+
+// mockPullIssueCommentsJSON 模拟PR普通评论响应
+const mockPullIssueCommentsJSON = `[
+	{
+		"id": 3001,
+		"body": "Looks good overall!",
+		"user": {"login": "maintainer1", "id": 2},
+		"created_at": "2024-01-05T10:00:00Z",
+		"updated_at": "2024-01-05T10:00:00Z"
+	},
+	{
+		"id": 3002,
+		"body": "Thanks for the update.",
+		"user": {"login": "maintainer1", "id": 2},
+		"created_at": "2024-01-05T12:00:00Z",
+		"updated_at": "2024-01-05T12:00:00Z"
+	}
+]`
+
+// createPullRequestMockServer 创建模拟PR相关API的服务器
+func createPullRequestMockServer() *httptest.Server {
+	mux := http.NewServeMux()
+
+	routes := map[string]string{
+		"/repos/testowner/testrepo/pulls/42":           mockPullRequestJSON,
+		"/graphql":                                     mockReviewsGraphQLJSON,
+		"/repos/testowner/testrepo/pulls/42/comments":  mockReviewCommentsJSON,
+		"/repos/testowner/testrepo/issues/42/comments": mockPullIssueCommentsJSON,
+	}
+	for path, body := range routes {
+		body := body
+		mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(body))
+		})
+	}
+
+	return httptest.NewServer(mux)
+}
+
+func TestGetPullRequest(t *testing.T) {
+	server := createPullRequestMockServer()
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		owner   string
+		repo    string
+		number  int
+		wantErr bool
+	}{
+		{
+			name:   "Successful Pull Request Retrieval",
+			owner:  "testowner",
+			repo:   "testrepo",
+			number: 42,
+		},
+		{
+			name:    "Pull Request Not Found",
+			owner:   "testowner",
+			repo:    "testrepo",
+			number:  404,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClientWithHTTPClient(server.Client(), "test-token")
+			client.Client.BaseURL, _ = url.Parse(server.URL + "/")
+
+			got, err := client.GetPullRequest(context.Background(), tt.owner, tt.repo, tt.number)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("GetPullRequest() expected error, but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetPullRequest() unexpected error = %v", err)
+			}
+
+			if got.Number != 42 || got.Title != "feat: add GitHub API client" {
+				t.Errorf("GetPullRequest() = #%d %q, want #42 %q", got.Number, got.Title, "feat: add GitHub API client")
+			}
+			if !got.Merged || got.Status() != "merged" {
+				t.Errorf("GetPullRequest().Status() = %v, want merged", got.Status())
+			}
+			if got.MergedBy == nil || got.MergedBy.Login != "maintainer1" {
+				t.Errorf("GetPullRequest().MergedBy = %v, want maintainer1", got.MergedBy)
+			}
+			if got.Head.Ref != "feature" || got.Base.Ref != "main" {
+				t.Errorf("GetPullRequest() head/base = %s/%s, want feature/main", got.Head.Ref, got.Base.Ref)
+			}
+			if got.Head.Repo != "contributor/testrepo" {
+				t.Errorf("GetPullRequest().Head.Repo = %v, want contributor/testrepo", got.Head.Repo)
+			}
+			if len(got.RequestedReviewers) != 1 || got.RequestedReviewers[0].Login != "reviewer2" {
+				t.Errorf("GetPullRequest().RequestedReviewers = %v, want [reviewer2]", got.RequestedReviewers)
+			}
+			if len(got.RequestedTeams) != 1 || got.RequestedTeams[0].Slug != "core" {
+				t.Errorf("GetPullRequest().RequestedTeams = %v, want [core]", got.RequestedTeams)
+			}
+
+			if got.Reactions.ThumbsUp != 1 || got.Reactions.Heart != 1 {
+				t.Errorf("GetPullRequest().Reactions = %+v, want 1 thumbs_up and 1 heart", got.Reactions)
+			}
+
+			// 验证评审结论
+			if len(got.Reviews) != 2 {
+				t.Fatalf("GetPullRequest().Reviews length = %d, want 2", len(got.Reviews))
+			}
+			if got.Reviews[0].State != "CHANGES_REQUESTED" || got.Reviews[1].State != "APPROVED" {
+				t.Errorf("GetPullRequest().Reviews states = %s, %s", got.Reviews[0].State, got.Reviews[1].State)
+			}
+			if got.Reviews[0].ID != 1001 || got.Reviews[0].User.Login != "reviewer1" || got.Reviews[0].CommitID != "def456" {
+				t.Errorf("GetPullRequest().Reviews[0] = %+v", got.Reviews[0])
+			}
+
+			// 验证普通评论与评审评论按时间合并
+			wantIDs := []int64{3001, 2001, 3002, 2002}
+			if len(got.Comments) != len(wantIDs) {
+				t.Fatalf("GetPullRequest().Comments length = %d, want %d", len(got.Comments), len(wantIDs))
+			}
+			for i, id := range wantIDs {
+				if got.Comments[i].ID != id {
+					t.Errorf("GetPullRequest().Comments[%d].ID = %d, want %d", i, got.Comments[i].ID, id)
+				}
+			}
+			if !got.Comments[1].IsReviewComment() || got.Comments[0].IsReviewComment() {
+				t.Error("GetPullRequest() review comment flags are wrong")
+			}
+			if got.Comments[1].Reactions.Eyes != 1 {
+				t.Errorf("GetPullRequest().Comments[1].Reactions = %+v, want 1 eyes", got.Comments[1].Reactions)
+			}
+			if got.Comments[3].Line != 12 || got.Comments[3].InReplyToID != 2001 {
+				t.Errorf("GetPullRequest().Comments[3] line/reply = %d/%d, want 12/2001", got.Comments[3].Line, got.Comments[3].InReplyToID)
+			}
+		})
+	}
+}
+
+func TestMergeCommentsByTime(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	comment := func(id int64, offset time.Duration) *model.Comment {
+		return &model.Comment{ID: id, CreatedAt: base.Add(offset)}
+	}
+
+	tests := []struct {
+		name    string
+		groups  [][]*model.Comment
+		wantIDs []int64
+	}{
+		{
+			name:    "Empty",
+			groups:  nil,
+			wantIDs: nil,
+		},
+		{
+			name: "Interleaved",
+			groups: [][]*model.Comment{
+				{comment(1, 0), comment(3, 2*time.Hour)},
+				{comment(2, time.Hour), comment(4, 3*time.Hour)},
+			},
+			wantIDs: []int64{1, 2, 3, 4},
+		},
+		{
+			name: "Same timestamp keeps group order",
+			groups: [][]*model.Comment{
+				{comment(1, time.Hour)},
+				{comment(2, time.Hour)},
+			},
+			wantIDs: []int64{1, 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := mergeCommentsByTime(tt.groups...)
+			if len(got) != len(tt.wantIDs) {
+				t.Fatalf("mergeCommentsByTime() length = %d, want %d", len(got), len(tt.wantIDs))
+			}
+			for i, id := range tt.wantIDs {
+				if got[i].ID != id {
+					t.Errorf("mergeCommentsByTime()[%d].ID = %d, want %d", i, got[i].ID, id)
+				}
+			}
+		})
+	}
+}
+
+func TestConvertGitHubPullRequest(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      *github.PullRequest
+		wantNil    bool
+		wantStatus string
+		wantDraft  bool
+	}{
+		{
+			name:    "Nil Input",
+			input:   nil,
+			wantNil: true,
+		},
+		{
+			name: "Open Draft",
+			input: &github.PullRequest{
+				Number: github.Int(1),
+				State:  github.String("open"),
+				Draft:  github.Bool(true),
+			},
+			wantStatus: "open",
+			wantDraft:  true,
+		},
+		{
+			name: "Closed Without Merge",
+			input: &github.PullRequest{
+				Number: github.Int(2),
+				State:  github.String("closed"),
+			},
+			wantStatus: "closed",
+		},
+		{
+			name: "Merged",
+			input: &github.PullRequest{
+				Number:   github.Int(3),
+				State:    github.String("closed"),
+				MergedAt: &github.Timestamp{Time: time.Date(2024, 1, 6, 14, 0, 0, 0, time.UTC)},
+			},
+			wantStatus: "merged",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := convertGitHubPullRequest(tt.input)
+			if tt.wantNil {
+				if got != nil {
+					t.Errorf("convertGitHubPullRequest() expected nil, got %v", got)
+				}
+				return
+			}
+
+			if got.Status() != tt.wantStatus {
+				t.Errorf("convertGitHubPullRequest().Status() = %v, want %v", got.Status(), tt.wantStatus)
+			}
+			if got.Draft != tt.wantDraft {
+				t.Errorf("convertGitHubPullRequest().Draft = %v, want %v", got.Draft, tt.wantDraft)
+			}
+		})
+	}
+}
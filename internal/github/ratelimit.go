@@ -0,0 +1,269 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bigwhite/issue2md/internal/transport"
+)
+
+const (
+	// defaultRateLimitRetries 触发限流后默认的最大重试次数
+	defaultRateLimitRetries = 3
+	// secondaryRateLimitBackoff 次级限流未给出Retry-After时的初始等待时间，GitHub建议至少等待一分钟
+	secondaryRateLimitBackoff = time.Minute
+	// rateLimitResetSlack 等待额度重置时额外等待的时间，用于抵消本地与服务端的时钟误差
+	rateLimitResetSlack = time.Second
+)
+
+// RateLimitState 某类API资源（core、search、graphql等）的限流状态
+type RateLimitState struct {
+	Resource  string    `json:"resource"`
+	Limit     int       `json:"limit"`
+	Remaining int       `json:"remaining"`
+	Used      int       `json:"used"`
+	Reset     time.Time `json:"reset"`
+}
+
+// RateLimitWait 描述一次因限流产生的等待
+type RateLimitWait struct {
+	Resource  string        // 受限的API资源
+	Secondary bool          // 是否为次级限流
+	Duration  time.Duration // 等待时长
+	Attempt   int           // 第几次重试，请求前的等待为0
+}
+
+// RateLimitTransport 感知GitHub API限流的http.RoundTripper
+// 它根据X-RateLimit-*响应头记录各资源的剩余额度，额度耗尽时等待至重置时间；
+// 遇到主限流或次级限流的403/429响应时，按Retry-After或指数退避等待后重试。
+// 所有等待都会响应请求context的取消。
+type RateLimitTransport struct {
+	// Base 实际发送请求的RoundTripper，为nil时使用http.DefaultTransport
+	Base http.RoundTripper
+	// MaxRetries 触发限流后的最大重试次数
+	MaxRetries int
+	// OnWait 每次因限流等待前调用，可用于向用户报告进度
+	OnWait func(RateLimitWait)
+
+	mu     sync.Mutex
+	states map[string]RateLimitState
+	now    func() time.Time
+	sleep  func(ctx context.Context, d time.Duration) error
+}
+
+// NewRateLimitTransport 创建限流感知的RoundTripper
+func NewRateLimitTransport(base http.RoundTripper) *RateLimitTransport {
+	return &RateLimitTransport{
+		Base:       base,
+		MaxRetries: defaultRateLimitRetries,
+		states:     make(map[string]RateLimitState),
+		now:        time.Now,
+		sleep:      transport.SleepContext,
+	}
+}
+
+// RoundTrip 实现http.RoundTripper接口
+func (t *RateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	resource := rateLimitResource(req)
+
+	if wait := t.resetWait(resource); wait > 0 {
+		if err := t.wait(ctx, RateLimitWait{Resource: resource, Duration: wait}); err != nil {
+			return nil, err
+		}
+	}
+
+	backoff := secondaryRateLimitBackoff
+	for attempt := 1; ; attempt++ {
+		resp, err := t.base().RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+		if r := t.update(resp); r != "" {
+			resource = r
+		}
+
+		wait, secondary, limited := t.checkResponse(resp, resource, &backoff)
+		if !limited {
+			// 即使本次请求用尽了额度也立即返回，下一次请求前的resetWait检查会等待重置
+			return resp, nil
+		}
+
+		if attempt > t.MaxRetries || !transport.CanReplay(req) {
+			return resp, nil
+		}
+
+		transport.DrainBody(resp.Body)
+		if err := t.wait(ctx, RateLimitWait{Resource: resource, Secondary: secondary, Duration: wait, Attempt: attempt}); err != nil {
+			return nil, err
+		}
+		if req, err = transport.RewindRequest(req); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// State 返回指定资源最近一次记录的限流状态
+func (t *RateLimitTransport) State(resource string) (RateLimitState, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	state, ok := t.states[resource]
+	return state, ok
+}
+
+// States 返回所有已记录资源的限流状态
+func (t *RateLimitTransport) States() map[string]RateLimitState {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	states := make(map[string]RateLimitState, len(t.states))
+	for resource, state := range t.states {
+		states[resource] = state
+	}
+	return states
+}
+
+// base 返回实际发送请求的RoundTripper
+func (t *RateLimitTransport) base() http.RoundTripper {
+	if t.Base != nil {
+		return t.Base
+	}
+	return http.DefaultTransport
+}
+
+// update 根据响应头更新限流状态，返回响应所属的资源，无限流头时返回空字符串
+func (t *RateLimitTransport) update(resp *http.Response) string {
+	remaining, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return ""
+	}
+
+	state := RateLimitState{
+		Resource:  resp.Header.Get("X-RateLimit-Resource"),
+		Remaining: remaining,
+	}
+	if state.Resource == "" {
+		state.Resource = rateLimitResource(resp.Request)
+	}
+	state.Limit, _ = strconv.Atoi(resp.Header.Get("X-RateLimit-Limit"))
+	state.Used, _ = strconv.Atoi(resp.Header.Get("X-RateLimit-Used"))
+	if reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		state.Reset = time.Unix(reset, 0)
+	}
+
+	t.mu.Lock()
+	t.states[state.Resource] = state
+	t.mu.Unlock()
+	return state.Resource
+}
+
+// resetWait 资源额度已耗尽时返回距重置的等待时间，否则返回0
+func (t *RateLimitTransport) resetWait(resource string) time.Duration {
+	state, ok := t.State(resource)
+	if !ok || state.Remaining > 0 || state.Reset.IsZero() {
+		return 0
+	}
+
+	wait := state.Reset.Sub(t.now())
+	if wait <= 0 {
+		return 0
+	}
+	return wait + rateLimitResetSlack
+}
+
+// checkResponse 判断响应是否因限流被拒绝，返回重试前的等待时间和是否为次级限流
+// backoff为次级限流未给出Retry-After时的当前退避时间，每次使用后翻倍
+func (t *RateLimitTransport) checkResponse(resp *http.Response, resource string, backoff *time.Duration) (time.Duration, bool, bool) {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return 0, false, false
+	}
+
+	if wait, ok := parseRetryAfter(resp.Header.Get("Retry-After"), t.now()); ok {
+		return wait, true, true
+	}
+
+	if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		wait := t.resetWait(resource)
+		if wait <= 0 {
+			wait = rateLimitResetSlack
+		}
+		return wait, false, true
+	}
+
+	if isSecondaryRateLimit(resp) {
+		wait := *backoff
+		*backoff *= 2
+		return wait, true, true
+	}
+
+	return 0, false, false
+}
+
+// wait 通知OnWait后等待指定时间
+func (t *RateLimitTransport) wait(ctx context.Context, w RateLimitWait) error {
+	if t.OnWait != nil {
+		t.OnWait(w)
+	}
+	if err := t.sleep(ctx, w.Duration); err != nil {
+		return fmt.Errorf("waiting for %s rate limit: %w", w.Resource, err)
+	}
+	return nil
+}
+
+// rateLimitResource 根据请求路径推断所属的限流资源
+func rateLimitResource(req *http.Request) string {
+	if req == nil || req.URL == nil {
+		return "core"
+	}
+
+	path := req.URL.Path
+	switch {
+	case strings.HasSuffix(path, "/graphql"):
+		return "graphql"
+	case strings.Contains(path, "/search/"):
+		return "search"
+	default:
+		return "core"
+	}
+}
+
+// parseRetryAfter 解析Retry-After头，支持秒数和HTTP日期两种格式
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		if wait := at.Sub(now); wait > 0 {
+			return wait, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// isSecondaryRateLimit 根据响应正文判断是否为次级限流
+// 读取的正文会被放回，调用方仍可完整读取
+func isSecondaryRateLimit(resp *http.Response) bool {
+	if resp.Body == nil {
+		return false
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return false
+	}
+
+	message := strings.ToLower(string(body))
+	return strings.Contains(message, "secondary rate limit") || strings.Contains(message, "abuse detection")
+}
@@ -0,0 +1,291 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// rateLimitResponse 模拟服务器依次返回的一个响应
+type rateLimitResponse struct {
+	status  int
+	headers map[string]string
+	body    string
+}
+
+// createRateLimitMockServer 创建依次返回给定响应的服务器，超出部分重复最后一个响应
+func createRateLimitMockServer(responses []rateLimitResponse) (*httptest.Server, *int32) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		i := int(atomic.AddInt32(&calls, 1)) - 1
+		if i >= len(responses) {
+			i = len(responses) - 1
+		}
+		resp := responses[i]
+		for key, value := range resp.headers {
+			w.Header().Set(key, value)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(resp.status)
+		w.Write([]byte(resp.body))
+	}))
+	return server, &calls
+}
+
+// recordSleep 记录等待时长而不真正等待
+func recordSleep(waits *[]time.Duration) func(context.Context, time.Duration) error {
+	return func(ctx context.Context, d time.Duration) error {
+		*waits = append(*waits, d)
+		return ctx.Err()
+	}
+}
+
+func TestRateLimitTransport(t *testing.T) {
+	reset := strconv.FormatInt(time.Now().Add(30*time.Second).Unix(), 10)
+	primaryLimited := rateLimitResponse{
+		status: http.StatusForbidden,
+		headers: map[string]string{
+			"X-RateLimit-Limit":     "5000",
+			"X-RateLimit-Remaining": "0",
+			"X-RateLimit-Reset":     reset,
+			"X-RateLimit-Resource":  "core",
+		},
+		body: `{"message": "API rate limit exceeded"}`,
+	}
+	secondaryLimited := rateLimitResponse{
+		status: http.StatusForbidden,
+		body:   `{"message": "You have exceeded a secondary rate limit. Please wait a few minutes before you try again."}`,
+	}
+	ok := rateLimitResponse{
+		status: http.StatusOK,
+		headers: map[string]string{
+			"X-RateLimit-Limit":     "5000",
+			"X-RateLimit-Remaining": "4999",
+			"X-RateLimit-Used":      "1",
+			"X-RateLimit-Reset":     reset,
+			"X-RateLimit-Resource":  "core",
+		},
+		body: `{}`,
+	}
+
+	tests := []struct {
+		name          string
+		responses     []rateLimitResponse
+		wantStatus    int
+		wantCalls     int32
+		wantWaits     int
+		wantSecondary []bool
+		checkWaits    func(t *testing.T, waits []time.Duration)
+	}{
+		{
+			name:       "No rate limit",
+			responses:  []rateLimitResponse{ok},
+			wantStatus: http.StatusOK,
+			wantCalls:  1,
+		},
+		{
+			name:          "Primary rate limit waits until reset",
+			responses:     []rateLimitResponse{primaryLimited, ok},
+			wantStatus:    http.StatusOK,
+			wantCalls:     2,
+			wantWaits:     1,
+			wantSecondary: []bool{false},
+			checkWaits: func(t *testing.T, waits []time.Duration) {
+				if waits[0] < 20*time.Second || waits[0] > 32*time.Second {
+					t.Errorf("primary wait = %v, want about 30s", waits[0])
+				}
+			},
+		},
+		{
+			name: "Retry-After honoured",
+			responses: []rateLimitResponse{
+				{status: http.StatusTooManyRequests, headers: map[string]string{"Retry-After": "7"}},
+				ok,
+			},
+			wantStatus:    http.StatusOK,
+			wantCalls:     2,
+			wantWaits:     1,
+			wantSecondary: []bool{true},
+			checkWaits: func(t *testing.T, waits []time.Duration) {
+				if waits[0] != 7*time.Second {
+					t.Errorf("Retry-After wait = %v, want 7s", waits[0])
+				}
+			},
+		},
+		{
+			name:          "Secondary rate limit backs off exponentially",
+			responses:     []rateLimitResponse{secondaryLimited, secondaryLimited, ok},
+			wantStatus:    http.StatusOK,
+			wantCalls:     3,
+			wantWaits:     2,
+			wantSecondary: []bool{true, true},
+			checkWaits: func(t *testing.T, waits []time.Duration) {
+				if waits[0] != time.Minute || waits[1] != 2*time.Minute {
+					t.Errorf("secondary waits = %v, want [1m 2m]", waits)
+				}
+			},
+		},
+		{
+			name:          "Retries exhausted",
+			responses:     []rateLimitResponse{secondaryLimited},
+			wantStatus:    http.StatusForbidden,
+			wantCalls:     defaultRateLimitRetries + 1,
+			wantWaits:     defaultRateLimitRetries,
+			wantSecondary: []bool{true, true, true},
+		},
+		{
+			name: "Ordinary 403 is not retried",
+			responses: []rateLimitResponse{
+				{status: http.StatusForbidden, body: `{"message": "Resource not accessible by integration"}`},
+			},
+			wantStatus: http.StatusForbidden,
+			wantCalls:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, calls := createRateLimitMockServer(tt.responses)
+			defer server.Close()
+
+			var waits []time.Duration
+			var events []RateLimitWait
+			transport := NewRateLimitTransport(server.Client().Transport)
+			transport.sleep = recordSleep(&waits)
+			transport.OnWait = func(w RateLimitWait) { events = append(events, w) }
+
+			req, _ := http.NewRequest(http.MethodGet, server.URL+"/repos/o/r/issues/1", nil)
+			resp, err := transport.RoundTrip(req)
+			if err != nil {
+				t.Fatalf("RoundTrip() unexpected error = %v", err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("RoundTrip() status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if got := atomic.LoadInt32(calls); got != tt.wantCalls {
+				t.Errorf("server calls = %d, want %d", got, tt.wantCalls)
+			}
+			if len(waits) != tt.wantWaits || len(events) != tt.wantWaits {
+				t.Fatalf("waits = %v, events = %v, want %d", waits, events, tt.wantWaits)
+			}
+			for i, secondary := range tt.wantSecondary {
+				if events[i].Secondary != secondary || events[i].Attempt != i+1 {
+					t.Errorf("events[%d] = %+v, want Secondary %v, Attempt %d", i, events[i], secondary, i+1)
+				}
+			}
+			if tt.checkWaits != nil {
+				tt.checkWaits(t, waits)
+			}
+		})
+	}
+}
+
+func TestRateLimitTransportState(t *testing.T) {
+	reset := time.Now().Add(time.Minute).Truncate(time.Second)
+	server, _ := createRateLimitMockServer([]rateLimitResponse{{
+		status: http.StatusOK,
+		headers: map[string]string{
+			"X-RateLimit-Limit":     "5000",
+			"X-RateLimit-Remaining": "0",
+			"X-RateLimit-Used":      "5000",
+			"X-RateLimit-Reset":     strconv.FormatInt(reset.Unix(), 10),
+		},
+	}})
+	defer server.Close()
+
+	var waits []time.Duration
+	transport := NewRateLimitTransport(server.Client().Transport)
+	transport.sleep = recordSleep(&waits)
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/search/issues", nil)
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip() unexpected error = %v", err)
+	}
+	resp.Body.Close()
+
+	// 没有X-RateLimit-Resource头时按路径推断资源
+	state, ok := transport.State("search")
+	if !ok {
+		t.Fatalf("State(search) not recorded, states = %v", transport.States())
+	}
+	want := RateLimitState{Resource: "search", Limit: 5000, Remaining: 0, Used: 5000, Reset: reset}
+	if state.Resource != want.Resource || state.Limit != want.Limit || state.Remaining != want.Remaining ||
+		state.Used != want.Used || !state.Reset.Equal(want.Reset) {
+		t.Errorf("State(search) = %+v, want %+v", state, want)
+	}
+
+	// 成功响应用尽额度时立即返回，不等待重置
+	if len(waits) != 0 {
+		t.Errorf("waits = %v, want none before returning the response", waits)
+	}
+
+	// 下一次请求前再次检查额度
+	if wait := transport.resetWait("search"); wait <= 0 {
+		t.Errorf("resetWait(search) = %v, want positive", wait)
+	}
+	if wait := transport.resetWait("core"); wait != 0 {
+		t.Errorf("resetWait(core) = %v, want 0", wait)
+	}
+
+	req, _ = http.NewRequest(http.MethodGet, server.URL+"/search/issues", nil)
+	if resp, err = transport.RoundTrip(req); err != nil {
+		t.Fatalf("RoundTrip() unexpected error = %v", err)
+	}
+	resp.Body.Close()
+	if len(waits) != 1 || waits[0] <= 0 {
+		t.Errorf("waits = %v, want one wait until reset before the next request", waits)
+	}
+}
+
+func TestRateLimitTransportCanceled(t *testing.T) {
+	server, calls := createRateLimitMockServer([]rateLimitResponse{
+		{status: http.StatusTooManyRequests, headers: map[string]string{"Retry-After": "3600"}},
+	})
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	transport := NewRateLimitTransport(server.Client().Transport)
+	transport.OnWait = func(RateLimitWait) { cancel() }
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/repos/o/r/issues/1", nil)
+	_, err := transport.RoundTrip(req)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("RoundTrip() error = %v, want context.Canceled", err)
+	}
+	if got := atomic.LoadInt32(calls); got != 1 {
+		t.Errorf("server calls = %d, want 1", got)
+	}
+}
+
+func TestGetIssueWithRateLimit(t *testing.T) {
+	server, calls := createRateLimitMockServer([]rateLimitResponse{
+		{status: http.StatusForbidden, headers: map[string]string{"Retry-After": "1"}, body: `{"message": "secondary rate limit"}`},
+		{status: http.StatusOK, body: mockIssueJSON},
+	})
+	defer server.Close()
+
+	client := NewClientWithHTTPClient(server.Client(), "test-token")
+	client.Client.BaseURL, _ = url.Parse(server.URL + "/")
+	var waits []time.Duration
+	client.RateLimiter.sleep = recordSleep(&waits)
+
+	issue, err := client.GetIssue(context.Background(), "testowner", "testrepo", 123)
+	if err != nil {
+		t.Fatalf("GetIssue() unexpected error = %v", err)
+	}
+	if issue.Title != "Test Issue Title" {
+		t.Errorf("GetIssue().Title = %v, want Test Issue Title", issue.Title)
+	}
+	if got := atomic.LoadInt32(calls); got != 2 || len(waits) != 1 || waits[0] != time.Second {
+		t.Errorf("calls = %d, waits = %v, want 2 calls and one 1s wait", got, waits)
+	}
+}
@@ -0,0 +1,167 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bigwhite/issue2md/internal/model"
+)
+
+// issueResolutionQuery 查询关闭Issue的Pull Request和最近一次关闭事件的关闭者
+const issueResolutionQuery = `query($owner: String!, $repo: String!, $number: Int!) {
+  repository(owner: $owner, name: $repo) {
+    issue(number: $number) {
+      closedByPullRequestsReferences(first: 25, includeClosedPrs: true) {
+        nodes { ` + graphQLClosingPullRequestFields + ` }
+      }
+      timelineItems(last: 1, itemTypes: [CLOSED_EVENT]) {
+        nodes {
+          ... on ClosedEvent {
+            closer {
+              __typename
+              ... on Commit { oid messageHeadline url }
+              ... on PullRequest { ` + graphQLClosingPullRequestFields + ` }
+            }
+          }
+        }
+      }
+    }
+  }
+}`
+
+// graphQLClosingPullRequestFields 查询关闭Issue的PR时使用的字段
+const graphQLClosingPullRequestFields = `number title url state mergedAt repository { nameWithOwner } mergeCommit { oid url messageHeadline }`
+
+// graphQLClosingPullRequest GraphQL中关闭Issue的PR
+type graphQLClosingPullRequest struct {
+	Number     int        `json:"number"`
+	Title      string     `json:"title"`
+	URL        string     `json:"url"`
+	State      string     `json:"state"`
+	MergedAt   *time.Time `json:"mergedAt"`
+	Repository struct {
+		NameWithOwner string `json:"nameWithOwner"`
+	} `json:"repository"`
+	MergeCommit *struct {
+		OID             string `json:"oid"`
+		URL             string `json:"url"`
+		MessageHeadline string `json:"messageHeadline"`
+	} `json:"mergeCommit"`
+}
+
+// toClosingPullRequest 转换为内部ClosingPullRequest结构
+func (pr *graphQLClosingPullRequest) toClosingPullRequest() *model.ClosingPullRequest {
+	return &model.ClosingPullRequest{
+		Repo:     pr.Repository.NameWithOwner,
+		Number:   pr.Number,
+		Title:    pr.Title,
+		URL:      pr.URL,
+		State:    strings.ToLower(pr.State),
+		MergedAt: pr.MergedAt,
+	}
+}
+
+// mergeCommit 返回PR的合并提交，未合并时返回nil
+func (pr *graphQLClosingPullRequest) mergeCommit() *model.ClosingCommit {
+	if pr.MergeCommit == nil || pr.MergeCommit.OID == "" {
+		return nil
+	}
+	return &model.ClosingCommit{
+		SHA:     pr.MergeCommit.OID,
+		Message: pr.MergeCommit.MessageHeadline,
+		URL:     pr.MergeCommit.URL,
+	}
+}
+
+// graphQLCloser GraphQL中关闭事件的关闭者，提交与PR共用url字段
+type graphQLCloser struct {
+	Typename        string `json:"__typename"`
+	OID             string `json:"oid"`
+	MessageHeadline string `json:"messageHeadline"`
+	graphQLClosingPullRequest
+}
+
+// GetIssueResolution 获取关闭Issue的Pull Request和提交
+// 由PR关闭时提交取关闭者PR的合并提交，关闭事件没有记录关闭者时取第一个已合并的关联PR的合并提交；
+// 没有任何关联的PR或提交、或服务器不支持查询的字段（如旧版GitHub Enterprise Server）时返回nil
+func (c *GitHubClient) GetIssueResolution(ctx context.Context, owner, repo string, issueNumber int) (*model.Resolution, error) {
+	var data struct {
+		Repository struct {
+			Issue *struct {
+				ClosedByPullRequestsReferences struct {
+					Nodes []*graphQLClosingPullRequest `json:"nodes"`
+				} `json:"closedByPullRequestsReferences"`
+				TimelineItems struct {
+					Nodes []*struct {
+						Closer *graphQLCloser `json:"closer"`
+					} `json:"nodes"`
+				} `json:"timelineItems"`
+			} `json:"issue"`
+		} `json:"repository"`
+	}
+
+	err := c.graphQL(ctx, issueResolutionQuery, map[string]interface{}{
+		"owner":  owner,
+		"repo":   repo,
+		"number": issueNumber,
+	}, &data)
+	if isUnsupported(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get resolution for issue %d from %s/%s: %w", issueNumber, owner, repo, err)
+	}
+
+	issue := data.Repository.Issue
+	if issue == nil {
+		return nil, fmt.Errorf("failed to get resolution for issue %d from %s/%s: not found", issueNumber, owner, repo)
+	}
+
+	resolution := &model.Resolution{}
+	seen := make(map[string]bool)
+	addPullRequest := func(pr *graphQLClosingPullRequest) {
+		key := fmt.Sprintf("%s#%d", pr.Repository.NameWithOwner, pr.Number)
+		if pr.Number == 0 || seen[key] {
+			return
+		}
+		seen[key] = true
+		resolution.ClosedBy = append(resolution.ClosedBy, pr.toClosingPullRequest())
+	}
+
+	for _, pr := range issue.ClosedByPullRequestsReferences.Nodes {
+		if pr != nil {
+			addPullRequest(pr)
+		}
+	}
+	for _, node := range issue.TimelineItems.Nodes {
+		if node == nil || node.Closer == nil {
+			continue
+		}
+		switch node.Closer.Typename {
+		case "PullRequest":
+			addPullRequest(&node.Closer.graphQLClosingPullRequest)
+			resolution.Commit = node.Closer.mergeCommit()
+		case "Commit":
+			resolution.Commit = &model.ClosingCommit{
+				SHA:     node.Closer.OID,
+				Message: node.Closer.MessageHeadline,
+				URL:     node.Closer.URL,
+			}
+		}
+	}
+	if resolution.Commit == nil {
+		for _, pr := range issue.ClosedByPullRequestsReferences.Nodes {
+			if pr != nil && pr.mergeCommit() != nil {
+				resolution.Commit = pr.mergeCommit()
+				break
+			}
+		}
+	}
+
+	if len(resolution.ClosedBy) == 0 && resolution.Commit == nil {
+		return nil, nil
+	}
+	return resolution, nil
+}
@@ -0,0 +1,102 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/bigwhite/issue2md/internal/model"
+)
+
+// mockResolutionJSON 按Issue编号返回的解决方式查询结果
+// Issue 1由两个PR关闭（其中一个同时是关闭者），Issue 2由提交关闭，Issue 3没有关联，
+// Issue 4关联了已合并的PR但关闭事件没有记录关闭者
+var mockResolutionJSON = map[float64]string{
+	1: `{"data": {"repository": {"issue": {
+		"closedByPullRequestsReferences": {"nodes": [
+			{"number": 10, "title": "Fix crash", "url": "https://github.com/o/r/pull/10", "state": "MERGED", "mergedAt": "2024-01-05T09:58:00Z", "repository": {"nameWithOwner": "o/r"}, "mergeCommit": {"oid": "5f3e2d1c", "url": "https://github.com/o/r/commit/5f3e2d1c", "messageHeadline": "Merge pull request #10 from o/fix"}},
+			{"number": 11, "title": "Alternative fix", "url": "https://github.com/o/r/pull/11", "state": "CLOSED", "mergedAt": null, "repository": {"nameWithOwner": "o/r"}}
+		]},
+		"timelineItems": {"nodes": [
+			{"closer": {"__typename": "PullRequest", "number": 10, "title": "Fix crash", "url": "https://github.com/o/r/pull/10", "state": "MERGED", "mergedAt": "2024-01-05T09:58:00Z", "repository": {"nameWithOwner": "o/r"}, "mergeCommit": {"oid": "5f3e2d1c", "url": "https://github.com/o/r/commit/5f3e2d1c", "messageHeadline": "Merge pull request #10 from o/fix"}}}
+		]}
+	}}}}`,
+	2: `{"data": {"repository": {"issue": {
+		"closedByPullRequestsReferences": {"nodes": []},
+		"timelineItems": {"nodes": [
+			{"closer": {"__typename": "Commit", "oid": "abc1234def", "messageHeadline": "Fix typo, closes #2", "url": "https://github.com/o/r/commit/abc1234def"}}
+		]}
+	}}}}`,
+	3: `{"data": {"repository": {"issue": {
+		"closedByPullRequestsReferences": {"nodes": []},
+		"timelineItems": {"nodes": [{"closer": null}]}
+	}}}}`,
+	4: `{"data": {"repository": {"issue": {
+		"closedByPullRequestsReferences": {"nodes": [
+			{"number": 12, "title": "Open fix", "url": "https://github.com/o/r/pull/12", "state": "OPEN", "mergedAt": null, "repository": {"nameWithOwner": "o/r"}, "mergeCommit": null},
+			{"number": 13, "title": "Squashed fix", "url": "https://github.com/o/r/pull/13", "state": "MERGED", "mergedAt": "2024-02-01T10:00:00Z", "repository": {"nameWithOwner": "o/r"}, "mergeCommit": {"oid": "9a8b7c6d", "url": "https://github.com/o/r/commit/9a8b7c6d", "messageHeadline": "Squashed fix (#13)"}}
+		]},
+		"timelineItems": {"nodes": [{"closer": null}]}
+	}}}}`,
+}
+
+func TestGetIssueResolution(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req graphQLRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode graphql request: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(mockResolutionJSON[req.Variables["number"].(float64)]))
+	}))
+	defer server.Close()
+
+	client := NewClientWithHTTPClient(server.Client(), "test-token")
+	client.Client.BaseURL, _ = url.Parse(server.URL + "/")
+
+	resolution, err := client.GetIssueResolution(context.Background(), "o", "r", 1)
+	if err != nil {
+		t.Fatalf("GetIssueResolution() unexpected error = %v", err)
+	}
+	if len(resolution.ClosedBy) != 2 {
+		t.Fatalf("GetIssueResolution() = %+v, want two pull requests", resolution)
+	}
+	wantMerge := &model.ClosingCommit{SHA: "5f3e2d1c", Message: "Merge pull request #10 from o/fix", URL: "https://github.com/o/r/commit/5f3e2d1c"}
+	if resolution.Commit == nil || *resolution.Commit != *wantMerge {
+		t.Errorf("Commit = %+v, want merge commit of the closing pull request %+v", resolution.Commit, wantMerge)
+	}
+	merged := resolution.ClosedBy[0]
+	wantMergedAt := time.Date(2024, 1, 5, 9, 58, 0, 0, time.UTC)
+	if merged.Number != 10 || merged.State != "merged" || merged.MergedAt == nil || !merged.MergedAt.Equal(wantMergedAt) {
+		t.Errorf("ClosedBy[0] = %+v", merged)
+	}
+	if resolution.ClosedBy[1].MergedAt != nil || resolution.ClosedBy[1].State != "closed" {
+		t.Errorf("ClosedBy[1] = %+v, want closed without merge", resolution.ClosedBy[1])
+	}
+
+	resolution, err = client.GetIssueResolution(context.Background(), "o", "r", 2)
+	if err != nil {
+		t.Fatalf("GetIssueResolution() unexpected error = %v", err)
+	}
+	want := &model.ClosingCommit{SHA: "abc1234def", Message: "Fix typo, closes #2", URL: "https://github.com/o/r/commit/abc1234def"}
+	if resolution.Commit == nil || *resolution.Commit != *want {
+		t.Errorf("Commit = %+v, want %+v", resolution.Commit, want)
+	}
+
+	resolution, err = client.GetIssueResolution(context.Background(), "o", "r", 3)
+	if err != nil || resolution != nil {
+		t.Errorf("GetIssueResolution() without closer = %+v, %v, want nil", resolution, err)
+	}
+
+	resolution, err = client.GetIssueResolution(context.Background(), "o", "r", 4)
+	if err != nil {
+		t.Fatalf("GetIssueResolution() unexpected error = %v", err)
+	}
+	if resolution.Commit == nil || resolution.Commit.SHA != "9a8b7c6d" || resolution.Commit.Message != "Squashed fix (#13)" {
+		t.Errorf("Commit = %+v, want merge commit of the merged pull request", resolution.Commit)
+	}
+}
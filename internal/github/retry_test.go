@@ -0,0 +1,65 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bigwhite/issue2md/internal/transport"
+)
+
+func TestGetIssueWithRetry(t *testing.T) {
+	tests := []struct {
+		name         string
+		failures     int32
+		wantErr      bool
+		wantAttempts int
+	}{
+		{name: "Transient failure recovered", failures: 1},
+		{name: "Retries exhausted", failures: 10, wantErr: true, wantAttempts: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if atomic.AddInt32(&calls, 1) <= tt.failures {
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(mockIssueJSON))
+			}))
+			defer server.Close()
+
+			client := NewClientWithHTTPClient(server.Client(), "test-token")
+			client.Client.BaseURL, _ = url.Parse(server.URL + "/")
+			client.Retrier.Policy.BaseDelay = time.Millisecond
+			client.Retrier.Policy.MaxDelay = time.Millisecond
+
+			issue, err := client.GetIssue(context.Background(), "testowner", "testrepo", 123)
+			if tt.wantErr {
+				var retryErr *transport.RetryError
+				if !errors.As(err, &retryErr) || retryErr.Attempts != tt.wantAttempts {
+					t.Fatalf("GetIssue() error = %v, want RetryError after %d attempts", err, tt.wantAttempts)
+				}
+				if !strings.Contains(err.Error(), "failed to get issue 123") {
+					t.Errorf("GetIssue() error = %v, expected to contain 'failed to get issue 123'", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetIssue() unexpected error = %v", err)
+			}
+			if issue.Title != "Test Issue Title" {
+				t.Errorf("GetIssue().Title = %v, want Test Issue Title", issue.Title)
+			}
+		})
+	}
+}
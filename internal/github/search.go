@@ -0,0 +1,191 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v56/github"
+
+	"github.com/bigwhite/issue2md/internal/model"
+)
+
+// searchResultLimit 搜索接口对单个查询最多返回的结果数
+const searchResultLimit = 1000
+
+// searchEpoch 按创建时间拆分搜索时的默认下界，早于GitHub上线时间
+var searchEpoch = time.Date(2007, 10, 1, 0, 0, 0, 0, time.UTC)
+
+// createdQualifierRegexp 匹配查询中的created:限定符，取反的-created:不匹配
+var createdQualifierRegexp = regexp.MustCompile(`(^|\s)created:(\S+)`)
+
+// SearchIssues 按GitHub搜索语法查找Issue和Pull Request，返回全部结果
+// 搜索接口对单个查询最多返回1000条结果，结果超过上限时按创建时间二分拆分查询，
+// 直到每个时间段都不超过上限；查询中已有的created:限定符会作为拆分的时间范围。
+// 结果按创建时间升序排列。
+func (c *GitHubClient) SearchIssues(ctx context.Context, query string) ([]*model.Issue, error) {
+	base, from, to, err := splitCreatedQualifier(query)
+	if err != nil {
+		return nil, err
+	}
+	if to.IsZero() {
+		to = time.Now().UTC().Truncate(time.Second)
+	}
+
+	s := &issueSearch{client: c, query: base, seen: make(map[string]bool)}
+	if err := s.searchRange(ctx, from, to); err != nil {
+		return nil, err
+	}
+	return s.issues, nil
+}
+
+// issueSearch 一次拆分搜索的状态
+type issueSearch struct {
+	client *GitHubClient
+	query  string
+	seen   map[string]bool
+	issues []*model.Issue
+}
+
+// searchRange 搜索创建时间在[from, to]内的结果，超过上限时拆分为两段
+func (s *issueSearch) searchRange(ctx context.Context, from, to time.Time) error {
+	if to.Before(from) {
+		return nil
+	}
+
+	query := fmt.Sprintf("%s created:%s..%s", s.query, formatSearchTime(from), formatSearchTime(to))
+	opts := &github.SearchOptions{
+		Sort:        "created",
+		Order:       "asc",
+		ListOptions: github.ListOptions{PerPage: defaultPerPage},
+	}
+
+	for {
+		// 调用 GitHub API 获取一页搜索结果
+		result, resp, err := s.client.Client.Search.Issues(ctx, query, opts)
+		if err != nil {
+			return fmt.Errorf("failed to search issues with %q: %w", query, err)
+		}
+
+		if opts.Page == 0 && result.GetTotal() > searchResultLimit {
+			if !to.After(from) {
+				return fmt.Errorf("search %q matches %d results created at %s, more than the %d the search API can return",
+					s.query, result.GetTotal(), formatSearchTime(from), searchResultLimit)
+			}
+			mid := from.Add(to.Sub(from) / 2).Truncate(time.Second)
+			if err := s.searchRange(ctx, from, mid); err != nil {
+				return err
+			}
+			return s.searchRange(ctx, mid.Add(time.Second), to)
+		}
+
+		for _, gitHubIssue := range result.Issues {
+			issue := convertGitHubIssue(gitHubIssue)
+			if issue == nil {
+				continue
+			}
+			// 翻页期间有新结果插入时，同一条结果可能出现在相邻两页
+			key := fmt.Sprintf("%s#%d", issue.Repository, issue.Number)
+			if s.seen[key] {
+				continue
+			}
+			s.seen[key] = true
+			s.issues = append(s.issues, issue)
+		}
+
+		if resp.NextPage == 0 {
+			return nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+// splitCreatedQualifier 从查询中取出created:限定符，返回其余查询和限定的创建时间范围
+// 没有限定下界时from为searchEpoch，没有限定上界时to为零值；有多个限定符时取交集
+func splitCreatedQualifier(query string) (string, time.Time, time.Time, error) {
+	from, to := searchEpoch, time.Time{}
+	var parseErr error
+
+	rest := createdQualifierRegexp.ReplaceAllStringFunc(query, func(match string) string {
+		value := createdQualifierRegexp.FindStringSubmatch(match)[2]
+		lo, hi, err := parseCreatedRange(value)
+		if err != nil {
+			if parseErr == nil {
+				parseErr = err
+			}
+			return match
+		}
+		if lo.After(from) {
+			from = lo
+		}
+		if !hi.IsZero() && (to.IsZero() || hi.Before(to)) {
+			to = hi
+		}
+		return ""
+	})
+	if parseErr != nil {
+		return "", time.Time{}, time.Time{}, parseErr
+	}
+
+	return strings.Join(strings.Fields(rest), " "), from, to, nil
+}
+
+// parseCreatedRange 解析created:限定符的值，支持 >X、>=X、<X、<=X、X..Y、X 几种形式，
+// X为YYYY-MM-DD日期或带时间的ISO 8601时间，*表示不限；返回闭区间，不限的一端为零值
+func parseCreatedRange(value string) (time.Time, time.Time, error) {
+	var lo, hi time.Time
+	var err error
+
+	switch {
+	case strings.HasPrefix(value, ">="):
+		lo, _, err = parseSearchTime(value[2:])
+	case strings.HasPrefix(value, ">"):
+		_, lo, err = parseSearchTime(value[1:])
+		lo = lo.Add(time.Second)
+	case strings.HasPrefix(value, "<="):
+		_, hi, err = parseSearchTime(value[2:])
+	case strings.HasPrefix(value, "<"):
+		hi, _, err = parseSearchTime(value[1:])
+		hi = hi.Add(-time.Second)
+	default:
+		start, end, ok := strings.Cut(value, "..")
+		if !ok {
+			lo, hi, err = parseSearchTime(value)
+			break
+		}
+		if start != "*" {
+			if lo, _, err = parseSearchTime(start); err != nil {
+				break
+			}
+		}
+		if end != "*" {
+			_, hi, err = parseSearchTime(end)
+		}
+	}
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid created qualifier %q: %w", value, err)
+	}
+	return lo, hi, nil
+}
+
+// parseSearchTime 解析搜索语法中的时间，返回它覆盖的第一秒和最后一秒
+// 日期覆盖当天（UTC）全天，不带时区的时间按UTC处理
+func parseSearchTime(value string) (time.Time, time.Time, error) {
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, t.AddDate(0, 0, 1).Add(-time.Second), nil
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02T15:04"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			t = t.UTC().Truncate(time.Second)
+			return t, t, nil
+		}
+	}
+	return time.Time{}, time.Time{}, fmt.Errorf("unrecognized time %q", value)
+}
+
+// formatSearchTime 按搜索语法格式化时间
+func formatSearchTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02T15:04:05Z")
+}
@@ -0,0 +1,208 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// createSearchMockServer 创建搜索模拟服务器
+// 数据集中的Issue按编号顺序每小时创建一个，服务器按created范围过滤、分页，并像GitHub一样拒绝读取第1000条之后的结果
+func createSearchMockServer(t *testing.T, count int, start time.Time, queries *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/search/issues" {
+			http.NotFound(w, r)
+			return
+		}
+		q := r.URL.Query().Get("q")
+		*queries = append(*queries, q)
+
+		from, to := searchEpoch, time.Now()
+		for _, field := range strings.Fields(q) {
+			if strings.HasPrefix(field, "created:") {
+				lo, hi, _ := strings.Cut(strings.TrimPrefix(field, "created:"), "..")
+				from, _ = time.Parse(time.RFC3339, lo)
+				to, _ = time.Parse(time.RFC3339, hi)
+			}
+		}
+
+		var matched []map[string]interface{}
+		for i := 1; i <= count; i++ {
+			created := start.Add(time.Duration(i-1) * time.Hour)
+			if created.Before(from) || created.After(to) {
+				continue
+			}
+			matched = append(matched, map[string]interface{}{
+				"number":         i,
+				"title":          "Issue " + strconv.Itoa(i),
+				"created_at":     created.Format(time.RFC3339),
+				"repository_url": "https://api.github.com/repos/o/r",
+			})
+		}
+
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page == 0 {
+			page = 1
+		}
+		perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+		if page*perPage > searchResultLimit {
+			http.Error(w, `{"message": "Only the first 1000 search results are available"}`, http.StatusUnprocessableEntity)
+			return
+		}
+
+		lo, hi := (page-1)*perPage, page*perPage
+		if lo > len(matched) {
+			lo = len(matched)
+		}
+		if hi > len(matched) {
+			hi = len(matched)
+		}
+		if hi < len(matched) {
+			next := *r.URL
+			values := next.Query()
+			values.Set("page", strconv.Itoa(page+1))
+			next.RawQuery = values.Encode()
+			w.Header().Set("Link", `<http://`+r.Host+next.String()+`>; rel="next"`)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"total_count": len(matched),
+			"items":       matched[lo:hi],
+		})
+	}))
+}
+
+func TestSearchIssues(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		count     int
+		query     string
+		wantFirst int
+		wantLast  int
+		wantSplit bool
+	}{
+		{
+			name:      "Under the result cap",
+			count:     250,
+			query:     "repo:o/r is:closed",
+			wantFirst: 1,
+			wantLast:  250,
+		},
+		{
+			name:      "Split past the result cap",
+			count:     2500,
+			query:     "repo:o/r label:incident",
+			wantFirst: 1,
+			wantLast:  2500,
+			wantSplit: true,
+		},
+		{
+			name:      "Created qualifier bounds the range",
+			count:     2500,
+			query:     "repo:o/r created:>=2024-01-02 created:<2024-01-03",
+			wantFirst: 25,
+			wantLast:  48,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var queries []string
+			server := createSearchMockServer(t, tt.count, start, &queries)
+			defer server.Close()
+
+			client := NewClientWithHTTPClient(server.Client(), "test-token")
+			client.Client.BaseURL, _ = url.Parse(server.URL + "/")
+
+			issues, err := client.SearchIssues(context.Background(), tt.query)
+			if err != nil {
+				t.Fatalf("SearchIssues() unexpected error = %v", err)
+			}
+			if want := tt.wantLast - tt.wantFirst + 1; len(issues) != want {
+				t.Fatalf("SearchIssues() returned %d issues, want %d", len(issues), want)
+			}
+			for i, issue := range issues {
+				if issue.Number != tt.wantFirst+i {
+					t.Fatalf("issues[%d].Number = %d, want %d", i, issue.Number, tt.wantFirst+i)
+				}
+			}
+			if issues[0].Repository != "o/r" {
+				t.Errorf("Repository = %q, want o/r", issues[0].Repository)
+			}
+
+			distinct := make(map[string]bool)
+			for _, q := range queries {
+				if strings.Count(q, "created:") != 1 {
+					t.Errorf("query %q should carry exactly one created qualifier", q)
+				}
+				distinct[q] = true
+			}
+			if split := len(distinct) > 1; split != tt.wantSplit {
+				t.Errorf("split into %d queries, want split %v", len(distinct), tt.wantSplit)
+			}
+		})
+	}
+}
+
+func TestParseCreatedRange(t *testing.T) {
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	endOfDay := day.AddDate(0, 0, 1).Add(-time.Second)
+
+	tests := []struct {
+		value   string
+		wantLo  time.Time
+		wantHi  time.Time
+		wantErr bool
+	}{
+		{value: ">2024-03-01", wantLo: endOfDay.Add(time.Second)},
+		{value: ">=2024-03-01", wantLo: day},
+		{value: "<2024-03-01", wantHi: day.Add(-time.Second)},
+		{value: "<=2024-03-01", wantHi: endOfDay},
+		{value: "2024-03-01", wantLo: day, wantHi: endOfDay},
+		{value: "2024-03-01..*", wantLo: day},
+		{value: "*..2024-03-01", wantHi: endOfDay},
+		{value: "2024-03-01T12:00:00+02:00..2024-03-01T12:30:00Z", wantLo: day.Add(10 * time.Hour), wantHi: day.Add(12*time.Hour + 30*time.Minute)},
+		{value: "yesterday", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		lo, hi, err := parseCreatedRange(tt.value)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("parseCreatedRange(%q) expected error, but got nil", tt.value)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseCreatedRange(%q) unexpected error = %v", tt.value, err)
+			continue
+		}
+		if !lo.Equal(tt.wantLo) || !hi.Equal(tt.wantHi) {
+			t.Errorf("parseCreatedRange(%q) = %v, %v, want %v, %v", tt.value, lo, hi, tt.wantLo, tt.wantHi)
+		}
+	}
+}
+
+func TestSplitCreatedQualifier(t *testing.T) {
+	rest, from, to, err := splitCreatedQualifier(`repo:o/r created:>2024-01-01 label:"help wanted" -created:2024-02-01`)
+	if err != nil {
+		t.Fatalf("splitCreatedQualifier() unexpected error = %v", err)
+	}
+	if want := `repo:o/r label:"help wanted" -created:2024-02-01`; rest != want {
+		t.Errorf("rest = %q, want %q", rest, want)
+	}
+	if want := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC); !from.Equal(want) {
+		t.Errorf("from = %v, want %v", from, want)
+	}
+	if !to.IsZero() {
+		t.Errorf("to = %v, want zero", to)
+	}
+}
@@ -0,0 +1,122 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+
+	"github.com/google/go-github/v56/github"
+
+	"github.com/bigwhite/issue2md/internal/model"
+)
+
+// taskListReferenceRegexp 匹配只包含一个Issue引用的任务列表项，即GitHub任务列表的"tracked by"关系
+// 支持 #12、owner/repo#12 和Issue链接三种写法
+var taskListReferenceRegexp = regexp.MustCompile(
+	`(?m)^[ \t]*[-*+][ \t]+\[[ xX]\][ \t]+(?:([\w.-]+/[\w.-]+)?#(\d+)|https?://[^/\s]+/([\w.-]+/[\w.-]+)/issues/(\d+))[ \t]*\r?$`)
+
+// GetSubIssues 获取Issue的子Issue，按GitHub中的顺序排列
+// 服务器不支持子Issue（如较旧的GitHub Enterprise Server）时返回空列表
+func (c *GitHubClient) GetSubIssues(ctx context.Context, owner, repo string, issueNumber int) ([]*model.SubIssue, error) {
+	var subIssues []*model.SubIssue
+	for page := 1; page != 0; {
+		u := fmt.Sprintf("repos/%s/%s/issues/%d/sub_issues?per_page=%d&page=%d", owner, repo, issueNumber, defaultPerPage, page)
+		req, err := c.Client.NewRequest(http.MethodGet, u, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create sub-issues request: %w", err)
+		}
+
+		// 调用 GitHub API 获取一页子Issue
+		var gitHubIssues []*github.Issue
+		resp, err := c.Client.Do(ctx, req, &gitHubIssues)
+		if err != nil {
+			if isNotFound(err) {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("failed to get sub-issues for issue %d from %s/%s: %w", issueNumber, owner, repo, err)
+		}
+
+		for _, gitHubIssue := range gitHubIssues {
+			if gitHubIssue != nil {
+				subIssues = append(subIssues, &model.SubIssue{IssueReference: *convertIssueReference(gitHubIssue, owner, repo)})
+			}
+		}
+		page = resp.NextPage
+	}
+	return subIssues, nil
+}
+
+// GetParentIssue 获取Issue的父Issue，没有父Issue时返回nil
+func (c *GitHubClient) GetParentIssue(ctx context.Context, owner, repo string, issueNumber int) (*model.IssueReference, error) {
+	u := fmt.Sprintf("repos/%s/%s/issues/%d/parent", owner, repo, issueNumber)
+	req, err := c.Client.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create parent issue request: %w", err)
+	}
+
+	// 调用 GitHub API 获取父Issue，没有父Issue时返回404
+	var gitHubIssue github.Issue
+	if _, err := c.Client.Do(ctx, req, &gitHubIssue); err != nil {
+		if isNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get parent of issue %d from %s/%s: %w", issueNumber, owner, repo, err)
+	}
+	return convertIssueReference(&gitHubIssue, owner, repo), nil
+}
+
+// TrackedIssues 从Issue正文的任务列表中提取被跟踪的Issue
+// owner和repo为正文所属仓库，用于补全 #12 这样的短引用；重复的引用只保留第一个
+func TrackedIssues(body, owner, repo string) []*model.SubIssue {
+	var refs []*model.SubIssue
+	seen := make(map[string]bool)
+	for _, m := range taskListReferenceRegexp.FindAllStringSubmatch(body, -1) {
+		fullName, number := m[1], m[2]
+		if m[4] != "" {
+			fullName, number = m[3], m[4]
+		}
+		if fullName == "" {
+			fullName = owner + "/" + repo
+		}
+		n, err := strconv.Atoi(number)
+		if err != nil {
+			continue
+		}
+
+		key := fmt.Sprintf("%s#%d", fullName, n)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		refs = append(refs, &model.SubIssue{IssueReference: model.IssueReference{Type: "issue", Repo: fullName, Number: n}})
+	}
+	return refs
+}
+
+// convertIssueReference 将GitHub API的Issue转换为引用，owner和repo为响应中没有仓库信息时的默认仓库
+func convertIssueReference(gitHubIssue *github.Issue, owner, repo string) *model.IssueReference {
+	ref := &model.IssueReference{
+		Type:   "issue",
+		Repo:   repositoryFromURL(gitHubIssue.GetRepositoryURL()),
+		Number: gitHubIssue.GetNumber(),
+		Title:  gitHubIssue.GetTitle(),
+		State:  gitHubIssue.GetState(),
+		URL:    gitHubIssue.GetHTMLURL(),
+	}
+	if gitHubIssue.IsPullRequest() {
+		ref.Type = "pull"
+	}
+	if ref.Repo == "" {
+		ref.Repo = owner + "/" + repo
+	}
+	return ref
+}
+
+// isNotFound 判断错误是否为GitHub API返回的404
+func isNotFound(err error) bool {
+	var errResp *github.ErrorResponse
+	return errors.As(err, &errResp) && errResp.Response != nil && errResp.Response.StatusCode == http.StatusNotFound
+}
@@ -0,0 +1,112 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+// createSubIssueMockServer 创建子Issue模拟服务器
+// Issue 1有两页子Issue，Issue 2的父Issue为Issue 1，Issue 3没有父Issue，Issue 4所在仓库不支持子Issue
+func createSubIssueMockServer() *httptest.Server {
+	mux := http.NewServeMux()
+	var server *httptest.Server
+
+	mux.HandleFunc("/repos/o/r/issues/1/sub_issues", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("page") == "2" {
+			w.Write([]byte(`[{"number": 9, "title": "Other repo", "state": "open", "repository_url": "https://api.github.com/repos/o/other"}]`))
+			return
+		}
+		w.Header().Set("Link", fmt.Sprintf(`<%s/repos/o/r/issues/1/sub_issues?page=2>; rel="next"`, server.URL))
+		w.Write([]byte(`[{"number": 2, "title": "Child", "state": "closed", "html_url": "https://github.com/o/r/issues/2"}]`))
+	})
+	mux.HandleFunc("/repos/o/r/issues/2/parent", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"number": 1, "title": "Epic", "state": "open", "repository_url": "https://api.github.com/repos/o/r"}`))
+	})
+	mux.HandleFunc("/repos/o/r/issues/4/sub_issues", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	server = httptest.NewServer(mux)
+	return server
+}
+
+func TestGetSubIssues(t *testing.T) {
+	server := createSubIssueMockServer()
+	defer server.Close()
+
+	client := NewClientWithHTTPClient(server.Client(), "test-token")
+	client.Client.BaseURL, _ = url.Parse(server.URL + "/")
+	client.Retrier.Policy.MaxAttempts = 1
+
+	refs, err := client.GetSubIssues(context.Background(), "o", "r", 1)
+	if err != nil {
+		t.Fatalf("GetSubIssues() unexpected error = %v", err)
+	}
+	if len(refs) != 2 {
+		t.Fatalf("GetSubIssues() returned %d sub-issues, want 2", len(refs))
+	}
+	if refs[0].Repo != "o/r" || refs[0].Number != 2 || refs[0].State != "closed" || refs[0].Title != "Child" {
+		t.Errorf("refs[0] = %+v", refs[0])
+	}
+	if refs[1].Repo != "o/other" || refs[1].Number != 9 {
+		t.Errorf("refs[1] = %+v, want o/other#9", refs[1])
+	}
+
+	// 没有子Issue接口时返回空列表
+	refs, err = client.GetSubIssues(context.Background(), "o", "r", 3)
+	if err != nil || len(refs) != 0 {
+		t.Errorf("GetSubIssues() without endpoint = %v, %v, want empty", refs, err)
+	}
+
+	if _, err := client.GetSubIssues(context.Background(), "o", "r", 4); err == nil {
+		t.Error("GetSubIssues() expected error for server failure, but got nil")
+	}
+}
+
+func TestGetParentIssue(t *testing.T) {
+	server := createSubIssueMockServer()
+	defer server.Close()
+
+	client := NewClientWithHTTPClient(server.Client(), "test-token")
+	client.Client.BaseURL, _ = url.Parse(server.URL + "/")
+
+	parent, err := client.GetParentIssue(context.Background(), "o", "r", 2)
+	if err != nil {
+		t.Fatalf("GetParentIssue() unexpected error = %v", err)
+	}
+	if parent == nil || parent.Repo != "o/r" || parent.Number != 1 || parent.Title != "Epic" {
+		t.Errorf("GetParentIssue() = %+v, want o/r#1", parent)
+	}
+
+	parent, err = client.GetParentIssue(context.Background(), "o", "r", 3)
+	if err != nil || parent != nil {
+		t.Errorf("GetParentIssue() without parent = %+v, %v, want nil", parent, err)
+	}
+}
+
+func TestTrackedIssues(t *testing.T) {
+	body := "## Tasks\r\n" +
+		"- [ ] #12\r\n" +
+		"- [x] other/repo#3\n" +
+		"  * [ ] https://github.com/o/r/issues/15\n" +
+		"- [ ] #12\n" +
+		"- [ ] #13 needs design\n" +
+		"- [ ] https://github.com/o/r/pull/16\n" +
+		"See #14 for details.\n"
+
+	refs := TrackedIssues(body, "o", "r")
+	var got []string
+	for _, ref := range refs {
+		got = append(got, fmt.Sprintf("%s#%d", ref.Repo, ref.Number))
+	}
+	want := []string{"o/r#12", "other/repo#3", "o/r#15"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("TrackedIssues() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,210 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bigwhite/issue2md/internal/model"
+)
+
+// issueTimelineQuery 分页查询Issue时间线上与分类、状态变化相关的事件
+// REST时间线接口缺少state_reason、转移来源等信息，因此使用GraphQL
+const issueTimelineQuery = `query($owner: String!, $repo: String!, $number: Int!, $cursor: String) {
+  repository(owner: $owner, name: $repo) {
+    issue(number: $number) {
+      timelineItems(first: 100, after: $cursor, itemTypes: [
+        LABELED_EVENT, UNLABELED_EVENT, ASSIGNED_EVENT, UNASSIGNED_EVENT,
+        MILESTONED_EVENT, DEMILESTONED_EVENT, CLOSED_EVENT, REOPENED_EVENT,
+        RENAMED_TITLE_EVENT, CROSS_REFERENCED_EVENT, TRANSFERRED_EVENT,
+        MARKED_AS_DUPLICATE_EVENT, UNMARKED_AS_DUPLICATE_EVENT
+      ]) {
+        pageInfo { hasNextPage endCursor }
+        nodes {
+          __typename
+          ... on LabeledEvent { createdAt actor { ` + graphQLActorFields + ` } label { name } }
+          ... on UnlabeledEvent { createdAt actor { ` + graphQLActorFields + ` } label { name } }
+          ... on AssignedEvent { createdAt actor { ` + graphQLActorFields + ` } assignee { ... on Actor { ` + graphQLActorFields + ` } } }
+          ... on UnassignedEvent { createdAt actor { ` + graphQLActorFields + ` } assignee { ... on Actor { ` + graphQLActorFields + ` } } }
+          ... on MilestonedEvent { createdAt actor { ` + graphQLActorFields + ` } milestoneTitle }
+          ... on DemilestonedEvent { createdAt actor { ` + graphQLActorFields + ` } milestoneTitle }
+          ... on ClosedEvent { createdAt actor { ` + graphQLActorFields + ` } stateReason }
+          ... on ReopenedEvent { createdAt actor { ` + graphQLActorFields + ` } }
+          ... on RenamedTitleEvent { createdAt actor { ` + graphQLActorFields + ` } previousTitle currentTitle }
+          ... on CrossReferencedEvent { createdAt actor { ` + graphQLActorFields + ` } source { ` + graphQLIssueReferenceFields + ` } }
+          ... on TransferredEvent { createdAt actor { ` + graphQLActorFields + ` } fromRepository { nameWithOwner } }
+          ... on MarkedAsDuplicateEvent { createdAt actor { ` + graphQLActorFields + ` } canonical { ` + graphQLIssueReferenceFields + ` } }
+          ... on UnmarkedAsDuplicateEvent { createdAt actor { ` + graphQLActorFields + ` } }
+        }
+      }
+    }
+  }
+}`
+
+// graphQLIssueReferenceFields 查询Issue或PR引用时使用的字段
+const graphQLIssueReferenceFields = `__typename
+  ... on Issue { number title url repository { nameWithOwner } }
+  ... on PullRequest { number title url repository { nameWithOwner } }`
+
+// graphQLIssueReference GraphQL中对Issue或PR的引用
+type graphQLIssueReference struct {
+	Typename   string `json:"__typename"`
+	Number     int    `json:"number"`
+	Title      string `json:"title"`
+	URL        string `json:"url"`
+	Repository struct {
+		NameWithOwner string `json:"nameWithOwner"`
+	} `json:"repository"`
+}
+
+// toReference 转换为内部IssueReference结构
+func (r *graphQLIssueReference) toReference() *model.IssueReference {
+	if r == nil || r.Number == 0 {
+		return nil
+	}
+
+	refType := "issue"
+	if r.Typename == "PullRequest" {
+		refType = "pull"
+	}
+	return &model.IssueReference{
+		Type:   refType,
+		Repo:   r.Repository.NameWithOwner,
+		Number: r.Number,
+		Title:  r.Title,
+		URL:    r.URL,
+	}
+}
+
+// graphQLTimelineItem GraphQL时间线中的一个事件，字段按事件类型择一填充
+type graphQLTimelineItem struct {
+	Typename       string                 `json:"__typename"`
+	CreatedAt      time.Time              `json:"createdAt"`
+	Actor          *graphQLActor          `json:"actor"`
+	Label          *struct{ Name string } `json:"label"`
+	Assignee       *graphQLActor          `json:"assignee"`
+	MilestoneTitle string                 `json:"milestoneTitle"`
+	StateReason    string                 `json:"stateReason"`
+	PreviousTitle  string                 `json:"previousTitle"`
+	CurrentTitle   string                 `json:"currentTitle"`
+	Source         *graphQLIssueReference `json:"source"`
+	Canonical      *graphQLIssueReference `json:"canonical"`
+	FromRepository *struct {
+		NameWithOwner string `json:"nameWithOwner"`
+	} `json:"fromRepository"`
+}
+
+// GetIssueTimeline 获取Issue时间线事件
+// 包括标签、指派、里程碑、关闭/重新打开、改名、交叉引用、转移和标记重复等事件，按发生时间排序；
+// 服务器不支持查询中的事件类型时返回空列表
+func (c *GitHubClient) GetIssueTimeline(ctx context.Context, owner, repo string, issueNumber int) ([]*model.TimelineEvent, error) {
+	var events []*model.TimelineEvent
+	var cursor interface{}
+	for {
+		var data struct {
+			Repository struct {
+				Issue *struct {
+					TimelineItems struct {
+						PageInfo graphQLPageInfo        `json:"pageInfo"`
+						Nodes    []*graphQLTimelineItem `json:"nodes"`
+					} `json:"timelineItems"`
+				} `json:"issue"`
+			} `json:"repository"`
+		}
+
+		err := c.graphQL(ctx, issueTimelineQuery, map[string]interface{}{
+			"owner":  owner,
+			"repo":   repo,
+			"number": issueNumber,
+			"cursor": cursor,
+		}, &data)
+		if isUnsupported(err) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get timeline for issue %d from %s/%s: %w", issueNumber, owner, repo, err)
+		}
+
+		issue := data.Repository.Issue
+		if issue == nil {
+			return nil, fmt.Errorf("failed to get timeline for issue %d from %s/%s: not found", issueNumber, owner, repo)
+		}
+
+		for _, node := range issue.TimelineItems.Nodes {
+			if event := convertGraphQLTimelineItem(node); event != nil {
+				events = append(events, event)
+			}
+		}
+
+		if !issue.TimelineItems.PageInfo.HasNextPage {
+			return events, nil
+		}
+		cursor = issue.TimelineItems.PageInfo.EndCursor
+	}
+}
+
+// convertGraphQLTimelineItem 将GraphQL时间线事件转换为内部TimelineEvent结构
+// 不关心的事件类型返回nil
+func convertGraphQLTimelineItem(node *graphQLTimelineItem) *model.TimelineEvent {
+	if node == nil {
+		return nil
+	}
+
+	event := &model.TimelineEvent{
+		Actor:     node.Actor.toUser(),
+		CreatedAt: node.CreatedAt,
+	}
+
+	switch node.Typename {
+	case "LabeledEvent", "UnlabeledEvent":
+		event.Event = model.EventLabeled
+		if node.Typename == "UnlabeledEvent" {
+			event.Event = model.EventUnlabeled
+		}
+		if node.Label != nil {
+			event.Label = node.Label.Name
+		}
+	case "AssignedEvent", "UnassignedEvent":
+		event.Event = model.EventAssigned
+		if node.Typename == "UnassignedEvent" {
+			event.Event = model.EventUnassigned
+		}
+		if node.Assignee != nil {
+			assignee := node.Assignee.toUser()
+			event.Assignee = &assignee
+		}
+	case "MilestonedEvent", "DemilestonedEvent":
+		event.Event = model.EventMilestoned
+		if node.Typename == "DemilestonedEvent" {
+			event.Event = model.EventDemilestoned
+		}
+		event.Milestone = node.MilestoneTitle
+	case "ClosedEvent":
+		event.Event = model.EventClosed
+		event.StateReason = strings.ToLower(node.StateReason)
+	case "ReopenedEvent":
+		event.Event = model.EventReopened
+	case "RenamedTitleEvent":
+		event.Event = model.EventRenamed
+		event.RenameFrom = node.PreviousTitle
+		event.RenameTo = node.CurrentTitle
+	case "CrossReferencedEvent":
+		event.Event = model.EventCrossReferenced
+		event.Source = node.Source.toReference()
+	case "TransferredEvent":
+		event.Event = model.EventTransferred
+		if node.FromRepository != nil {
+			event.FromRepo = node.FromRepository.NameWithOwner
+		}
+	case "MarkedAsDuplicateEvent":
+		event.Event = model.EventMarkedAsDuplicate
+		event.Source = node.Canonical.toReference()
+	case "UnmarkedAsDuplicateEvent":
+		event.Event = model.EventUnmarkedAsDuplicate
+	default:
+		return nil
+	}
+
+	return event
+}
@@ -0,0 +1,140 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bigwhite/issue2md/internal/model"
+)
+
+// mockTimelinePage1JSON 模拟Issue时间线查询的第一页
+const mockTimelinePage1JSON = `{"data": {"repository": {"issue": {"timelineItems": {
+	"pageInfo": {"hasNextPage": true, "endCursor": "t1"},
+	"nodes": [
+		{"__typename": "LabeledEvent", "createdAt": "2024-01-03T09:00:00Z", "actor": {"login": "alice", "__typename": "User"}, "label": {"name": "bug"}},
+		{"__typename": "AssignedEvent", "createdAt": "2024-01-03T09:05:00Z", "actor": {"login": "alice"}, "assignee": {"login": "bob", "url": "https://github.com/bob"}},
+		{"__typename": "RenamedTitleEvent", "createdAt": "2024-01-04T10:00:00Z", "actor": {"login": "bob"}, "previousTitle": "Crash", "currentTitle": "Crash on startup"},
+		{"__typename": "CrossReferencedEvent", "createdAt": "2024-01-05T10:00:00Z", "actor": {"login": "carol"}, "source": {"__typename": "PullRequest", "number": 99, "title": "Fix crash", "url": "https://github.com/testowner/testrepo/pull/99", "repository": {"nameWithOwner": "testowner/testrepo"}}},
+		{"__typename": "SubscribedEvent", "createdAt": "2024-01-05T11:00:00Z", "actor": {"login": "dave"}}
+	]
+}}}}}`
+
+// mockTimelinePage2JSON 模拟Issue时间线查询的第二页
+const mockTimelinePage2JSON = `{"data": {"repository": {"issue": {"timelineItems": {
+	"pageInfo": {"hasNextPage": false, "endCursor": "t2"},
+	"nodes": [
+		{"__typename": "MarkedAsDuplicateEvent", "createdAt": "2024-01-06T10:00:00Z", "actor": {"login": "alice"}, "canonical": {"__typename": "Issue", "number": 7, "url": "https://github.com/testowner/testrepo/issues/7", "repository": {"nameWithOwner": "testowner/testrepo"}}},
+		{"__typename": "ClosedEvent", "createdAt": "2024-01-06T10:01:00Z", "actor": {"login": "alice"}, "stateReason": "DUPLICATE"},
+		{"__typename": "TransferredEvent", "createdAt": "2024-01-07T10:00:00Z", "actor": null, "fromRepository": {"nameWithOwner": "testowner/oldrepo"}}
+	]
+}}}}}`
+
+// createTimelineMockServer 创建模拟Issue时间线GraphQL查询的服务器
+func createTimelineMockServer(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/graphql", func(w http.ResponseWriter, r *http.Request) {
+		var req graphQLRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode graphql request: %v", err)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case req.Variables["number"] == float64(404):
+			w.Write([]byte(`{"data": {"repository": {"issue": null}}, "errors": [{"type": "NOT_FOUND", "message": "Could not resolve to an Issue with the number of 404."}]}`))
+		case req.Variables["cursor"] == "t1":
+			w.Write([]byte(mockTimelinePage2JSON))
+		default:
+			w.Write([]byte(mockTimelinePage1JSON))
+		}
+	})
+
+	return httptest.NewServer(mux)
+}
+
+func TestGetIssueTimeline(t *testing.T) {
+	server := createTimelineMockServer(t)
+	defer server.Close()
+
+	tests := []struct {
+		name          string
+		number        int
+		wantErr       bool
+		errorContains string
+	}{
+		{
+			name:   "Successful Timeline Retrieval",
+			number: 123,
+		},
+		{
+			name:          "Issue Not Found",
+			number:        404,
+			wantErr:       true,
+			errorContains: "Could not resolve",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClientWithHTTPClient(server.Client(), "test-token")
+			client.Client.BaseURL, _ = url.Parse(server.URL + "/")
+
+			got, err := client.GetIssueTimeline(context.Background(), "testowner", "testrepo", tt.number)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("GetIssueTimeline() expected error, but got nil")
+				}
+				if !strings.Contains(err.Error(), tt.errorContains) {
+					t.Errorf("GetIssueTimeline() error = %v, expected to contain '%s'", err, tt.errorContains)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetIssueTimeline() unexpected error = %v", err)
+			}
+
+			// 两页都应被读取，不关心的事件类型被丢弃
+			wantEvents := []string{
+				model.EventLabeled, model.EventAssigned, model.EventRenamed, model.EventCrossReferenced,
+				model.EventMarkedAsDuplicate, model.EventClosed, model.EventTransferred,
+			}
+			if len(got) != len(wantEvents) {
+				t.Fatalf("GetIssueTimeline() length = %d, want %d", len(got), len(wantEvents))
+			}
+			for i, want := range wantEvents {
+				if got[i].Event != want {
+					t.Errorf("GetIssueTimeline()[%d].Event = %v, want %v", i, got[i].Event, want)
+				}
+			}
+
+			if got[0].Label != "bug" || got[0].Actor.Login != "alice" || !got[0].CreatedAt.Equal(time.Date(2024, 1, 3, 9, 0, 0, 0, time.UTC)) {
+				t.Errorf("labeled event = %+v", got[0])
+			}
+			if got[1].Assignee == nil || got[1].Assignee.Login != "bob" {
+				t.Errorf("assigned event Assignee = %v, want bob", got[1].Assignee)
+			}
+			if got[2].RenameFrom != "Crash" || got[2].RenameTo != "Crash on startup" {
+				t.Errorf("renamed event = %+v", got[2])
+			}
+			wantSource := model.IssueReference{Type: "pull", Repo: "testowner/testrepo", Number: 99, Title: "Fix crash", URL: "https://github.com/testowner/testrepo/pull/99"}
+			if got[3].Source == nil || *got[3].Source != wantSource {
+				t.Errorf("cross-referenced event Source = %+v, want %+v", got[3].Source, wantSource)
+			}
+			if got[4].Source == nil || got[4].Source.Type != "issue" || got[4].Source.Number != 7 {
+				t.Errorf("marked_as_duplicate event Source = %+v", got[4].Source)
+			}
+			if got[5].StateReason != "duplicate" {
+				t.Errorf("closed event StateReason = %v, want duplicate", got[5].StateReason)
+			}
+			if got[6].FromRepo != "testowner/oldrepo" || got[6].Actor.Login != "" {
+				t.Errorf("transferred event = %+v", got[6])
+			}
+		})
+	}
+}
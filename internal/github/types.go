@@ -2,100 +2,78 @@ package github
 
 import (
 	"context"
+	"fmt"
 	"net/http"
-	"time"
+	"net/url"
 
 	"github.com/google/go-github/v56/github"
+
+	"github.com/bigwhite/issue2md/internal/model"
+	"github.com/bigwhite/issue2md/internal/transport"
 )
 
 // Client 定义GitHub客户端接口
 type Client interface {
-	GetIssue(ctx context.Context, owner, repo string, issueNumber int) (*Issue, error)
-	GetIssueComments(ctx context.Context, owner, repo string, issueNumber int) ([]*Comment, error)
+	GetIssue(ctx context.Context, owner, repo string, issueNumber int) (*model.Issue, error)
+	GetIssueComments(ctx context.Context, owner, repo string, issueNumber int) ([]*model.Comment, error)
 }
 
 // GitHubClient GitHub客户端实现
 type GitHubClient struct {
 	Client *github.Client
+	// RateLimiter 处理API限流的传输层，可读取限流状态或设置等待回调
+	RateLimiter *RateLimitTransport
+	// Retrier 对瞬时故障重试的传输层，可通过Policy调整重试策略
+	Retrier *transport.RetryTransport
+	// Cache 磁盘缓存层，设置Dir后启用
+	Cache *transport.CacheTransport
 }
 
 // NewClient 创建新的GitHub客户端
 func NewClient(token string) *GitHubClient {
-	client := github.NewClient(nil).WithAuthToken(token)
-	return &GitHubClient{
-		Client: client,
-	}
+	return NewClientWithHTTPClient(nil, token)
 }
 
 // NewClientWithHTTPClient 使用自定义HTTP客户端创建GitHub客户端
+// httpClient的Transport会依次被包装为RetryTransport、限流感知的RateLimitTransport
+// 和CacheTransport（默认禁用）
 func NewClientWithHTTPClient(httpClient *http.Client, token string) *GitHubClient {
-	client := github.NewClient(httpClient).WithAuthToken(token)
-	return &GitHubClient{
-		Client: client,
+	if httpClient == nil {
+		httpClient = &http.Client{}
 	}
-}
-
-// Issue 表示一个GitHub Issue
-type Issue struct {
-	Number      int       `json:"number"`
-	Title       string    `json:"title"`
-	Body        string    `json:"body"`
-	State       string    `json:"state"`
-	User        User      `json:"user"`
-	Labels      []Label   `json:"labels"`
-	Assignees   []User    `json:"assignees"`
-	Milestone   *Milestone `json:"milestone,omitempty"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
-	ClosedAt    *time.Time `json:"closed_at,omitempty"`
-	URL         string    `json:"url"`
-	HTMLURL     string    `json:"html_url"`
-}
 
-// Comment 表示Issue评论
-type Comment struct {
-	ID        int64     `json:"id"`
-	Body      string    `json:"body"`
-	User      User      `json:"user"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
-	URL       string    `json:"url"`
-	HTMLURL   string    `json:"html_url"`
-}
+	retrier := transport.NewRetryTransport(httpClient.Transport)
+	rateLimiter := NewRateLimitTransport(retrier)
+	cache := transport.NewCacheTransport(rateLimiter, "", transport.CacheRevalidate)
+	wrapped := *httpClient
+	wrapped.Transport = cache
 
-// User 表示GitHub用户
-type User struct {
-	Login     string `json:"login"`
-	ID        int64  `json:"id"`
-	AvatarURL string `json:"avatar_url"`
-	HTMLURL   string `json:"html_url"`
-	Type      string `json:"type"`
-}
-
-// Label 表示Issue标签
-type Label struct {
-	Name        string `json:"name"`
-	Color       string `json:"color"`
-	Description string `json:"description,omitempty"`
+	client := github.NewClient(&wrapped).WithAuthToken(token)
+	return &GitHubClient{
+		Client:      client,
+		RateLimiter: rateLimiter,
+		Retrier:     retrier,
+		Cache:       cache,
+	}
 }
 
-// Milestone 表示Issue里程碑
-type Milestone struct {
-	Title       string     `json:"title"`
-	Number      int        `json:"number"`
-	State       string     `json:"state"`
-	Description string     `json:"description"`
-	CreatedAt   time.Time  `json:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at"`
-	DueDate     *time.Time `json:"due_date,omitempty"`
-	ClosedAt    *time.Time `json:"closed_at,omitempty"`
-}
+// NewEnterpriseClient 创建访问GitHub Enterprise Server的客户端
+// baseURL为REST接口地址，如 https://git.corp.example/api/v3/，只给出主机地址时会自动补全路径；
+// uploadURL为空时按baseURL的主机推导为 https://<host>/api/uploads/
+func NewEnterpriseClient(httpClient *http.Client, baseURL, uploadURL, token string) (*GitHubClient, error) {
+	if uploadURL == "" {
+		base, err := url.Parse(baseURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid enterprise base URL %q: %w", baseURL, err)
+		}
+		uploadURL = (&url.URL{Scheme: base.Scheme, Host: base.Host, Path: "/"}).String()
+	}
 
-// Repository 表示GitHub仓库
-type Repository struct {
-	Owner    string `json:"owner"`
-	Name     string `json:"name"`
-	FullName string `json:"full_name"`
-	URL      string `json:"url"`
-	HTMLURL  string `json:"html_url"`
+	c := NewClientWithHTTPClient(httpClient, token)
+	client, err := c.Client.WithEnterpriseURLs(baseURL, uploadURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid enterprise URLs %q, %q: %w", baseURL, uploadURL, err)
+	}
+	c.Client = client
+	return c, nil
 }
@@ -1,8 +1,10 @@
 package github
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
 	"testing"
-	"time"
 )
 
 func TestNewClient(t *testing.T) {
@@ -36,57 +38,78 @@ func TestNewClient(t *testing.T) {
 	}
 }
 
-func TestIssue(t *testing.T) {
-	now := time.Now()
-	issue := &Issue{
-		Number:    123,
-		Title:     "Test Issue",
-		Body:      "This is a test issue",
-		State:     "open",
-		CreatedAt: now,
-		UpdatedAt: now,
-	}
-
-	if issue.Number != 123 {
-		t.Errorf("Expected issue number 123, got %d", issue.Number)
-	}
-
-	if issue.Title != "Test Issue" {
-		t.Errorf("Expected title 'Test Issue', got %s", issue.Title)
-	}
-}
-
-func TestComment(t *testing.T) {
-	now := time.Now()
-	comment := &Comment{
-		ID:        456,
-		Body:      "This is a test comment",
-		CreatedAt: now,
-		UpdatedAt: now,
-	}
+func TestNewEnterpriseClient(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/repos/corp/infra/issues/7", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"number": 7, "title": "Enterprise issue", "state": "open"}`))
+	})
+	mux.HandleFunc("/api/graphql", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data": {"repository": {"issue": {"timelineItems": {"pageInfo": {"hasNextPage": false}, "nodes": []}}}}}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
 
-	if comment.ID != 456 {
-		t.Errorf("Expected comment ID 456, got %d", comment.ID)
-	}
-
-	if comment.Body != "This is a test comment" {
-		t.Errorf("Expected body 'This is a test comment', got %s", comment.Body)
+	tests := []struct {
+		name       string
+		baseURL    string
+		uploadURL  string
+		wantBase   string
+		wantUpload string
+		wantErr    bool
+	}{
+		{
+			name:       "Host only",
+			baseURL:    server.URL,
+			wantBase:   server.URL + "/api/v3/",
+			wantUpload: server.URL + "/api/uploads/",
+		},
+		{
+			name:       "Full API path",
+			baseURL:    server.URL + "/api/v3/",
+			uploadURL:  server.URL + "/api/uploads/",
+			wantBase:   server.URL + "/api/v3/",
+			wantUpload: server.URL + "/api/uploads/",
+		},
+		{
+			name:    "Invalid URL",
+			baseURL: "://bad",
+			wantErr: true,
+		},
 	}
-}
 
-func TestUser(t *testing.T) {
-	user := &User{
-		Login:     "testuser",
-		ID:        789,
-		AvatarURL: "https://example.com/avatar.jpg",
-		Type:      "User",
-	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewEnterpriseClient(server.Client(), tt.baseURL, tt.uploadURL, "test-token")
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("NewEnterpriseClient() expected error, but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewEnterpriseClient() unexpected error = %v", err)
+			}
 
-	if user.Login != "testuser" {
-		t.Errorf("Expected login 'testuser', got %s", user.Login)
-	}
+			if got := client.Client.BaseURL.String(); got != tt.wantBase {
+				t.Errorf("BaseURL = %v, want %v", got, tt.wantBase)
+			}
+			if got := client.Client.UploadURL.String(); got != tt.wantUpload {
+				t.Errorf("UploadURL = %v, want %v", got, tt.wantUpload)
+			}
+			if got, want := client.graphQLURL(), server.URL+"/api/graphql"; got != want {
+				t.Errorf("graphQLURL() = %v, want %v", got, want)
+			}
 
-	if user.ID != 789 {
-		t.Errorf("Expected ID 789, got %d", user.ID)
+			// REST和GraphQL请求都应发往企业实例的对应路径
+			issue, err := client.GetIssue(context.Background(), "corp", "infra", 7)
+			if err != nil || issue.Title != "Enterprise issue" {
+				t.Errorf("GetIssue() = %v, %v", issue, err)
+			}
+			if _, err := client.GetIssueTimeline(context.Background(), "corp", "infra", 7); err != nil {
+				t.Errorf("GetIssueTimeline() unexpected error = %v", err)
+			}
+		})
 	}
 }
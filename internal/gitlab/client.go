@@ -0,0 +1,71 @@
+package gitlab
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/bigwhite/issue2md/internal/rest"
+)
+
+// defaultPerPage GitLab REST API 单页允许的最大条目数
+const defaultPerPage = 100
+
+// Client GitLab客户端
+// 实现source.Client、source.EpicClient和source.AssetClient接口，Issue、Merge Request和Epic转换为与GitHub相同的数据模型；
+// GitLab没有对应概念的增强数据（时间线、子Issue、修改历史等）和Discussion、搜索等GitHub独有的资源不提供相应的可选接口
+type Client struct {
+	*rest.Client
+}
+
+// NewClient 创建GitLab客户端
+// baseURL为REST接口地址，只给出主机地址（如 https://gitlab.example.com）时自动补全 /api/v4/；
+// httpClient的Transport会依次被包装为RetryTransport和CacheTransport（默认禁用）；
+// token为个人或项目访问令牌，为空时只能访问公开项目
+func NewClient(httpClient *http.Client, baseURL, token string) (*Client, error) {
+	authorization := ""
+	if token != "" {
+		authorization = "Bearer " + token
+	}
+	client, err := rest.NewClient(httpClient, baseURL, "api/v4/", authorization)
+	if err != nil {
+		return nil, fmt.Errorf("invalid GitLab base URL %q", baseURL)
+	}
+	return &Client{Client: client}, nil
+}
+
+// get 请求接口并将JSON响应解码到out，返回下一页页码（0表示没有下一页）
+// path相对BaseURL，不以/开头
+func (c *Client) get(ctx context.Context, path string, query url.Values, out interface{}) (int, error) {
+	header, err := c.Get(ctx, path, query, out)
+	if err != nil {
+		return 0, err
+	}
+	next, _ := strconv.Atoi(header.Get("X-Next-Page"))
+	return next, nil
+}
+
+// pageQuery 返回分页查询参数
+func pageQuery(page int) url.Values {
+	return url.Values{
+		"page":     {strconv.Itoa(page)},
+		"per_page": {strconv.Itoa(defaultPerPage)},
+	}
+}
+
+// projectPath 返回接口路径中的项目标识，即URL编码后的 namespace/project
+func projectPath(owner, repo string) string {
+	return "projects/" + url.PathEscape(owner+"/"+repo)
+}
+
+// groupPath 返回接口路径中的群组标识，即URL编码后的群组完整路径
+func groupPath(group string) string {
+	return "groups/" + url.PathEscape(group)
+}
+
+// webHost 返回GitLab网页所在主机，上传的附件位于该主机上
+func (c *Client) webHost() string {
+	return c.BaseURL.Host
+}
@@ -0,0 +1,62 @@
+package gitlab
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bigwhite/issue2md/internal/model"
+)
+
+// GetEpic 获取群组的Epic及其全部评论，实现source.EpicClient接口
+// Epic下的Issue填充在SubIssues中，Repository为群组路径
+func (c *Client) GetEpic(ctx context.Context, group string, number int) (*model.Issue, []*model.Comment, error) {
+	var epic apiIssue
+	path := fmt.Sprintf("%s/epics/%d", groupPath(group), number)
+	if _, err := c.get(ctx, path, nil, &epic); err != nil {
+		return nil, nil, fmt.Errorf("failed to get epic &%d from %s: %w", number, group, err)
+	}
+
+	issue := convertIssue(&epic)
+	issue.Repository = group
+
+	for page := 1; page != 0; {
+		var children []*apiIssue
+		next, err := c.get(ctx, path+"/issues", pageQuery(page), &children)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get issues of epic &%d from %s: %w", number, group, err)
+		}
+		for _, child := range children {
+			if child != nil {
+				issue.SubIssues = append(issue.SubIssues, &model.SubIssue{IssueReference: childReference(child)})
+			}
+		}
+		page = next
+	}
+
+	// 备注接口使用Epic的全局ID而不是群组内编号
+	notesPath := fmt.Sprintf("%s/epics/%d/notes", groupPath(group), epic.ID)
+	var comments []*model.Comment
+	for page := 1; page != 0; {
+		pageComments, next, err := c.notes(ctx, notesPath, page, epic.WebURL)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get comments for epic &%d from %s: %w", number, group, err)
+		}
+		comments = append(comments, pageComments...)
+		page = next
+	}
+	return issue, comments, nil
+}
+
+// childReference 返回Epic下Issue的引用，仓库取自完整引用 group/project#N
+func childReference(issue *apiIssue) model.IssueReference {
+	repo, _, _ := strings.Cut(issue.References.Full, "#")
+	return model.IssueReference{
+		Type:   "issue",
+		Repo:   repo,
+		Number: issue.IID,
+		Title:  issue.Title,
+		State:  convertState(issue.State),
+		URL:    issue.WebURL,
+	}
+}
@@ -0,0 +1,56 @@
+package gitlab
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientGetEpic(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.EscapedPath() {
+		case "/api/v4/groups/group%2Fsub/epics/3":
+			w.Write([]byte(`{
+				"id": 301, "iid": 3, "title": "Q1 roadmap", "description": "Plan", "state": "opened",
+				"author": {"username": "alice"}, "labels": ["roadmap"],
+				"created_at": "2024-01-01T09:00:00Z", "updated_at": "2024-01-02T09:00:00Z",
+				"web_url": "https://gitlab.com/groups/group/sub/-/epics/3"
+			}`))
+		case "/api/v4/groups/group%2Fsub/epics/3/issues":
+			w.Write([]byte(`[
+				{"iid": 12, "title": "Crash on startup", "state": "closed", "web_url": "https://gitlab.com/group/sub/app/-/issues/12",
+				 "references": {"full": "group/sub/app#12"}}
+			]`))
+		case "/api/v4/groups/group%2Fsub/epics/301/notes":
+			w.Write([]byte(`[
+				{"id": 1, "body": "Kicking this off", "author": {"username": "bob"}, "created_at": "2024-01-01T10:00:00Z"}
+			]`))
+		default:
+			t.Errorf("unexpected path %s", r.URL.EscapedPath())
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	epic, comments, err := newTestClient(t, server).GetEpic(context.Background(), "group/sub", 3)
+	if err != nil {
+		t.Fatalf("GetEpic() error = %v", err)
+	}
+
+	if epic.Number != 3 || epic.Title != "Q1 roadmap" || epic.State != "open" || epic.Repository != "group/sub" {
+		t.Errorf("epic = %+v", epic)
+	}
+	if len(epic.SubIssues) != 1 {
+		t.Fatalf("got %d sub-issues, want 1", len(epic.SubIssues))
+	}
+	if child := epic.SubIssues[0]; child.Repo != "group/sub/app" || child.Number != 12 || child.State != "closed" || child.Type != "issue" {
+		t.Errorf("sub-issue = %+v", child.IssueReference)
+	}
+	if len(comments) != 1 || comments[0].Body != "Kicking this off" {
+		t.Errorf("comments = %+v", comments)
+	}
+	if want := "https://gitlab.com/groups/group/sub/-/epics/3#note_1"; comments[0].HTMLURL != want {
+		t.Errorf("HTMLURL = %q, want %q", comments[0].HTMLURL, want)
+	}
+}
@@ -0,0 +1,243 @@
+package gitlab
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bigwhite/issue2md/internal/model"
+)
+
+// apiUser GitLab接口返回的用户
+type apiUser struct {
+	ID        int64  `json:"id"`
+	Username  string `json:"username"`
+	Name      string `json:"name"`
+	AvatarURL string `json:"avatar_url"`
+	WebURL    string `json:"web_url"`
+}
+
+// apiMilestone GitLab接口返回的里程碑
+type apiMilestone struct {
+	IID         int       `json:"iid"`
+	Title       string    `json:"title"`
+	State       string    `json:"state"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	DueDate     string    `json:"due_date"`
+}
+
+// apiIssue GitLab接口返回的Issue，Merge Request和Epic共用其中的字段
+type apiIssue struct {
+	ID          int64         `json:"id"`
+	IID         int           `json:"iid"`
+	Title       string        `json:"title"`
+	Description string        `json:"description"`
+	State       string        `json:"state"`
+	Author      apiUser       `json:"author"`
+	Assignees   []apiUser     `json:"assignees"`
+	Labels      []string      `json:"labels"`
+	Milestone   *apiMilestone `json:"milestone"`
+	CreatedAt   time.Time     `json:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at"`
+	ClosedAt    *time.Time    `json:"closed_at"`
+	WebURL      string        `json:"web_url"`
+	Upvotes     int           `json:"upvotes"`
+	Downvotes   int           `json:"downvotes"`
+	References  struct {
+		Full string `json:"full"`
+	} `json:"references"`
+}
+
+// apiNote GitLab接口返回的备注（评论）
+type apiNote struct {
+	ID        int64     `json:"id"`
+	Type      string    `json:"type"` // DiscussionNote、DiffNote 或空
+	Body      string    `json:"body"`
+	Author    apiUser   `json:"author"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	System    bool      `json:"system"`
+	Position  *struct {
+		OldPath string `json:"old_path"`
+		NewPath string `json:"new_path"`
+		OldLine int    `json:"old_line"`
+		NewLine int    `json:"new_line"`
+	} `json:"position"`
+}
+
+// uploadLinkPattern 匹配Markdown中相对项目的上传文件链接，如 ](/uploads/<hash>/a.png)
+var uploadLinkPattern = regexp.MustCompile(`(\]\(|src=["'])(/uploads/)`)
+
+// GetIssue 获取项目的Issue
+func (c *Client) GetIssue(ctx context.Context, owner, repo string, issueNumber int) (*model.Issue, error) {
+	var issue apiIssue
+	path := fmt.Sprintf("%s/issues/%d", projectPath(owner, repo), issueNumber)
+	if _, err := c.get(ctx, path, nil, &issue); err != nil {
+		return nil, fmt.Errorf("failed to get issue #%d from %s/%s: %w", issueNumber, owner, repo, err)
+	}
+
+	result := convertIssue(&issue)
+	result.Repository = owner + "/" + repo
+	result.Body = absolutizeUploads(result.Body, projectWebURL(issue.WebURL))
+	return result, nil
+}
+
+// GetIssueComments 获取Issue的全部评论，系统备注（状态变更等）被跳过
+func (c *Client) GetIssueComments(ctx context.Context, owner, repo string, issueNumber int) ([]*model.Comment, error) {
+	var comments []*model.Comment
+	it := c.IssueComments(owner, repo, issueNumber)
+	for it.Next(ctx) {
+		comments = append(comments, it.Comment())
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+// IssueComments 返回按页拉取Issue评论的迭代器，评论按创建时间升序排列
+func (c *Client) IssueComments(owner, repo string, issueNumber int) *model.CommentIterator {
+	path := fmt.Sprintf("%s/issues/%d/notes", projectPath(owner, repo), issueNumber)
+	webURL := fmt.Sprintf("%s://%s/%s/%s/-/issues/%d", c.BaseURL.Scheme, c.webHost(), owner, repo, issueNumber)
+	return model.NewCommentIterator(func(ctx context.Context, page int) ([]*model.Comment, int, error) {
+		comments, next, err := c.notes(ctx, path, page, webURL)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to get comments for issue #%d from %s/%s: %w", issueNumber, owner, repo, err)
+		}
+		return comments, next, nil
+	})
+}
+
+// notes 获取一页备注并转换为评论，webURL为备注所属资源的网页地址
+func (c *Client) notes(ctx context.Context, path string, page int, webURL string) ([]*model.Comment, int, error) {
+	query := pageQuery(page)
+	query.Set("sort", "asc")
+	query.Set("order_by", "created_at")
+
+	var notes []*apiNote
+	next, err := c.get(ctx, path, query, &notes)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	comments := make([]*model.Comment, 0, len(notes))
+	for _, note := range notes {
+		if note == nil || note.System {
+			continue
+		}
+		comments = append(comments, convertNote(note, webURL))
+	}
+	return comments, next, nil
+}
+
+// convertIssue 把GitLab Issue转换为model.Issue
+func convertIssue(issue *apiIssue) *model.Issue {
+	result := &model.Issue{
+		Number:    issue.IID,
+		Title:     issue.Title,
+		Body:      issue.Description,
+		State:     convertState(issue.State),
+		User:      convertUser(issue.Author),
+		CreatedAt: issue.CreatedAt,
+		UpdatedAt: issue.UpdatedAt,
+		ClosedAt:  issue.ClosedAt,
+		HTMLURL:   issue.WebURL,
+		Reactions: model.Reactions{
+			TotalCount: issue.Upvotes + issue.Downvotes,
+			ThumbsUp:   issue.Upvotes,
+			ThumbsDown: issue.Downvotes,
+		},
+	}
+	for _, label := range issue.Labels {
+		result.Labels = append(result.Labels, model.Label{Name: label})
+	}
+	for _, assignee := range issue.Assignees {
+		result.Assignees = append(result.Assignees, convertUser(assignee))
+	}
+	if m := issue.Milestone; m != nil {
+		result.Milestone = &model.Milestone{
+			Title:       m.Title,
+			Number:      m.IID,
+			State:       m.State,
+			Description: m.Description,
+			CreatedAt:   m.CreatedAt,
+			UpdatedAt:   m.UpdatedAt,
+		}
+		if due, err := time.Parse("2006-01-02", m.DueDate); err == nil {
+			result.Milestone.DueDate = &due
+		}
+	}
+	return result
+}
+
+// convertNote 把GitLab备注转换为model.Comment
+// 代码评审备注的Path和Line取自备注位置，新文件一侧优先
+func convertNote(note *apiNote, webURL string) *model.Comment {
+	comment := &model.Comment{
+		ID:        note.ID,
+		Body:      absolutizeUploads(note.Body, projectWebURL(webURL)),
+		User:      convertUser(note.Author),
+		CreatedAt: note.CreatedAt,
+		UpdatedAt: note.UpdatedAt,
+		HTMLURL:   webURL + "#note_" + strconv.FormatInt(note.ID, 10),
+	}
+	if pos := note.Position; note.Type == "DiffNote" && pos != nil {
+		comment.Path, comment.Line = pos.NewPath, pos.NewLine
+		if comment.Line == 0 {
+			comment.Path, comment.Line = pos.OldPath, pos.OldLine
+		}
+		if comment.Path == "" {
+			comment.Path = pos.OldPath
+		}
+	}
+	return comment
+}
+
+// convertUser 把GitLab用户转换为model.User
+func convertUser(user apiUser) model.User {
+	return model.User{
+		Login:     user.Username,
+		ID:        user.ID,
+		AvatarURL: user.AvatarURL,
+		HTMLURL:   user.WebURL,
+		Type:      "User",
+	}
+}
+
+// convertState 把GitLab状态转换为GitHub的open/closed
+// Issue和Epic为opened/closed，Merge Request还有merged和locked
+func convertState(state string) string {
+	if state == "opened" {
+		return "open"
+	}
+	return "closed"
+}
+
+// projectWebURL 从资源网页地址（如 .../group/project/-/issues/1）推导项目网页地址
+func projectWebURL(webURL string) string {
+	if i := strings.Index(webURL, "/-/"); i >= 0 {
+		return webURL[:i]
+	}
+	return ""
+}
+
+// absolutizeUploads 把相对项目的上传文件链接改写为绝对地址，使导出的文档脱离GitLab后仍能访问附件
+func absolutizeUploads(body, projectURL string) string {
+	if projectURL == "" {
+		return body
+	}
+	return uploadLinkPattern.ReplaceAllString(body, "${1}"+projectURL+"${2}")
+}
+
+// sortComments 按创建时间排序评论
+func sortComments(comments []*model.Comment) {
+	sort.SliceStable(comments, func(i, j int) bool {
+		return comments[i].CreatedAt.Before(comments[j].CreatedAt)
+	})
+}
@@ -0,0 +1,164 @@
+package gitlab
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// mockIssueJSON 模拟GitLab Issue API响应
+const mockIssueJSON = `{
+	"id": 9001,
+	"iid": 12,
+	"title": "Crash on startup",
+	"description": "See ![log](/uploads/abc123/log.png)",
+	"state": "opened",
+	"author": {"id": 7, "username": "alice", "avatar_url": "https://gitlab.com/a.png", "web_url": "https://gitlab.com/alice"},
+	"assignees": [{"id": 8, "username": "bob"}],
+	"labels": ["bug", "priority::high"],
+	"milestone": {"iid": 3, "title": "v1.0", "state": "active", "due_date": "2024-02-01"},
+	"created_at": "2024-01-05T09:00:00Z",
+	"updated_at": "2024-01-06T14:00:00Z",
+	"web_url": "https://gitlab.com/group/sub/app/-/issues/12",
+	"upvotes": 3,
+	"downvotes": 1
+}`
+
+// newTestClient 创建访问测试服务器的客户端，禁用重试
+func newTestClient(t *testing.T, server *httptest.Server) *Client {
+	t.Helper()
+	client, err := NewClient(server.Client(), server.URL, "test-token")
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	client.Retrier.Policy.MaxAttempts = 1
+	return client
+}
+
+func TestNewClient(t *testing.T) {
+	tests := []struct {
+		name    string
+		baseURL string
+		want    string
+		wantErr bool
+	}{
+		{name: "host only", baseURL: "https://gitlab.example.com", want: "https://gitlab.example.com/api/v4/"},
+		{name: "full API URL", baseURL: "https://gitlab.example.com/api/v4", want: "https://gitlab.example.com/api/v4/"},
+		{name: "sub path", baseURL: "https://example.com/gitlab/", want: "https://example.com/gitlab/api/v4/"},
+		{name: "missing host", baseURL: "gitlab", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewClient(nil, tt.baseURL, "")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewClient() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && client.BaseURL.String() != tt.want {
+				t.Errorf("BaseURL = %q, want %q", client.BaseURL, tt.want)
+			}
+		})
+	}
+}
+
+func TestClientGetIssue(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/api/v4/projects/group%2Fsub%2Fapp/issues/12" {
+			t.Errorf("unexpected path %s", r.URL.EscapedPath())
+			http.NotFound(w, r)
+			return
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer test-token" {
+			t.Errorf("Authorization = %q", got)
+		}
+		w.Write([]byte(mockIssueJSON))
+	}))
+	defer server.Close()
+
+	issue, err := newTestClient(t, server).GetIssue(context.Background(), "group/sub", "app", 12)
+	if err != nil {
+		t.Fatalf("GetIssue() error = %v", err)
+	}
+
+	if issue.Number != 12 || issue.Title != "Crash on startup" || issue.State != "open" {
+		t.Errorf("issue = #%d %q %s", issue.Number, issue.Title, issue.State)
+	}
+	if issue.Repository != "group/sub/app" {
+		t.Errorf("Repository = %q", issue.Repository)
+	}
+	if issue.User.Login != "alice" || issue.User.HTMLURL != "https://gitlab.com/alice" {
+		t.Errorf("User = %+v", issue.User)
+	}
+	if len(issue.Labels) != 2 || issue.Labels[1].Name != "priority::high" {
+		t.Errorf("Labels = %+v", issue.Labels)
+	}
+	if len(issue.Assignees) != 1 || issue.Assignees[0].Login != "bob" {
+		t.Errorf("Assignees = %+v", issue.Assignees)
+	}
+	if issue.Milestone == nil || issue.Milestone.Title != "v1.0" || issue.Milestone.DueDate == nil {
+		t.Errorf("Milestone = %+v", issue.Milestone)
+	}
+	if issue.Reactions.ThumbsUp != 3 || issue.Reactions.ThumbsDown != 1 || issue.Reactions.TotalCount != 4 {
+		t.Errorf("Reactions = %+v", issue.Reactions)
+	}
+	if want := "See ![log](https://gitlab.com/group/sub/app/uploads/abc123/log.png)"; issue.Body != want {
+		t.Errorf("Body = %q, want %q", issue.Body, want)
+	}
+}
+
+func TestClientGetIssueNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message":"404 Not found"}`))
+	}))
+	defer server.Close()
+
+	_, err := newTestClient(t, server).GetIssue(context.Background(), "group", "app", 1)
+	if err == nil {
+		t.Fatal("GetIssue() error = nil, want error")
+	}
+}
+
+func TestClientGetIssueComments(t *testing.T) {
+	pages := map[string]string{
+		"1": `[
+			{"id": 1, "body": "First", "author": {"username": "bob"}, "created_at": "2024-01-05T10:00:00Z"},
+			{"id": 2, "body": "changed the description", "system": true, "author": {"username": "alice"}, "created_at": "2024-01-05T10:30:00Z"}
+		]`,
+		"2": `[
+			{"id": 3, "body": "Second", "author": {"username": "alice"}, "created_at": "2024-01-05T11:00:00Z"}
+		]`,
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/api/v4/projects/group%2Fapp/issues/12/notes" {
+			t.Errorf("unexpected path %s", r.URL.EscapedPath())
+		}
+		query := r.URL.Query()
+		if query.Get("sort") != "asc" || query.Get("per_page") != "100" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		page := query.Get("page")
+		if page == "1" {
+			w.Header().Set("X-Next-Page", "2")
+		}
+		w.Write([]byte(pages[page]))
+	}))
+	defer server.Close()
+
+	comments, err := newTestClient(t, server).GetIssueComments(context.Background(), "group", "app", 12)
+	if err != nil {
+		t.Fatalf("GetIssueComments() error = %v", err)
+	}
+
+	if len(comments) != 2 {
+		t.Fatalf("got %d comments, want 2 (system note skipped)", len(comments))
+	}
+	if comments[0].Body != "First" || comments[1].Body != "Second" || comments[1].User.Login != "alice" {
+		t.Errorf("comments = %+v, %+v", comments[0], comments[1])
+	}
+	if want := server.URL + "/group/app/-/issues/12#note_3"; comments[1].HTMLURL != want {
+		t.Errorf("HTMLURL = %q, want %q", comments[1].HTMLURL, want)
+	}
+}
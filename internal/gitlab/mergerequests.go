@@ -0,0 +1,116 @@
+package gitlab
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/bigwhite/issue2md/internal/model"
+)
+
+// apiMergeRequest GitLab接口返回的Merge Request
+type apiMergeRequest struct {
+	apiIssue
+	Draft          bool       `json:"draft"`
+	WorkInProgress bool       `json:"work_in_progress"` // 旧版本GitLab的草稿标志
+	MergedAt       *time.Time `json:"merged_at"`
+	MergedBy       *apiUser   `json:"merged_by"`
+	MergeUser      *apiUser   `json:"merge_user"`
+	MergeCommitSHA string     `json:"merge_commit_sha"`
+	SquashSHA      string     `json:"squash_commit_sha"`
+	SourceBranch   string     `json:"source_branch"`
+	TargetBranch   string     `json:"target_branch"`
+	Reviewers      []apiUser  `json:"reviewers"`
+	DiffRefs       *struct {
+		BaseSHA string `json:"base_sha"`
+		HeadSHA string `json:"head_sha"`
+	} `json:"diff_refs"`
+}
+
+// apiDiscussion GitLab接口返回的讨论，第一条备注发起讨论，其余为回复
+type apiDiscussion struct {
+	ID    string     `json:"id"`
+	Notes []*apiNote `json:"notes"`
+}
+
+// GetPullRequest 获取Merge Request及其全部讨论
+// 讨论的第一条备注作为评论，其余备注作为该评论的回复；代码评审讨论的Path和Line取自备注位置
+func (c *Client) GetPullRequest(ctx context.Context, owner, repo string, number int) (*model.PullRequest, error) {
+	var mr apiMergeRequest
+	path := fmt.Sprintf("%s/merge_requests/%d", projectPath(owner, repo), number)
+	if _, err := c.get(ctx, path, nil, &mr); err != nil {
+		return nil, fmt.Errorf("failed to get merge request !%d from %s/%s: %w", number, owner, repo, err)
+	}
+
+	issue := convertIssue(&mr.apiIssue)
+	issue.Repository = owner + "/" + repo
+	issue.Body = absolutizeUploads(issue.Body, projectWebURL(mr.WebURL))
+	pr := &model.PullRequest{
+		Issue:          *issue,
+		Draft:          mr.Draft || mr.WorkInProgress,
+		Merged:         mr.State == "merged",
+		MergedAt:       mr.MergedAt,
+		MergeCommitSHA: mr.MergeCommitSHA,
+		Head:           model.Branch{Label: mr.SourceBranch, Ref: mr.SourceBranch},
+		Base:           model.Branch{Label: mr.TargetBranch, Ref: mr.TargetBranch, Repo: owner + "/" + repo},
+	}
+	if pr.MergeCommitSHA == "" {
+		pr.MergeCommitSHA = mr.SquashSHA
+	}
+	if mr.DiffRefs != nil {
+		pr.Head.SHA, pr.Base.SHA = mr.DiffRefs.HeadSHA, mr.DiffRefs.BaseSHA
+	}
+	if user := mr.MergeUser; user != nil || mr.MergedBy != nil {
+		if user == nil {
+			user = mr.MergedBy
+		}
+		mergedBy := convertUser(*user)
+		pr.MergedBy = &mergedBy
+	}
+	for _, reviewer := range mr.Reviewers {
+		pr.RequestedReviewers = append(pr.RequestedReviewers, convertUser(reviewer))
+	}
+
+	comments, err := c.discussions(ctx, path+"/discussions", mr.WebURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get discussions for merge request !%d from %s/%s: %w", number, owner, repo, err)
+	}
+	pr.Comments = comments
+	return pr, nil
+}
+
+// discussions 获取全部讨论并转换为带回复的评论，按创建时间排序
+// 只包含系统备注的讨论被跳过
+func (c *Client) discussions(ctx context.Context, path, webURL string) ([]*model.Comment, error) {
+	var comments []*model.Comment
+	for page := 1; page != 0; {
+		var discussions []*apiDiscussion
+		next, err := c.get(ctx, path, pageQuery(page), &discussions)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, discussion := range discussions {
+			var first *model.Comment
+			for _, note := range discussion.Notes {
+				if note == nil || note.System {
+					continue
+				}
+				comment := convertNote(note, webURL)
+				if first == nil {
+					first = comment
+					continue
+				}
+				comment.InReplyToID = first.ID
+				first.Replies = append(first.Replies, comment)
+			}
+			if first != nil {
+				comments = append(comments, first)
+			}
+		}
+		page = next
+	}
+
+	sortComments(comments)
+	return comments, nil
+}
@@ -0,0 +1,102 @@
+package gitlab
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// mockMergeRequestJSON 模拟GitLab Merge Request API响应
+const mockMergeRequestJSON = `{
+	"id": 5001,
+	"iid": 7,
+	"title": "Add retry support",
+	"description": "Closes #12",
+	"state": "merged",
+	"draft": false,
+	"author": {"id": 7, "username": "alice"},
+	"labels": ["feature"],
+	"created_at": "2024-01-05T09:00:00Z",
+	"updated_at": "2024-01-06T14:00:00Z",
+	"merged_at": "2024-01-06T14:00:00Z",
+	"merge_user": {"id": 9, "username": "maintainer"},
+	"merge_commit_sha": "abc123",
+	"source_branch": "retry",
+	"target_branch": "main",
+	"reviewers": [{"id": 8, "username": "bob"}],
+	"diff_refs": {"base_sha": "789abc", "head_sha": "def456"},
+	"web_url": "https://gitlab.com/group/app/-/merge_requests/7"
+}`
+
+// mockDiscussionsJSON 模拟GitLab Merge Request Discussions API响应
+const mockDiscussionsJSON = `[
+	{
+		"id": "d1",
+		"notes": [
+			{"id": 11, "type": "DiffNote", "body": "Rename this?", "author": {"username": "bob"}, "created_at": "2024-01-05T11:00:00Z",
+			 "position": {"old_path": "retry.go", "new_path": "retry.go", "old_line": null, "new_line": 12}},
+			{"id": 12, "type": "DiffNote", "body": "Done.", "author": {"username": "alice"}, "created_at": "2024-01-05T12:00:00Z",
+			 "position": {"old_path": "retry.go", "new_path": "retry.go", "new_line": 12}}
+		]
+	},
+	{
+		"id": "d2",
+		"notes": [
+			{"id": 10, "type": null, "body": "Looks good overall", "author": {"username": "bob"}, "created_at": "2024-01-05T10:00:00Z"}
+		]
+	},
+	{
+		"id": "d3",
+		"notes": [
+			{"id": 13, "body": "approved this merge request", "system": true, "author": {"username": "bob"}, "created_at": "2024-01-06T10:00:00Z"}
+		]
+	}
+]`
+
+func TestClientGetPullRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.EscapedPath() {
+		case "/api/v4/projects/group%2Fapp/merge_requests/7":
+			w.Write([]byte(mockMergeRequestJSON))
+		case "/api/v4/projects/group%2Fapp/merge_requests/7/discussions":
+			w.Write([]byte(mockDiscussionsJSON))
+		default:
+			t.Errorf("unexpected path %s", r.URL.EscapedPath())
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	pr, err := newTestClient(t, server).GetPullRequest(context.Background(), "group", "app", 7)
+	if err != nil {
+		t.Fatalf("GetPullRequest() error = %v", err)
+	}
+
+	if pr.Number != 7 || pr.State != "closed" || !pr.Merged || pr.Status() != "merged" {
+		t.Errorf("pr = #%d state %s merged %v", pr.Number, pr.State, pr.Merged)
+	}
+	if pr.MergedBy == nil || pr.MergedBy.Login != "maintainer" || pr.MergeCommitSHA != "abc123" {
+		t.Errorf("MergedBy = %+v, MergeCommitSHA = %q", pr.MergedBy, pr.MergeCommitSHA)
+	}
+	if pr.Head.Ref != "retry" || pr.Head.SHA != "def456" || pr.Base.Ref != "main" || pr.Base.SHA != "789abc" {
+		t.Errorf("Head = %+v, Base = %+v", pr.Head, pr.Base)
+	}
+	if len(pr.RequestedReviewers) != 1 || pr.RequestedReviewers[0].Login != "bob" {
+		t.Errorf("RequestedReviewers = %+v", pr.RequestedReviewers)
+	}
+
+	if len(pr.Comments) != 2 {
+		t.Fatalf("got %d comments, want 2", len(pr.Comments))
+	}
+	general, review := pr.Comments[0], pr.Comments[1]
+	if general.Body != "Looks good overall" || general.IsReviewComment() {
+		t.Errorf("first comment = %+v", general)
+	}
+	if review.Path != "retry.go" || review.Line != 12 || len(review.Replies) != 1 {
+		t.Fatalf("review comment = %+v", review)
+	}
+	if reply := review.Replies[0]; reply.Body != "Done." || reply.InReplyToID != 11 {
+		t.Errorf("reply = %+v", reply)
+	}
+}
@@ -0,0 +1,121 @@
+package gitlab
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/bigwhite/issue2md/internal/parser"
+)
+
+// DefaultHost 公共GitLab的主机名
+const DefaultHost = "gitlab.com"
+
+// URLParser GitLab URL解析器，实现parser.URLParser接口
+// 支持的URL:
+//   - Issue: https://gitlab.com/group/subgroup/project/-/issues/12
+//   - Merge Request: https://gitlab.com/group/project/-/merge_requests/7，类型为"pull"
+//   - Epic: https://gitlab.com/groups/group/subgroup/-/epics/3，Owner为群组路径，Repo为空
+//
+// 项目可以位于多级群组中，Owner为项目所在的完整命名空间，如 group/subgroup
+type URLParser struct {
+	hosts map[string]bool
+}
+
+// NewURLParser 创建GitLab URL解析器
+// 除gitlab.com外，还接受hosts中的自托管GitLab主机
+func NewURLParser(hosts ...string) *URLParser {
+	accepted := map[string]bool{DefaultHost: true}
+	for _, host := range hosts {
+		accepted[strings.ToLower(host)] = true
+	}
+	return &URLParser{hosts: accepted}
+}
+
+// Parse 解析GitLab Issue、Merge Request或Epic的URL
+func (p *URLParser) Parse(rawURL string) (*parser.ResourceURL, error) {
+	u, err := p.parseURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	// 资源路径以 /-/ 与项目或群组路径分隔，旧式URL没有 /-/
+	path := strings.Trim(u.Path, "/")
+	namespace, resource, ok := strings.Cut(path, "/-/")
+	if !ok {
+		i := strings.LastIndex(path, "/")
+		if i < 0 {
+			return nil, fmt.Errorf("invalid URL: not a GitLab issue, merge request or epic URL")
+		}
+		j := strings.LastIndex(path[:i], "/")
+		if j < 0 {
+			return nil, fmt.Errorf("invalid URL: not a GitLab issue, merge request or epic URL")
+		}
+		namespace, resource = path[:j], path[j+1:]
+	}
+
+	kind, numberStr, ok := strings.Cut(resource, "/")
+	if !ok {
+		return nil, fmt.Errorf("invalid URL: missing number component")
+	}
+	number, err := strconv.Atoi(numberStr)
+	if err != nil || number <= 0 {
+		return nil, fmt.Errorf("invalid URL: invalid number format '%s'", numberStr)
+	}
+
+	res := &parser.ResourceURL{Host: strings.ToLower(u.Host), Number: number, URL: rawURL}
+	switch kind {
+	case "issues":
+		res.Type = "issue"
+	case "merge_requests":
+		res.Type = "pull"
+	case "epics":
+		group := strings.TrimPrefix(namespace, "groups/")
+		if group == namespace || group == "" {
+			return nil, fmt.Errorf("invalid URL: epic URL must start with /groups/")
+		}
+		res.Type, res.Owner = "epic", group
+		return res, nil
+	default:
+		return nil, fmt.Errorf("invalid URL: unsupported URL type: %s", kind)
+	}
+
+	i := strings.LastIndex(namespace, "/")
+	if i <= 0 || i == len(namespace)-1 {
+		return nil, fmt.Errorf("invalid URL: missing group or project")
+	}
+	res.Owner, res.Repo = namespace[:i], namespace[i+1:]
+	return res, nil
+}
+
+// ParseRepository 实现parser.URLParser接口，GitLab项目的批量导出尚未支持
+func (p *URLParser) ParseRepository(rawURL string) (*parser.ResourceURL, error) {
+	if _, err := p.parseURL(rawURL); err != nil {
+		return nil, err
+	}
+	return nil, fmt.Errorf("invalid URL: exporting a whole GitLab project is not supported")
+}
+
+// Validate 验证URL格式
+func (p *URLParser) Validate(rawURL string) error {
+	_, err := p.Parse(rawURL)
+	return err
+}
+
+// SupportedTypes 返回支持的资源类型
+func (p *URLParser) SupportedTypes() []string {
+	return []string{"issue", "pull", "epic"}
+}
+
+// parseURL 解析URL并检查主机
+func (p *URLParser) parseURL(rawURL string) (*url.URL, error) {
+	if rawURL == "" {
+		return nil, fmt.Errorf("empty URL")
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme != "https" || !p.hosts[strings.ToLower(u.Host)] {
+		return nil, fmt.Errorf("invalid URL: %w", fmt.Errorf("not a GitLab URL"))
+	}
+	return u, nil
+}
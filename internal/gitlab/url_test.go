@@ -0,0 +1,73 @@
+package gitlab
+
+import (
+	"testing"
+
+	"github.com/bigwhite/issue2md/internal/parser"
+)
+
+func TestURLParserParse(t *testing.T) {
+	p := NewURLParser("gitlab.example.com")
+
+	tests := []struct {
+		name    string
+		url     string
+		want    parser.ResourceURL
+		wantErr bool
+	}{
+		{
+			name: "issue",
+			url:  "https://gitlab.com/group/app/-/issues/12",
+			want: parser.ResourceURL{Host: "gitlab.com", Owner: "group", Repo: "app", Number: 12, Type: "issue"},
+		},
+		{
+			name: "issue in subgroup",
+			url:  "https://gitlab.com/group/sub/app/-/issues/12",
+			want: parser.ResourceURL{Host: "gitlab.com", Owner: "group/sub", Repo: "app", Number: 12, Type: "issue"},
+		},
+		{
+			name: "merge request on self-managed host",
+			url:  "https://gitlab.example.com/group/app/-/merge_requests/7",
+			want: parser.ResourceURL{Host: "gitlab.example.com", Owner: "group", Repo: "app", Number: 7, Type: "pull"},
+		},
+		{
+			name: "legacy URL without separator",
+			url:  "https://gitlab.com/group/app/issues/5",
+			want: parser.ResourceURL{Host: "gitlab.com", Owner: "group", Repo: "app", Number: 5, Type: "issue"},
+		},
+		{
+			name: "epic",
+			url:  "https://gitlab.com/groups/group/sub/-/epics/3",
+			want: parser.ResourceURL{Host: "gitlab.com", Owner: "group/sub", Number: 3, Type: "epic"},
+		},
+		{name: "unknown host", url: "https://github.com/owner/repo/issues/1", wantErr: true},
+		{name: "http scheme", url: "http://gitlab.com/group/app/-/issues/1", wantErr: true},
+		{name: "epic outside groups", url: "https://gitlab.com/group/-/epics/3", wantErr: true},
+		{name: "missing project", url: "https://gitlab.com/app/-/issues/1", wantErr: true},
+		{name: "unsupported type", url: "https://gitlab.com/group/app/-/wikis/1", wantErr: true},
+		{name: "invalid number", url: "https://gitlab.com/group/app/-/issues/abc", wantErr: true},
+		{name: "empty", url: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := p.Parse(tt.url)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			tt.want.URL = tt.url
+			if *got != tt.want {
+				t.Errorf("Parse() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestURLParserParseRepository(t *testing.T) {
+	if _, err := NewURLParser().ParseRepository("https://gitlab.com/group/app"); err == nil {
+		t.Error("ParseRepository() error = nil, want error")
+	}
+}
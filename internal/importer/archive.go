@@ -0,0 +1,437 @@
+package importer
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bigwhite/issue2md/internal/model"
+)
+
+// archiveReaction 迁移归档中的一个表情回应
+type archiveReaction struct {
+	Content string `json:"content"` // +1、-1、laugh、hooray、confused、heart、rocket、eyes
+}
+
+// archiveIssue 迁移归档issues_*.json中的Issue，用户、标签和里程碑都以URL表示
+type archiveIssue struct {
+	URL       string            `json:"url"`
+	User      string            `json:"user"`
+	Title     string            `json:"title"`
+	Body      string            `json:"body"`
+	Assignee  string            `json:"assignee"`
+	Assignees []string          `json:"assignees"`
+	Milestone string            `json:"milestone"`
+	Labels    []string          `json:"labels"`
+	Reactions []archiveReaction `json:"reactions"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+	ClosedAt  *time.Time        `json:"closed_at"`
+}
+
+// archivePullRequest 迁移归档pull_requests_*.json中的Pull Request，与Issue相同的字段沿用archiveIssue
+type archivePullRequest struct {
+	archiveIssue
+	Head           archiveBranch `json:"head"`
+	Base           archiveBranch `json:"base"`
+	WorkInProgress bool          `json:"work_in_progress"`
+	MergedAt       *time.Time    `json:"merged_at"`
+}
+
+// archiveBranch Pull Request的源分支或目标分支，所有者和仓库都以URL表示
+type archiveBranch struct {
+	Ref  string `json:"ref"`
+	SHA  string `json:"sha"`
+	User string `json:"user"`
+	Repo string `json:"repo"`
+}
+
+// archiveComment 迁移归档issue_comments_*.json中的评论，Pull Request的评论带pull_request而不是issue
+type archiveComment struct {
+	URL         string            `json:"url"`
+	Issue       string            `json:"issue"`
+	PullRequest string            `json:"pull_request"`
+	User        string            `json:"user"`
+	Body        string            `json:"body"`
+	Reactions   []archiveReaction `json:"reactions"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+}
+
+// archiveReviewComment 迁移归档pull_request_review_comments_*.json中的代码评审评论
+type archiveReviewComment struct {
+	archiveComment
+	PullRequestReview string `json:"pull_request_review"`
+	Path              string `json:"path"`
+	DiffHunk          string `json:"diff_hunk"`
+}
+
+// archiveMilestone 迁移归档milestones_*.json中的里程碑
+type archiveMilestone struct {
+	URL         string     `json:"url"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	State       string     `json:"state"`
+	DueOn       *time.Time `json:"due_on"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	ClosedAt    *time.Time `json:"closed_at"`
+}
+
+// archiveLabel 迁移归档labels_*.json中的标签
+type archiveLabel struct {
+	URL         string `json:"url"`
+	Name        string `json:"name"`
+	Color       string `json:"color"`
+	Description string `json:"description"`
+}
+
+// archiveFilePattern 匹配归档中需要读取的数据文件，如 issues_000001.json
+var archiveFilePattern = regexp.MustCompile(`^(issues|issue_comments|pull_requests|pull_request_review_comments|milestones|labels)_\d+\.json$`)
+
+// archive 从迁移归档中收集的数据，评论按所属Issue或Pull Request的URL分组
+type archive struct {
+	issues         []*archiveIssue
+	pulls          []*archivePullRequest
+	comments       map[string][]*archiveComment
+	reviewComments map[string][]*archiveReviewComment
+	milestones     map[string]*archiveMilestone
+	labels         map[string]*archiveLabel
+}
+
+// ReadGitHubArchive 读取GitHub组织迁移归档（tar或tar.gz），按仓库和编号顺序为每个Issue和Pull Request调用一次emit
+// 评论、标签和里程碑分布在不同文件中，需要读完整个归档才能组装Issue；
+// Pull Request包含普通评论和代码评审评论，不包含评审意见和代码diff；附件文件不会导入，正文中的附件链接保持原样
+func ReadGitHubArchive(r io.Reader, emit func(*Record) error) error {
+	br := bufio.NewReader(r)
+	if head, _ := br.Peek(len(gzipMagic)); bytes.Equal(head, gzipMagic) {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return fmt.Errorf("failed to read migration archive: %w", err)
+		}
+		defer gz.Close()
+		r = gz
+	} else {
+		r = br
+	}
+
+	a := &archive{
+		comments:       make(map[string][]*archiveComment),
+		reviewComments: make(map[string][]*archiveReviewComment),
+		milestones:     make(map[string]*archiveMilestone),
+		labels:         make(map[string]*archiveLabel),
+	}
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read migration archive: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		name := path.Base(header.Name)
+		match := archiveFilePattern.FindStringSubmatch(name)
+		if match == nil {
+			continue
+		}
+		if err := a.read(match[1], tr); err != nil {
+			return fmt.Errorf("failed to read %s from migration archive: %w", header.Name, err)
+		}
+	}
+
+	records := make([]*Record, 0, len(a.issues)+len(a.pulls))
+	for _, issue := range a.issues {
+		record, err := a.record(issue)
+		if err != nil {
+			return err
+		}
+		records = append(records, record)
+	}
+	for _, pull := range a.pulls {
+		record, err := a.pullRecord(pull)
+		if err != nil {
+			return err
+		}
+		records = append(records, record)
+	}
+	sort.SliceStable(records, func(i, j int) bool {
+		ri, rj := records[i].Issue, records[j].Issue
+		if ri.Repository != rj.Repository {
+			return ri.Repository < rj.Repository
+		}
+		return ri.Number < rj.Number
+	})
+
+	for _, record := range records {
+		if err := emit(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// read 逐个解码数据文件中的JSON数组元素并按类型收集
+func (a *archive) read(kind string, r io.Reader) error {
+	decoder := json.NewDecoder(r)
+	if _, err := decoder.Token(); err != nil {
+		return err
+	}
+	for decoder.More() {
+		switch kind {
+		case "issues":
+			var issue archiveIssue
+			if err := decoder.Decode(&issue); err != nil {
+				return err
+			}
+			a.issues = append(a.issues, &issue)
+		case "issue_comments":
+			var comment archiveComment
+			if err := decoder.Decode(&comment); err != nil {
+				return err
+			}
+			target := comment.Issue
+			if target == "" {
+				target = comment.PullRequest
+			}
+			if target != "" {
+				a.comments[target] = append(a.comments[target], &comment)
+			}
+		case "pull_requests":
+			var pull archivePullRequest
+			if err := decoder.Decode(&pull); err != nil {
+				return err
+			}
+			a.pulls = append(a.pulls, &pull)
+		case "pull_request_review_comments":
+			var comment archiveReviewComment
+			if err := decoder.Decode(&comment); err != nil {
+				return err
+			}
+			if comment.PullRequest != "" {
+				a.reviewComments[comment.PullRequest] = append(a.reviewComments[comment.PullRequest], &comment)
+			}
+		case "milestones":
+			var milestone archiveMilestone
+			if err := decoder.Decode(&milestone); err != nil {
+				return err
+			}
+			a.milestones[milestone.URL] = &milestone
+		case "labels":
+			var label archiveLabel
+			if err := decoder.Decode(&label); err != nil {
+				return err
+			}
+			a.labels[label.URL] = &label
+		}
+	}
+	_, err := decoder.Token()
+	return err
+}
+
+// record 把归档中的Issue及其评论转换为Issue和评论
+func (a *archive) record(issue *archiveIssue) (*Record, error) {
+	result, err := a.issue(issue, "issues")
+	if err != nil {
+		return nil, err
+	}
+	return &Record{Issue: result, Comments: a.issueComments(issue.URL)}, nil
+}
+
+// pullRecord 把归档中的Pull Request及其普通评论和代码评审评论转换为Pull Request
+func (a *archive) pullRecord(pull *archivePullRequest) (*Record, error) {
+	issue, err := a.issue(&pull.archiveIssue, "pull")
+	if err != nil {
+		return nil, err
+	}
+
+	result := &model.PullRequest{
+		Issue:    *issue,
+		Draft:    pull.WorkInProgress,
+		Merged:   pull.MergedAt != nil,
+		MergedAt: pull.MergedAt,
+		Head:     convertArchiveBranch(pull.Head),
+		Base:     convertArchiveBranch(pull.Base),
+		Comments: a.issueComments(pull.URL),
+	}
+	for _, c := range a.reviewComments[pull.URL] {
+		comment := convertArchiveComment(&c.archiveComment, "r")
+		comment.Path = c.Path
+		comment.DiffHunk = c.DiffHunk
+		if u, err := url.Parse(c.PullRequestReview); err == nil {
+			comment.ReviewID, _ = strconv.ParseInt(strings.TrimPrefix(u.Fragment, "pullrequestreview-"), 10, 64)
+		}
+		result.Comments = append(result.Comments, comment)
+	}
+	sortComments(result.Comments)
+	return &Record{Issue: &result.Issue, PullRequest: result}, nil
+}
+
+// issue 把归档中的Issue或Pull Request的公共字段转换为Issue，segment为URL中编号前的路径段（issues或pull）
+func (a *archive) issue(issue *archiveIssue, segment string) (*model.Issue, error) {
+	repo, number, err := splitIssueURL(issue.URL, segment)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &model.Issue{
+		Number:     number,
+		Title:      issue.Title,
+		Body:       issue.Body,
+		State:      "open",
+		User:       archiveUser(issue.User),
+		CreatedAt:  issue.CreatedAt,
+		UpdatedAt:  issue.UpdatedAt,
+		ClosedAt:   issue.ClosedAt,
+		HTMLURL:    issue.URL,
+		Reactions:  countReactions(issue.Reactions),
+		Repository: repo,
+	}
+	if issue.ClosedAt != nil {
+		result.State = "closed"
+	}
+	if result.UpdatedAt.IsZero() {
+		result.UpdatedAt = issue.CreatedAt
+	}
+
+	assignees := issue.Assignees
+	if len(assignees) == 0 && issue.Assignee != "" {
+		assignees = []string{issue.Assignee}
+	}
+	for _, assignee := range assignees {
+		result.Assignees = append(result.Assignees, archiveUser(assignee))
+	}
+	for _, labelURL := range issue.Labels {
+		result.Labels = append(result.Labels, a.label(labelURL))
+	}
+	if issue.Milestone != "" {
+		result.Milestone = a.milestone(issue.Milestone)
+	}
+	return result, nil
+}
+
+// issueComments 返回URL对应的Issue或Pull Request的普通评论，按创建时间排序
+func (a *archive) issueComments(issueURL string) []*model.Comment {
+	archived := a.comments[issueURL]
+	comments := make([]*model.Comment, 0, len(archived))
+	for _, c := range archived {
+		comments = append(comments, convertArchiveComment(c, "issuecomment-"))
+	}
+	sortComments(comments)
+	return comments
+}
+
+// label 返回标签URL对应的标签，归档中没有该标签时使用URL的最后一段作为名称
+func (a *archive) label(labelURL string) model.Label {
+	if label, ok := a.labels[labelURL]; ok {
+		return model.Label{Name: label.Name, Color: label.Color, Description: label.Description}
+	}
+	name, err := url.PathUnescape(path.Base(labelURL))
+	if err != nil {
+		name = path.Base(labelURL)
+	}
+	return model.Label{Name: name}
+}
+
+// milestone 返回里程碑URL对应的里程碑，归档中没有该里程碑时返回nil
+func (a *archive) milestone(milestoneURL string) *model.Milestone {
+	milestone, ok := a.milestones[milestoneURL]
+	if !ok {
+		return nil
+	}
+	number, _ := strconv.Atoi(path.Base(milestoneURL))
+	return &model.Milestone{
+		Title:       milestone.Title,
+		Number:      number,
+		State:       milestone.State,
+		Description: milestone.Description,
+		CreatedAt:   milestone.CreatedAt,
+		UpdatedAt:   milestone.UpdatedAt,
+		DueDate:     milestone.DueOn,
+		ClosedAt:    milestone.ClosedAt,
+	}
+}
+
+// convertArchiveComment 转换评论，评论ID取自URL片段去掉idPrefix（如 issuecomment-、r）后的部分
+func convertArchiveComment(c *archiveComment, idPrefix string) *model.Comment {
+	comment := &model.Comment{
+		Body:      c.Body,
+		User:      archiveUser(c.User),
+		CreatedAt: c.CreatedAt,
+		UpdatedAt: c.UpdatedAt,
+		HTMLURL:   c.URL,
+		Reactions: countReactions(c.Reactions),
+	}
+	if u, err := url.Parse(c.URL); err == nil {
+		comment.ID, _ = strconv.ParseInt(strings.TrimPrefix(u.Fragment, idPrefix), 10, 64)
+	}
+	return comment
+}
+
+// sortComments 按创建时间排序评论
+func sortComments(comments []*model.Comment) {
+	sort.SliceStable(comments, func(i, j int) bool {
+		return comments[i].CreatedAt.Before(comments[j].CreatedAt)
+	})
+}
+
+// convertArchiveBranch 转换分支，Label为 所有者:分支名，Repo为仓库URL中的 owner/name
+func convertArchiveBranch(branch archiveBranch) model.Branch {
+	result := model.Branch{Ref: branch.Ref, SHA: branch.SHA}
+	if branch.User != "" {
+		result.Label = path.Base(branch.User) + ":" + branch.Ref
+	}
+	if u, err := url.Parse(branch.Repo); err == nil {
+		result.Repo = strings.Trim(u.Path, "/")
+	}
+	return result
+}
+
+// splitIssueURL 从Issue或Pull Request的URL中解析仓库和编号，segment为编号前的路径段，
+// 如 https://github.com/org/repo/issues/1 和segment issues 返回 org/repo 和 1
+func splitIssueURL(rawURL, segment string) (string, int, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid issue URL %q: %w", rawURL, err)
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) != 4 || parts[2] != segment {
+		return "", 0, fmt.Errorf("invalid issue URL %q", rawURL)
+	}
+	number, err := strconv.Atoi(parts[3])
+	if err != nil || number <= 0 {
+		return "", 0, fmt.Errorf("invalid issue URL %q", rawURL)
+	}
+	return parts[0] + "/" + parts[1], number, nil
+}
+
+// archiveUser 转换以主页URL表示的用户，用户名为URL的最后一段，已删除的用户为空
+func archiveUser(userURL string) model.User {
+	if userURL == "" {
+		return model.User{}
+	}
+	return model.User{Login: path.Base(userURL), HTMLURL: userURL}
+}
+
+// countReactions 按内容统计表情回应
+func countReactions(reactions []archiveReaction) model.Reactions {
+	var counts model.Reactions
+	for _, reaction := range reactions {
+		counts.Add(reaction.Content, 1)
+	}
+	return counts
+}
@@ -0,0 +1,192 @@
+package importer
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"testing"
+
+	"github.com/bigwhite/issue2md/internal/model"
+)
+
+// archiveFiles 一个包含两个仓库的迁移归档
+var archiveFiles = map[string]string{
+	"schema.json": `{"version": "1.0.1"}`,
+	"issues_000001.json": `[
+		{"type": "issue", "url": "https://github.com/org/web/issues/2", "user": "https://github.com/alice",
+		 "title": "Broken link", "body": "See footer", "labels": ["https://github.com/org/web/labels/good%20first%20issue"],
+		 "reactions": [], "closed_at": null, "created_at": "2023-05-01T09:00:00.000+00:00"},
+		{"type": "issue", "url": "https://github.com/org/api/issues/7", "user": "https://github.com/bob",
+		 "title": "Timeout", "body": "Requests hang", "assignee": "https://github.com/carol",
+		 "milestone": "https://github.com/org/api/milestones/1", "labels": ["https://github.com/org/api/labels/bug"],
+		 "reactions": [{"content": "+1"}, {"content": "+1"}, {"content": "heart"}],
+		 "closed_at": "2023-05-03T12:00:00.000+00:00", "created_at": "2023-05-02T09:00:00.000+00:00",
+		 "updated_at": "2023-05-03T12:00:00.000+00:00"}
+	]`,
+	"issue_comments_000001.json": `[
+		{"type": "issue_comment", "url": "https://github.com/org/api/issues/7#issuecomment-200",
+		 "issue": "https://github.com/org/api/issues/7", "user": "https://github.com/carol", "body": "Fixed",
+		 "reactions": [{"content": "rocket"}], "created_at": "2023-05-03T11:00:00.000+00:00"},
+		{"type": "issue_comment", "url": "https://github.com/org/api/issues/7#issuecomment-100",
+		 "issue": "https://github.com/org/api/issues/7", "user": "https://github.com/bob", "body": "Still happening",
+		 "reactions": [], "created_at": "2023-05-02T10:00:00.000+00:00"},
+		{"type": "issue_comment", "url": "https://github.com/org/api/pull/8#issuecomment-300",
+		 "pull_request": "https://github.com/org/api/pull/8", "user": "https://github.com/carol", "body": "LGTM",
+		 "reactions": [], "created_at": "2023-05-03T10:00:00.000+00:00"}
+	]`,
+	"milestones_000001.json": `[
+		{"type": "milestone", "url": "https://github.com/org/api/milestones/1", "title": "v2.0", "state": "open",
+		 "created_at": "2023-04-01T09:00:00.000+00:00"}
+	]`,
+	"labels_000001.json": `[
+		{"type": "label", "url": "https://github.com/org/api/labels/bug", "name": "bug", "color": "fc2929"}
+	]`,
+	"pull_requests_000001.json": `[
+		{"type": "pull_request", "url": "https://github.com/org/api/pull/8", "user": "https://github.com/carol",
+		 "title": "Fix timeout", "body": "Fixes #7", "labels": ["https://github.com/org/api/labels/bug"],
+		 "head": {"ref": "fix-timeout", "sha": "abc123", "user": "https://github.com/carol", "repo": "https://github.com/carol/api"},
+		 "base": {"ref": "main", "sha": "def456", "user": "https://github.com/org", "repo": "https://github.com/org/api"},
+		 "work_in_progress": false, "merged_at": "2023-05-03T12:00:00.000+00:00", "closed_at": "2023-05-03T12:00:00.000+00:00",
+		 "created_at": "2023-05-03T09:00:00.000+00:00"}
+	]`,
+	"pull_request_review_comments_000001.json": `[
+		{"type": "pull_request_review_comment", "url": "https://github.com/org/api/pull/8/files#r400",
+		 "pull_request": "https://github.com/org/api/pull/8",
+		 "pull_request_review": "https://github.com/org/api/pull/8/files#pullrequestreview-40",
+		 "user": "https://github.com/bob", "body": "Nit", "path": "client.go", "diff_hunk": "@@ -1,3 +1,4 @@",
+		 "reactions": [{"content": "eyes"}], "created_at": "2023-05-03T09:30:00.000+00:00"}
+	]`,
+}
+
+// buildArchive 在内存中构建迁移归档，compress为true时使用gzip压缩
+func buildArchive(t *testing.T, files map[string]string, compress bool) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	var gz *gzip.Writer
+	tw := tar.NewWriter(&buf)
+	if compress {
+		gz = gzip.NewWriter(&buf)
+		tw = tar.NewWriter(gz)
+	}
+	for name, content := range files {
+		header := &tar.Header{Name: "migration/" + name, Mode: 0o644, Size: int64(len(content)), Typeflag: tar.TypeReg}
+		if err := tw.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if gz != nil {
+		if err := gz.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return buf.Bytes()
+}
+
+func TestReadGitHubArchive(t *testing.T) {
+	for _, compress := range []bool{true, false} {
+		var records []*Record
+		err := ReadGitHubArchive(bytes.NewReader(buildArchive(t, archiveFiles, compress)), func(record *Record) error {
+			records = append(records, record)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("ReadGitHubArchive(compress=%v) error = %v", compress, err)
+		}
+		if len(records) != 3 {
+			t.Fatalf("got %d records, want 3", len(records))
+		}
+
+		// 按仓库排序，org/api 在 org/web 之前
+		issue := records[0].Issue
+		if issue.Repository != "org/api" || issue.Number != 7 || issue.Title != "Timeout" || issue.State != "closed" {
+			t.Errorf("issue = %s#%d %q %s", issue.Repository, issue.Number, issue.Title, issue.State)
+		}
+		if issue.User.Login != "bob" || issue.User.HTMLURL != "https://github.com/bob" {
+			t.Errorf("User = %+v", issue.User)
+		}
+		if len(issue.Assignees) != 1 || issue.Assignees[0].Login != "carol" {
+			t.Errorf("Assignees = %+v", issue.Assignees)
+		}
+		if len(issue.Labels) != 1 || issue.Labels[0].Name != "bug" || issue.Labels[0].Color != "fc2929" {
+			t.Errorf("Labels = %+v", issue.Labels)
+		}
+		if issue.Milestone == nil || issue.Milestone.Title != "v2.0" || issue.Milestone.Number != 1 {
+			t.Errorf("Milestone = %+v", issue.Milestone)
+		}
+		if issue.Reactions.TotalCount != 3 || issue.Reactions.ThumbsUp != 2 || issue.Reactions.Heart != 1 {
+			t.Errorf("Reactions = %+v", issue.Reactions)
+		}
+
+		comments := records[0].Comments
+		if len(comments) != 2 {
+			t.Fatalf("got %d comments, want 2", len(comments))
+		}
+		if comments[0].ID != 100 || comments[0].Body != "Still happening" || comments[1].ID != 200 {
+			t.Errorf("comments not sorted by time: %+v, %+v", comments[0], comments[1])
+		}
+		if comments[1].Reactions.Rocket != 1 {
+			t.Errorf("comment reactions = %+v", comments[1].Reactions)
+		}
+
+		if records[0].PullRequest != nil {
+			t.Errorf("issue record has PullRequest = %+v", records[0].PullRequest)
+		}
+
+		// Pull Request与Issue共用编号，排在#7之后，普通评论和代码评审评论按时间交错
+		pr := records[1].PullRequest
+		if pr == nil {
+			t.Fatal("records[1].PullRequest = nil, want org/api#8")
+		}
+		if records[1].Issue != &pr.Issue || pr.Repository != "org/api" || pr.Number != 8 || pr.Title != "Fix timeout" {
+			t.Errorf("pull request = %s#%d %q", pr.Repository, pr.Number, pr.Title)
+		}
+		if !pr.Merged || pr.Status() != "merged" || pr.Draft || len(pr.Labels) != 1 || pr.Labels[0].Name != "bug" {
+			t.Errorf("pull request = merged %v draft %v labels %+v", pr.Merged, pr.Draft, pr.Labels)
+		}
+		wantHead := model.Branch{Label: "carol:fix-timeout", Ref: "fix-timeout", SHA: "abc123", Repo: "carol/api"}
+		if pr.Head != wantHead || pr.Base.Label != "org:main" || pr.Base.Repo != "org/api" {
+			t.Errorf("Head = %+v, Base = %+v", pr.Head, pr.Base)
+		}
+		if len(pr.Comments) != 2 {
+			t.Fatalf("got %d pull request comments, want 2", len(pr.Comments))
+		}
+		review, comment := pr.Comments[0], pr.Comments[1]
+		if !review.IsReviewComment() || review.ID != 400 || review.ReviewID != 40 || review.Path != "client.go" ||
+			review.DiffHunk != "@@ -1,3 +1,4 @@" || review.User.Login != "bob" || review.Reactions.Eyes != 1 {
+			t.Errorf("review comment = %+v", review)
+		}
+		if comment.IsReviewComment() || comment.ID != 300 || comment.Body != "LGTM" {
+			t.Errorf("comment = %+v", comment)
+		}
+
+		web := records[2].Issue
+		if web.Repository != "org/web" || web.State != "open" || !web.UpdatedAt.Equal(web.CreatedAt) {
+			t.Errorf("issue = %+v", web)
+		}
+		if len(web.Labels) != 1 || web.Labels[0].Name != "good first issue" {
+			t.Errorf("Labels = %+v", web.Labels)
+		}
+		if len(records[2].Comments) != 0 {
+			t.Errorf("got %d comments, want none", len(records[2].Comments))
+		}
+	}
+}
+
+func TestReadGitHubArchiveInvalidIssueURL(t *testing.T) {
+	for name, content := range map[string]string{
+		"issues_000001.json":        `[{"url": "https://github.com/org/api/pull/8", "title": "Not an issue"}]`,
+		"pull_requests_000001.json": `[{"url": "https://github.com/org/api/issues/7", "title": "Not a pull request"}]`,
+	} {
+		files := map[string]string{name: content}
+		err := ReadGitHubArchive(bytes.NewReader(buildArchive(t, files, true)), func(*Record) error { return nil })
+		if err == nil {
+			t.Errorf("ReadGitHubArchive(%s) error = nil, want error", name)
+		}
+	}
+}
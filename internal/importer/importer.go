@@ -0,0 +1,70 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/bigwhite/issue2md/internal/model"
+)
+
+// Record 从导出文件读取的一个Issue及其评论
+// 迁移归档中的Pull Request设置PullRequest，Issue指向其中的Issue部分，评论在PullRequest.Comments中
+type Record struct {
+	Issue       *model.Issue
+	Comments    []*model.Comment
+	PullRequest *model.PullRequest
+}
+
+// 支持的导出文件格式
+const (
+	FormatJira          = "jira"           // Jira的XML导出（RSS格式）
+	FormatGitHubArchive = "github-archive" // GitHub组织迁移归档（tar或tar.gz）
+)
+
+// gzipMagic gzip文件的前两个字节
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// ReadFile 读取导出文件，按内容识别格式，每读到一个Issue或Pull Request调用一次emit
+// emit返回错误时停止读取并返回该错误
+func ReadFile(path string, emit func(*Record) error) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open import file: %w", err)
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	format, err := DetectFormat(r)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	switch format {
+	case FormatJira:
+		return ReadJira(r, emit)
+	default:
+		return ReadGitHubArchive(r, emit)
+	}
+}
+
+// DetectFormat 根据开头的内容识别导出文件格式，不消耗r中的数据
+// 以XML标记开头的是Jira导出，gzip压缩或带ustar标记的tar文件是GitHub迁移归档
+func DetectFormat(r *bufio.Reader) (string, error) {
+	head, err := r.Peek(512)
+	if err != nil && err != io.EOF {
+		return "", fmt.Errorf("failed to read import file: %w", err)
+	}
+
+	switch {
+	case bytes.HasPrefix(head, gzipMagic):
+		return FormatGitHubArchive, nil
+	case len(head) >= 262 && string(head[257:262]) == "ustar":
+		return FormatGitHubArchive, nil
+	case bytes.HasPrefix(bytes.TrimLeft(bytes.TrimPrefix(head, []byte("\xef\xbb\xbf")), " \t\r\n"), []byte("<")):
+		return FormatJira, nil
+	default:
+		return "", fmt.Errorf("unrecognized import format, expected a Jira XML export or a GitHub migration archive")
+	}
+}
@@ -0,0 +1,86 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestDetectFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   []byte
+		want    string
+		wantErr bool
+	}{
+		{name: "jira", input: []byte(jiraExport), want: FormatJira},
+		{name: "jira with BOM", input: []byte("\xef\xbb\xbf\n<rss></rss>"), want: FormatJira},
+		{name: "gzip archive", input: buildArchive(t, archiveFiles, true), want: FormatGitHubArchive},
+		{name: "tar archive", input: buildArchive(t, archiveFiles, false), want: FormatGitHubArchive},
+		{name: "json", input: []byte(`[{"url": "x"}]`), wantErr: true},
+		{name: "empty", input: nil, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DetectFormat(bufio.NewReader(bytes.NewReader(tt.input)))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DetectFormat() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("DetectFormat() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadFile(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string][]byte{
+		"export.xml":         []byte(jiraExport),
+		"migration.tar.gz":   buildArchive(t, archiveFiles, true),
+		"unknown-format.txt": []byte("hello"),
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		file    string
+		want    []string
+		wantErr string
+	}{
+		{file: "export.xml", want: []string{"PROJ#12", "PROJ#13"}},
+		{file: "migration.tar.gz", want: []string{"org/api#7", "org/api#8", "org/web#2"}},
+		{file: "unknown-format.txt", wantErr: "unrecognized import format"},
+		{file: "missing.xml", wantErr: "failed to open import file"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			var got []string
+			err := ReadFile(filepath.Join(dir, tt.file), func(record *Record) error {
+				got = append(got, record.Issue.Repository+"#"+strconv.Itoa(record.Issue.Number))
+				return nil
+			})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ReadFile() error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ReadFile() error = %v", err)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("ReadFile() issues = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,250 @@
+package importer
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bigwhite/issue2md/internal/model"
+)
+
+// jiraTimeLayout Jira XML导出中的时间格式，如 Mon, 5 Feb 2024 09:00:00 +0000
+const jiraTimeLayout = "Mon, 2 Jan 2006 15:04:05 -0700"
+
+// jiraUser Jira导出中的用户，Server/Data Center带username，Cloud带accountid
+type jiraUser struct {
+	Username    string `xml:"username,attr"`
+	AccountID   string `xml:"accountid,attr"`
+	DisplayName string `xml:",chardata"`
+}
+
+// jiraComment Jira导出中的评论，正文为渲染后的HTML
+type jiraComment struct {
+	ID      int64  `xml:"id,attr"`
+	Author  string `xml:"author,attr"`
+	Created string `xml:"created,attr"`
+	Body    string `xml:",chardata"`
+}
+
+// jiraItem Jira导出中的一个Issue，即RSS中的item元素
+type jiraItem struct {
+	Link           string `xml:"link"`
+	Key            string `xml:"key"`
+	Summary        string `xml:"summary"`
+	Description    string `xml:"description"`
+	Type           string `xml:"type"`
+	Priority       string `xml:"priority"`
+	StatusCategory struct {
+		Key string `xml:"key,attr"` // new、indeterminate 或 done
+	} `xml:"statusCategory"`
+	Assignee    jiraUser      `xml:"assignee"`
+	Reporter    jiraUser      `xml:"reporter"`
+	Labels      []string      `xml:"labels>label"`
+	Components  []string      `xml:"component"`
+	FixVersions []string      `xml:"fixVersion"`
+	Created     string        `xml:"created"`
+	Updated     string        `xml:"updated"`
+	Resolved    string        `xml:"resolved"`
+	Votes       string        `xml:"votes"`
+	Parent      string        `xml:"parent"`
+	Subtasks    []string      `xml:"subtasks>subtask"`
+	Comments    []jiraComment `xml:"comments>comment"`
+}
+
+// ReadJira 流式读取Jira的XML导出，每读到一个Issue调用一次emit
+// 项目键作为仓库，如 PROJ-12 对应仓库 PROJ 的 #12；类型、优先级和组件转换为标签，
+// 首个修复版本作为里程碑，投票数计为👍；正文和评论保留Jira渲染的HTML
+func ReadJira(r io.Reader, emit func(*Record) error) error {
+	decoder := xml.NewDecoder(r)
+	decoder.Entity = xml.HTMLEntity
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read Jira export: %w", err)
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "item" {
+			continue
+		}
+		var item jiraItem
+		if err := decoder.DecodeElement(&item, &start); err != nil {
+			return fmt.Errorf("failed to read Jira export: %w", err)
+		}
+		record, err := item.record()
+		if err != nil {
+			return err
+		}
+		if err := emit(record); err != nil {
+			return err
+		}
+	}
+}
+
+// record 把Jira Issue转换为Issue和评论
+func (item *jiraItem) record() (*Record, error) {
+	project, number, err := splitJiraKey(item.Key)
+	if err != nil {
+		return nil, err
+	}
+	site := jiraSite(item.Link)
+
+	issue := &model.Issue{
+		Number:     number,
+		Title:      strings.TrimSpace(item.Summary),
+		Body:       strings.TrimSpace(item.Description),
+		State:      "open",
+		User:       item.Reporter.user(site),
+		HTMLURL:    strings.TrimSpace(item.Link),
+		Repository: project,
+	}
+	if issue.CreatedAt, err = parseJiraTime(item.Created); err != nil {
+		return nil, fmt.Errorf("invalid created time of %s: %w", item.Key, err)
+	}
+	if issue.UpdatedAt, err = parseJiraTime(item.Updated); err != nil {
+		return nil, fmt.Errorf("invalid updated time of %s: %w", item.Key, err)
+	}
+	if item.Resolved != "" {
+		resolved, err := parseJiraTime(item.Resolved)
+		if err != nil {
+			return nil, fmt.Errorf("invalid resolved time of %s: %w", item.Key, err)
+		}
+		issue.State, issue.ClosedAt = "closed", &resolved
+	} else if item.StatusCategory.Key == "done" {
+		issue.State = "closed"
+	}
+
+	if assignee := item.Assignee.user(site); assignee.Login != "" {
+		issue.Assignees = []model.User{assignee}
+	}
+	issue.Labels = item.labels()
+	if len(item.FixVersions) > 0 {
+		issue.Milestone = &model.Milestone{Title: item.FixVersions[0]}
+	}
+	if votes, _ := strconv.Atoi(strings.TrimSpace(item.Votes)); votes > 0 {
+		issue.Reactions = model.Reactions{TotalCount: votes, ThumbsUp: votes}
+	}
+
+	if parent := strings.TrimSpace(item.Parent); parent != "" {
+		if ref, err := jiraReference(site, parent); err == nil {
+			issue.Parent = ref
+		}
+	}
+	for _, key := range item.Subtasks {
+		if ref, err := jiraReference(site, strings.TrimSpace(key)); err == nil {
+			issue.SubIssues = append(issue.SubIssues, &model.SubIssue{IssueReference: *ref})
+		}
+	}
+
+	comments := make([]*model.Comment, 0, len(item.Comments))
+	for _, c := range item.Comments {
+		created, err := parseJiraTime(c.Created)
+		if err != nil {
+			return nil, fmt.Errorf("invalid time of comment %d on %s: %w", c.ID, item.Key, err)
+		}
+		comment := &model.Comment{
+			ID:        c.ID,
+			Body:      strings.TrimSpace(c.Body),
+			User:      jiraUser{Username: c.Author}.user(site),
+			CreatedAt: created,
+			UpdatedAt: created,
+		}
+		if issue.HTMLURL != "" {
+			comment.HTMLURL = fmt.Sprintf("%s?focusedCommentId=%d#comment-%d", issue.HTMLURL, c.ID, c.ID)
+		}
+		comments = append(comments, comment)
+	}
+	return &Record{Issue: issue, Comments: comments}, nil
+}
+
+// labels 返回Issue的标签，类型、优先级和组件带前缀以便区分，如 type: Bug
+func (item *jiraItem) labels() []model.Label {
+	var labels []model.Label
+	add := func(prefix, name string) {
+		if name = strings.TrimSpace(name); name != "" {
+			labels = append(labels, model.Label{Name: prefix + name})
+		}
+	}
+	add("type: ", item.Type)
+	add("priority: ", item.Priority)
+	for _, component := range item.Components {
+		add("component: ", component)
+	}
+	for _, label := range item.Labels {
+		add("", label)
+	}
+	return labels
+}
+
+// user 转换Jira用户，未分配的经办人（username为-1）返回空用户
+// 用户名优先使用username，其次accountid和显示名称，主页链接指向Jira站点的用户资料页
+func (u jiraUser) user(site string) model.User {
+	login := u.Username
+	if login == "" {
+		login = u.AccountID
+	}
+	if login == "" {
+		login = strings.TrimSpace(u.DisplayName)
+	}
+	if login == "" || login == "-1" {
+		return model.User{}
+	}
+
+	user := model.User{Login: login}
+	if site != "" {
+		user.HTMLURL = site + "/secure/ViewProfile.jspa?name=" + url.QueryEscape(login)
+	}
+	return user
+}
+
+// splitJiraKey 把Issue键拆分为项目键和编号，如 PROJ-12 返回 PROJ 和 12
+func splitJiraKey(key string) (string, int, error) {
+	key = strings.TrimSpace(key)
+	i := strings.LastIndex(key, "-")
+	if i <= 0 {
+		return "", 0, fmt.Errorf("invalid Jira issue key %q", key)
+	}
+	number, err := strconv.Atoi(key[i+1:])
+	if err != nil || number <= 0 {
+		return "", 0, fmt.Errorf("invalid Jira issue key %q", key)
+	}
+	return key[:i], number, nil
+}
+
+// jiraReference 返回对另一个Jira Issue的引用
+func jiraReference(site, key string) (*model.IssueReference, error) {
+	project, number, err := splitJiraKey(key)
+	if err != nil {
+		return nil, err
+	}
+	ref := &model.IssueReference{Type: "issue", Repo: project, Number: number}
+	if site != "" {
+		ref.URL = site + "/browse/" + key
+	}
+	return ref, nil
+}
+
+// jiraSite 从Issue链接推导Jira站点地址，如 https://jira.example.com/browse/PROJ-12 返回 https://jira.example.com
+func jiraSite(link string) string {
+	site, _, ok := strings.Cut(strings.TrimSpace(link), "/browse/")
+	if !ok {
+		return ""
+	}
+	return site
+}
+
+// parseJiraTime 解析Jira时间，空值返回零值
+func parseJiraTime(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(jiraTimeLayout, value)
+}
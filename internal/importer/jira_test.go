@@ -0,0 +1,169 @@
+package importer
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+const jiraExport = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="0.92">
+<channel>
+	<title>Jira export</title>
+	<link>https://jira.example.com</link>
+	<item>
+		<title>[PROJ-12] Crash on startup</title>
+		<link>https://jira.example.com/browse/PROJ-12</link>
+		<project id="10000" key="PROJ">Project</project>
+		<description>&lt;p&gt;Steps to reproduce&lt;/p&gt;</description>
+		<key id="10012">PROJ-12</key>
+		<summary>Crash on startup</summary>
+		<type id="1">Bug</type>
+		<priority id="3">Major</priority>
+		<status id="6">Closed</status>
+		<statusCategory id="3" key="done" colorName="green"/>
+		<resolution id="1">Fixed</resolution>
+		<assignee username="bob">Bob Smith</assignee>
+		<reporter username="alice">Alice Jones</reporter>
+		<labels>
+			<label>crash</label>
+		</labels>
+		<created>Mon, 5 Feb 2024 09:00:00 +0000</created>
+		<updated>Tue, 6 Feb 2024 10:00:00 +0000</updated>
+		<resolved>Tue, 6 Feb 2024 10:00:00 +0000</resolved>
+		<fixVersion>1.0</fixVersion>
+		<component>Core</component>
+		<votes>2</votes>
+		<parent id="10001">PROJ-1</parent>
+		<comments>
+			<comment id="10100" author="bob" created="Mon, 5 Feb 2024 10:30:00 +0000">&lt;p&gt;Reproduced&lt;/p&gt;</comment>
+		</comments>
+	</item>
+	<item>
+		<link>https://jira.example.com/browse/PROJ-13</link>
+		<key id="10013">PROJ-13</key>
+		<summary>Add dark mode</summary>
+		<statusCategory id="2" key="new"/>
+		<assignee username="-1">Unassigned</assignee>
+		<reporter accountid="5b10ac8d82e05b22cc7d4ef5">Carol</reporter>
+		<created>Wed, 7 Feb 2024 09:00:00 +0000</created>
+		<updated>Wed, 7 Feb 2024 09:00:00 +0000</updated>
+	</item>
+</channel>
+</rss>`
+
+func TestReadJira(t *testing.T) {
+	var records []*Record
+	err := ReadJira(strings.NewReader(jiraExport), func(record *Record) error {
+		records = append(records, record)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ReadJira() error = %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want 2", len(records))
+	}
+
+	issue := records[0].Issue
+	if issue.Repository != "PROJ" || issue.Number != 12 || issue.Title != "Crash on startup" {
+		t.Errorf("issue = %s#%d %q", issue.Repository, issue.Number, issue.Title)
+	}
+	if issue.Body != "<p>Steps to reproduce</p>" {
+		t.Errorf("Body = %q", issue.Body)
+	}
+	if issue.State != "closed" || issue.ClosedAt == nil || !issue.ClosedAt.Equal(time.Date(2024, 2, 6, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("State = %q, ClosedAt = %v", issue.State, issue.ClosedAt)
+	}
+	if !issue.CreatedAt.Equal(time.Date(2024, 2, 5, 9, 0, 0, 0, time.UTC)) {
+		t.Errorf("CreatedAt = %v", issue.CreatedAt)
+	}
+	if issue.User.Login != "alice" || issue.User.HTMLURL != "https://jira.example.com/secure/ViewProfile.jspa?name=alice" {
+		t.Errorf("User = %+v", issue.User)
+	}
+	if len(issue.Assignees) != 1 || issue.Assignees[0].Login != "bob" {
+		t.Errorf("Assignees = %+v", issue.Assignees)
+	}
+	var labels []string
+	for _, label := range issue.Labels {
+		labels = append(labels, label.Name)
+	}
+	if got, want := strings.Join(labels, ","), "type: Bug,priority: Major,component: Core,crash"; got != want {
+		t.Errorf("Labels = %s, want %s", got, want)
+	}
+	if issue.Milestone == nil || issue.Milestone.Title != "1.0" {
+		t.Errorf("Milestone = %+v", issue.Milestone)
+	}
+	if issue.Reactions.ThumbsUp != 2 {
+		t.Errorf("Reactions = %+v", issue.Reactions)
+	}
+	if issue.Parent == nil || issue.Parent.Repo != "PROJ" || issue.Parent.Number != 1 || issue.Parent.URL != "https://jira.example.com/browse/PROJ-1" {
+		t.Errorf("Parent = %+v", issue.Parent)
+	}
+
+	comments := records[0].Comments
+	if len(comments) != 1 {
+		t.Fatalf("got %d comments, want 1", len(comments))
+	}
+	if c := comments[0]; c.ID != 10100 || c.User.Login != "bob" || c.Body != "<p>Reproduced</p>" ||
+		c.HTMLURL != "https://jira.example.com/browse/PROJ-12?focusedCommentId=10100#comment-10100" {
+		t.Errorf("comment = %+v", c)
+	}
+
+	open := records[1].Issue
+	if open.State != "open" || open.ClosedAt != nil {
+		t.Errorf("State = %q, ClosedAt = %v", open.State, open.ClosedAt)
+	}
+	if len(open.Assignees) != 0 {
+		t.Errorf("Assignees = %+v, want none", open.Assignees)
+	}
+	if open.User.Login != "5b10ac8d82e05b22cc7d4ef5" {
+		t.Errorf("User = %+v", open.User)
+	}
+}
+
+func TestReadJiraErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{
+			name:    "invalid key",
+			input:   `<rss><channel><item><key>PROJ</key></item></channel></rss>`,
+			wantErr: `invalid Jira issue key "PROJ"`,
+		},
+		{
+			name:    "invalid time",
+			input:   `<rss><channel><item><key>PROJ-1</key><created>yesterday</created></item></channel></rss>`,
+			wantErr: "invalid created time of PROJ-1",
+		},
+		{
+			name:    "truncated",
+			input:   `<rss><channel><item><key>PROJ-1</key>`,
+			wantErr: "failed to read Jira export",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ReadJira(strings.NewReader(tt.input), func(*Record) error { return nil })
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ReadJira() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestReadJiraStopsOnEmitError(t *testing.T) {
+	stop := errors.New("stop")
+	calls := 0
+	err := ReadJira(strings.NewReader(jiraExport), func(*Record) error {
+		calls++
+		return stop
+	})
+	if !errors.Is(err, stop) || calls != 1 {
+		t.Errorf("ReadJira() error = %v after %d calls, want stop after 1", err, calls)
+	}
+}
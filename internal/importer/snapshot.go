@@ -0,0 +1,302 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bigwhite/issue2md/internal/model"
+	"github.com/bigwhite/issue2md/internal/parser"
+)
+
+// Snapshot 从JSON读取的一个资源，PullRequest、Issue和Document三者择一
+type Snapshot struct {
+	Issue       *model.Issue
+	Comments    []*model.Comment
+	PullRequest *model.PullRequest
+	// Document 不带Issue或Pull Request数据的issue2md JSON输出（如Discussion），内容已经渲染为Markdown，只需转换为目标格式
+	Document *parser.MarkdownDocument
+}
+
+// ghUser gh CLI输出的用户
+type ghUser struct {
+	Login string `json:"login"`
+}
+
+// ghReactionGroup gh CLI输出的按表情分组的reactions统计
+type ghReactionGroup struct {
+	Content string `json:"content"`
+	Users   struct {
+		TotalCount int `json:"totalCount"`
+	} `json:"users"`
+}
+
+// ghLabel gh CLI输出的标签
+type ghLabel struct {
+	Name        string `json:"name"`
+	Color       string `json:"color"`
+	Description string `json:"description"`
+}
+
+// ghMilestone gh CLI输出的里程碑
+type ghMilestone struct {
+	Number      int        `json:"number"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	DueOn       *time.Time `json:"dueOn"`
+}
+
+// ghComment gh CLI输出的评论
+type ghComment struct {
+	Author          *ghUser           `json:"author"`
+	Body            string            `json:"body"`
+	CreatedAt       time.Time         `json:"createdAt"`
+	URL             string            `json:"url"`
+	IsMinimized     bool              `json:"isMinimized"`
+	MinimizedReason string            `json:"minimizedReason"`
+	ReactionGroups  []ghReactionGroup `json:"reactionGroups"`
+}
+
+// ghReview gh CLI输出的PR评审
+type ghReview struct {
+	Author      *ghUser    `json:"author"`
+	Body        string     `json:"body"`
+	State       string     `json:"state"`
+	SubmittedAt *time.Time `json:"submittedAt"`
+	Commit      struct {
+		OID string `json:"oid"`
+	} `json:"commit"`
+}
+
+// ghReviewRequest gh CLI输出的评审请求，用户带login，团队带name和slug
+type ghReviewRequest struct {
+	Login string `json:"login"`
+	Name  string `json:"name"`
+	Slug  string `json:"slug"`
+}
+
+// ghIssue gh issue view --json 和 gh pr view --json 输出的字段，PR专有字段对Issue为空
+type ghIssue struct {
+	Number         int               `json:"number"`
+	Title          string            `json:"title"`
+	Body           string            `json:"body"`
+	State          string            `json:"state"` // OPEN、CLOSED 或 MERGED
+	Author         *ghUser           `json:"author"`
+	Assignees      []ghUser          `json:"assignees"`
+	Labels         []ghLabel         `json:"labels"`
+	Milestone      *ghMilestone      `json:"milestone"`
+	CreatedAt      time.Time         `json:"createdAt"`
+	UpdatedAt      time.Time         `json:"updatedAt"`
+	ClosedAt       *time.Time        `json:"closedAt"`
+	URL            string            `json:"url"`
+	ReactionGroups []ghReactionGroup `json:"reactionGroups"`
+	Comments       []ghComment       `json:"comments"`
+
+	IsDraft        bool   `json:"isDraft"`
+	HeadRefName    string `json:"headRefName"`
+	HeadRefOID     string `json:"headRefOid"`
+	BaseRefName    string `json:"baseRefName"`
+	HeadRepository *struct {
+		Name string `json:"name"`
+	} `json:"headRepository"`
+	HeadRepositoryOwner *ghUser    `json:"headRepositoryOwner"`
+	MergedAt            *time.Time `json:"mergedAt"`
+	MergedBy            *ghUser    `json:"mergedBy"`
+	MergeCommit         *struct {
+		OID string `json:"oid"`
+	} `json:"mergeCommit"`
+	Reviews        []ghReview        `json:"reviews"`
+	ReviewRequests []ghReviewRequest `json:"reviewRequests"`
+}
+
+// ReadSnapshot 读取一个Issue或Pull Request的JSON
+// 支持gh issue view --json和gh pr view --json的输出（至少包含number字段），以及issue2md的JSON输出；
+// gh输出带headRefName、baseRefName或MERGED状态，或url指向/pull/时按Pull Request处理；
+// issue2md的JSON输出带issue或pull_request数据时还原为Issue或Pull Request，否则原样返回渲染好的文档
+func ReadSnapshot(r io.Reader) (*Snapshot, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JSON input: %w", err)
+	}
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+		return nil, fmt.Errorf("JSON input is an array, expected a single issue or pull request")
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("invalid JSON input: %w", err)
+	}
+
+	switch {
+	case fields["number"] != nil:
+		var issue ghIssue
+		if err := json.Unmarshal(data, &issue); err != nil {
+			return nil, fmt.Errorf("invalid gh JSON input: %w", err)
+		}
+		return issue.snapshot(), nil
+	case fields["content"] != nil:
+		var doc parser.MarkdownDocument
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("invalid issue2md JSON input: %w", err)
+		}
+		switch {
+		case doc.PullRequest != nil:
+			return &Snapshot{PullRequest: doc.PullRequest}, nil
+		case doc.Issue != nil:
+			return &Snapshot{Issue: doc.Issue, Comments: doc.Comments}, nil
+		}
+		return &Snapshot{Document: &doc}, nil
+	default:
+		return nil, fmt.Errorf("unrecognized JSON input, expected gh --json output including number, or issue2md JSON output")
+	}
+}
+
+// snapshot 把gh输出转换为Issue或Pull Request
+func (i *ghIssue) snapshot() *Snapshot {
+	issue := model.Issue{
+		Number:     i.Number,
+		Title:      i.Title,
+		Body:       i.Body,
+		State:      strings.ToLower(i.State),
+		User:       i.Author.user(),
+		CreatedAt:  i.CreatedAt,
+		UpdatedAt:  i.UpdatedAt,
+		ClosedAt:   nonZeroTime(i.ClosedAt),
+		HTMLURL:    i.URL,
+		Reactions:  convertReactionGroups(i.ReactionGroups),
+		Repository: repositoryOf(i.URL),
+	}
+	if issue.State == "merged" {
+		issue.State = "closed"
+	}
+	for idx := range i.Assignees {
+		issue.Assignees = append(issue.Assignees, i.Assignees[idx].user())
+	}
+	for _, label := range i.Labels {
+		issue.Labels = append(issue.Labels, model.Label{Name: label.Name, Color: label.Color, Description: label.Description})
+	}
+	if m := i.Milestone; m != nil && m.Title != "" {
+		issue.Milestone = &model.Milestone{Title: m.Title, Number: m.Number, Description: m.Description, DueDate: m.DueOn}
+	}
+
+	comments := make([]*model.Comment, 0, len(i.Comments))
+	for _, c := range i.Comments {
+		comment := &model.Comment{
+			Body:            c.Body,
+			User:            c.Author.user(),
+			CreatedAt:       c.CreatedAt,
+			UpdatedAt:       c.CreatedAt,
+			HTMLURL:         c.URL,
+			Reactions:       convertReactionGroups(c.ReactionGroups),
+			Minimized:       c.IsMinimized,
+			MinimizedReason: strings.ToLower(c.MinimizedReason),
+		}
+		if u, err := url.Parse(c.URL); err == nil {
+			comment.ID, _ = strconv.ParseInt(strings.TrimPrefix(u.Fragment, "issuecomment-"), 10, 64)
+		}
+		comments = append(comments, comment)
+	}
+
+	if !i.isPullRequest() {
+		return &Snapshot{Issue: &issue, Comments: comments}
+	}
+
+	pr := &model.PullRequest{
+		Issue:    issue,
+		Draft:    i.IsDraft,
+		Merged:   strings.EqualFold(i.State, "merged") || nonZeroTime(i.MergedAt) != nil,
+		MergedAt: nonZeroTime(i.MergedAt),
+		Comments: comments,
+	}
+	if i.MergedBy != nil {
+		mergedBy := i.MergedBy.user()
+		pr.MergedBy = &mergedBy
+	}
+	if i.MergeCommit != nil {
+		pr.MergeCommitSHA = i.MergeCommit.OID
+	}
+
+	baseOwner, _, _ := strings.Cut(issue.Repository, "/")
+	headOwner := baseOwner
+	if i.HeadRepositoryOwner != nil && i.HeadRepositoryOwner.Login != "" {
+		headOwner = i.HeadRepositoryOwner.Login
+	}
+	pr.Base = model.Branch{Ref: i.BaseRefName, Label: baseOwner + ":" + i.BaseRefName, Repo: issue.Repository}
+	pr.Head = model.Branch{Ref: i.HeadRefName, Label: headOwner + ":" + i.HeadRefName, SHA: i.HeadRefOID}
+	if i.HeadRepository != nil && i.HeadRepository.Name != "" {
+		pr.Head.Repo = headOwner + "/" + i.HeadRepository.Name
+	}
+
+	for _, request := range i.ReviewRequests {
+		if request.Login != "" {
+			pr.RequestedReviewers = append(pr.RequestedReviewers, model.User{Login: request.Login})
+		} else if request.Slug != "" || request.Name != "" {
+			pr.RequestedTeams = append(pr.RequestedTeams, model.Team{Name: request.Name, Slug: request.Slug})
+		}
+	}
+	for _, r := range i.Reviews {
+		if r.State == "PENDING" {
+			continue
+		}
+		pr.Reviews = append(pr.Reviews, &model.Review{
+			User:        r.Author.user(),
+			Body:        r.Body,
+			State:       r.State,
+			SubmittedAt: nonZeroTime(r.SubmittedAt),
+			CommitID:    r.Commit.OID,
+		})
+	}
+	return &Snapshot{PullRequest: pr}
+}
+
+// isPullRequest 判断gh输出是否为Pull Request
+func (i *ghIssue) isPullRequest() bool {
+	if i.HeadRefName != "" || i.BaseRefName != "" || strings.EqualFold(i.State, "merged") {
+		return true
+	}
+	u, err := url.Parse(i.URL)
+	return err == nil && strings.Contains(u.Path, "/pull/")
+}
+
+// user 转换gh输出的用户，已删除的用户为空
+func (u *ghUser) user() model.User {
+	if u == nil {
+		return model.User{}
+	}
+	return model.User{Login: u.Login}
+}
+
+// convertReactionGroups 将gh输出的reactions分组汇总为Reactions
+func convertReactionGroups(groups []ghReactionGroup) model.Reactions {
+	var reactions model.Reactions
+	for _, group := range groups {
+		reactions.Add(group.Content, group.Users.TotalCount)
+	}
+	return reactions
+}
+
+// repositoryOf 从Issue或Pull Request的URL中解析仓库，如 https://github.com/org/repo/pull/1 返回 org/repo
+func repositoryOf(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) < 4 || (parts[2] != "issues" && parts[2] != "pull") {
+		return ""
+	}
+	return parts[0] + "/" + parts[1]
+}
+
+// nonZeroTime gh对未设置的时间可能输出零值，统一转换为nil
+func nonZeroTime(t *time.Time) *time.Time {
+	if t == nil || t.IsZero() {
+		return nil
+	}
+	return t
+}
@@ -0,0 +1,188 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+)
+
+const ghIssueJSON = `{
+	"number": 42,
+	"title": "Crash on startup",
+	"body": "Steps to reproduce",
+	"state": "CLOSED",
+	"author": {"id": "MDQ6VXNlcjE=", "is_bot": false, "login": "alice", "name": "Alice"},
+	"assignees": [{"id": "MDQ6VXNlcjI=", "login": "bob", "name": "Bob"}],
+	"labels": [{"id": "LA_1", "name": "bug", "description": "Something is broken", "color": "d73a4a"}],
+	"milestone": {"number": 3, "title": "v1.2", "description": "", "dueOn": null},
+	"createdAt": "2024-03-01T09:00:00Z",
+	"updatedAt": "2024-03-02T09:00:00Z",
+	"closedAt": "2024-03-02T09:00:00Z",
+	"url": "https://github.com/org/app/issues/42",
+	"reactionGroups": [{"content": "THUMBS_UP", "users": {"totalCount": 3}}, {"content": "EYES", "users": {"totalCount": 1}}],
+	"comments": [
+		{"id": "IC_1", "author": {"login": "bob"}, "authorAssociation": "MEMBER", "body": "Fixed in #43",
+		 "createdAt": "2024-03-01T10:00:00Z", "includesCreatedEdit": false, "isMinimized": false, "minimizedReason": "",
+		 "reactionGroups": [{"content": "HEART", "users": {"totalCount": 2}}],
+		 "url": "https://github.com/org/app/issues/42#issuecomment-1001", "viewerDidAuthor": false},
+		{"id": "IC_2", "author": {"login": "spammer"}, "body": "Buy now", "createdAt": "2024-03-01T11:00:00Z",
+		 "isMinimized": true, "minimizedReason": "SPAM", "reactionGroups": [],
+		 "url": "https://github.com/org/app/issues/42#issuecomment-1002"}
+	]
+}`
+
+const ghPullRequestJSON = `{
+	"number": 43,
+	"title": "Fix crash",
+	"body": "Fixes #42",
+	"state": "MERGED",
+	"author": {"login": "bob"},
+	"isDraft": false,
+	"headRefName": "fix-crash",
+	"headRefOid": "abc123",
+	"headRepository": {"id": "R_1", "name": "app-fork"},
+	"headRepositoryOwner": {"id": "U_1", "login": "bob"},
+	"baseRefName": "main",
+	"mergedAt": "2024-03-02T08:00:00Z",
+	"mergedBy": {"login": "alice"},
+	"mergeCommit": {"oid": "def456"},
+	"createdAt": "2024-03-01T12:00:00Z",
+	"updatedAt": "2024-03-02T08:00:00Z",
+	"closedAt": "2024-03-02T08:00:00Z",
+	"url": "https://github.com/org/app/pull/43",
+	"reviewRequests": [{"__typename": "User", "login": "carol"}, {"__typename": "Team", "name": "Core", "slug": "core"}],
+	"reviews": [
+		{"id": "PRR_1", "author": {"login": "alice"}, "body": "LGTM", "state": "APPROVED",
+		 "submittedAt": "2024-03-02T07:00:00Z", "commit": {"oid": "abc123"}},
+		{"id": "PRR_2", "author": {"login": "carol"}, "body": "", "state": "PENDING", "submittedAt": null, "commit": {"oid": "abc123"}}
+	],
+	"comments": []
+}`
+
+const issue2mdJSON = `{
+	"title": "Crash on startup",
+	"content": "# Crash on startup - Closed\n\nSteps to reproduce\n",
+	"metadata": {"number": "42", "state": "closed"}
+}`
+
+func TestReadSnapshotIssue(t *testing.T) {
+	snapshot, err := ReadSnapshot(strings.NewReader(ghIssueJSON))
+	if err != nil {
+		t.Fatalf("ReadSnapshot() error = %v", err)
+	}
+	if snapshot.Issue == nil || snapshot.PullRequest != nil || snapshot.Document != nil {
+		t.Fatalf("snapshot = %+v, want an issue", snapshot)
+	}
+
+	issue := snapshot.Issue
+	if issue.Number != 42 || issue.State != "closed" || issue.ClosedAt == nil || issue.Repository != "org/app" {
+		t.Errorf("issue = %+v", issue)
+	}
+	if issue.User.Login != "alice" || len(issue.Assignees) != 1 || issue.Assignees[0].Login != "bob" {
+		t.Errorf("User = %+v, Assignees = %+v", issue.User, issue.Assignees)
+	}
+	if len(issue.Labels) != 1 || issue.Labels[0].Name != "bug" || issue.Labels[0].Color != "d73a4a" {
+		t.Errorf("Labels = %+v", issue.Labels)
+	}
+	if issue.Milestone == nil || issue.Milestone.Title != "v1.2" || issue.Milestone.Number != 3 {
+		t.Errorf("Milestone = %+v", issue.Milestone)
+	}
+	if issue.Reactions.TotalCount != 4 || issue.Reactions.ThumbsUp != 3 || issue.Reactions.Eyes != 1 {
+		t.Errorf("Reactions = %+v", issue.Reactions)
+	}
+
+	comments := snapshot.Comments
+	if len(comments) != 2 {
+		t.Fatalf("got %d comments, want 2", len(comments))
+	}
+	if c := comments[0]; c.ID != 1001 || c.User.Login != "bob" || c.Reactions.Heart != 2 || c.Minimized {
+		t.Errorf("comment = %+v", c)
+	}
+	if c := comments[1]; !c.Minimized || c.MinimizedReason != "spam" {
+		t.Errorf("minimized comment = %+v", c)
+	}
+}
+
+func TestReadSnapshotPullRequest(t *testing.T) {
+	snapshot, err := ReadSnapshot(strings.NewReader(ghPullRequestJSON))
+	if err != nil {
+		t.Fatalf("ReadSnapshot() error = %v", err)
+	}
+	pr := snapshot.PullRequest
+	if pr == nil {
+		t.Fatalf("snapshot = %+v, want a pull request", snapshot)
+	}
+
+	if !pr.Merged || pr.State != "closed" || pr.MergedBy == nil || pr.MergedBy.Login != "alice" || pr.MergeCommitSHA != "def456" {
+		t.Errorf("merge = %v %s %+v %s", pr.Merged, pr.State, pr.MergedBy, pr.MergeCommitSHA)
+	}
+	if pr.Head.Label != "bob:fix-crash" || pr.Head.Repo != "bob/app-fork" || pr.Head.SHA != "abc123" {
+		t.Errorf("Head = %+v", pr.Head)
+	}
+	if pr.Base.Label != "org:main" || pr.Base.Repo != "org/app" {
+		t.Errorf("Base = %+v", pr.Base)
+	}
+	if len(pr.RequestedReviewers) != 1 || pr.RequestedReviewers[0].Login != "carol" {
+		t.Errorf("RequestedReviewers = %+v", pr.RequestedReviewers)
+	}
+	if len(pr.RequestedTeams) != 1 || pr.RequestedTeams[0].Slug != "core" {
+		t.Errorf("RequestedTeams = %+v", pr.RequestedTeams)
+	}
+	if len(pr.Reviews) != 1 || pr.Reviews[0].State != "APPROVED" || pr.Reviews[0].CommitID != "abc123" {
+		t.Errorf("Reviews = %+v", pr.Reviews)
+	}
+}
+
+func TestReadSnapshot(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		wantKind string
+		wantErr  string
+	}{
+		{name: "minimal gh issue", input: `{"number": 1, "title": "x"}`, wantKind: "issue"},
+		{name: "gh pr by URL", input: `{"number": 2, "url": "https://github.com/o/r/pull/2"}`, wantKind: "pull"},
+		{name: "issue2md output", input: issue2mdJSON, wantKind: "document"},
+		{
+			name:     "issue2md output with issue",
+			input:    `{"title": "x", "content": "# x", "issue": {"number": 1, "title": "x"}, "comments": [{"body": "y"}]}`,
+			wantKind: "issue",
+		},
+		{
+			name:     "issue2md output with pull request",
+			input:    `{"title": "x", "content": "# x", "pull_request": {"number": 2, "title": "x", "merged": true}}`,
+			wantKind: "pull",
+		},
+		{name: "array", input: `[{"number": 1}]`, wantErr: "JSON input is an array"},
+		{name: "unknown object", input: `{"name": "x"}`, wantErr: "unrecognized JSON input"},
+		{name: "invalid JSON", input: `{"number":`, wantErr: "invalid JSON input"},
+		{name: "wrong field type", input: `{"number": "one"}`, wantErr: "invalid gh JSON input"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			snapshot, err := ReadSnapshot(strings.NewReader(tt.input))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ReadSnapshot() error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ReadSnapshot() error = %v", err)
+			}
+
+			var kind string
+			switch {
+			case snapshot.PullRequest != nil:
+				kind = "pull"
+			case snapshot.Issue != nil:
+				kind = "issue"
+			case snapshot.Document != nil:
+				kind = "document"
+			}
+			if kind != tt.wantKind {
+				t.Errorf("ReadSnapshot() kind = %q, want %q", kind, tt.wantKind)
+			}
+		})
+	}
+}
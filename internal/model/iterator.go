@@ -0,0 +1,85 @@
+package model
+
+import (
+	"context"
+)
+
+// commentPageFunc 获取指定页的评论，返回评论列表和下一页页码（0 表示没有下一页）
+type commentPageFunc func(ctx context.Context, page int) ([]*Comment, int, error)
+
+// CommentIterator 评论流式迭代器
+// 按页拉取评论，适合处理包含成千上万条评论的讨论而无需一次性载入内存
+//
+// 使用方式:
+//
+//	it := client.IssueComments(owner, repo, number)
+//	for it.Next(ctx) {
+//		comment := it.Comment()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type CommentIterator struct {
+	fetch    commentPageFunc
+	page     []*Comment
+	index    int
+	nextPage int
+	current  *Comment
+	done     bool
+	err      error
+}
+
+// NewCommentIterator 创建从第一页开始按页拉取评论的迭代器，供各平台的客户端使用
+// fetch返回指定页（从1开始）的评论和下一页页码，0表示没有下一页
+func NewCommentIterator(fetch func(ctx context.Context, page int) ([]*Comment, int, error)) *CommentIterator {
+	return &CommentIterator{
+		fetch:    fetch,
+		nextPage: 1,
+	}
+}
+
+// Next 前进到下一条评论
+// 当前页读完时自动请求下一页，没有更多评论或出错时返回false
+func (it *CommentIterator) Next(ctx context.Context) bool {
+	if it.err != nil {
+		return false
+	}
+
+	for it.index >= len(it.page) {
+		if it.done {
+			it.current = nil
+			return false
+		}
+
+		if err := ctx.Err(); err != nil {
+			it.err = err
+			return false
+		}
+
+		comments, nextPage, err := it.fetch(ctx, it.nextPage)
+		if err != nil {
+			it.err = err
+			return false
+		}
+
+		it.page = comments
+		it.index = 0
+		it.nextPage = nextPage
+		it.done = nextPage == 0
+	}
+
+	it.current = it.page[it.index]
+	it.index++
+	return true
+}
+
+// Comment 返回当前评论，必须在 Next 返回true之后调用
+func (it *CommentIterator) Comment() *Comment {
+	return it.current
+}
+
+// Err 返回遍历过程中遇到的第一个错误
+func (it *CommentIterator) Err() error {
+	return it.err
+}
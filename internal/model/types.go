@@ -0,0 +1,371 @@
+// Package model 定义与平台无关的Issue、Pull Request、Discussion等数据结构
+// GitHub、GitLab、Gitea客户端和各种导入器都把数据转换为这些结构，解析器据此渲染文档
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// Issue 表示一个GitHub Issue
+type Issue struct {
+	Number    int              `json:"number"`
+	Title     string           `json:"title"`
+	Body      string           `json:"body"`
+	State     string           `json:"state"`
+	User      User             `json:"user"`
+	Labels    []Label          `json:"labels"`
+	Assignees []User           `json:"assignees"`
+	Milestone *Milestone       `json:"milestone,omitempty"`
+	CreatedAt time.Time        `json:"created_at"`
+	UpdatedAt time.Time        `json:"updated_at"`
+	ClosedAt  *time.Time       `json:"closed_at,omitempty"`
+	URL       string           `json:"url"`
+	HTMLURL   string           `json:"html_url"`
+	Reactions Reactions        `json:"reactions"`
+	Timeline  []*TimelineEvent `json:"timeline,omitempty"` // 由GetIssueTimeline填充
+	// IsPullRequest 是否为Pull Request，仅Issue列表接口会返回Pull Request
+	IsPullRequest bool `json:"is_pull_request,omitempty"`
+	// Repository 所属仓库，格式为 owner/repo
+	Repository string `json:"repository,omitempty"`
+	// Parent 父Issue，SubIssues 子Issue和正文任务列表跟踪的Issue，由GetParentIssue等填充
+	Parent    *IssueReference `json:"parent,omitempty"`
+	SubIssues []*SubIssue     `json:"sub_issues,omitempty"`
+	// Resolution 关闭Issue的Pull Request和提交，由GetIssueResolution填充
+	Resolution *Resolution `json:"resolution,omitempty"`
+	// Edits 正文的修改历史，由GetEditHistory填充
+	Edits []*Edit `json:"edits,omitempty"`
+	// Projects 所属的Projects (v2)条目及字段值，由GetProjectItems填充
+	Projects []*ProjectItem `json:"projects,omitempty"`
+}
+
+// TimelineEvent 表示Issue时间线上的一个事件
+// Event取值见Event*常量，其余字段按事件类型择一填充
+type TimelineEvent struct {
+	Event       string          `json:"event"`
+	Actor       User            `json:"actor"`
+	CreatedAt   time.Time       `json:"created_at"`
+	Label       string          `json:"label,omitempty"`
+	Assignee    *User           `json:"assignee,omitempty"`
+	Milestone   string          `json:"milestone,omitempty"`
+	StateReason string          `json:"state_reason,omitempty"` // closed事件: completed、not_planned 或 duplicate
+	RenameFrom  string          `json:"rename_from,omitempty"`
+	RenameTo    string          `json:"rename_to,omitempty"`
+	Source      *IssueReference `json:"source,omitempty"` // 交叉引用的来源，或重复Issue的原始Issue
+	FromRepo    string          `json:"from_repo,omitempty"`
+}
+
+// IssueReference 表示对某个Issue或PR的引用
+type IssueReference struct {
+	Type   string `json:"type"` // issue 或 pull
+	Repo   string `json:"repo"`
+	Number int    `json:"number"`
+	Title  string `json:"title,omitempty"`
+	State  string `json:"state,omitempty"`
+	URL    string `json:"url"`
+	// File 递归导出时被引用Issue的导出文件，设置后文档中链接到该文件而不是GitHub
+	File string `json:"file,omitempty"`
+}
+
+// SubIssue 子Issue引用，递归导出时Children为它自己的子Issue
+type SubIssue struct {
+	IssueReference
+	Children []*SubIssue `json:"children,omitempty"`
+}
+
+// Comment 表示Issue评论
+// 对于Pull Request的代码评审评论，Path等字段描述评论所在的代码位置
+type Comment struct {
+	ID          int64      `json:"id"`
+	Body        string     `json:"body"`
+	User        User       `json:"user"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	URL         string     `json:"url"`
+	HTMLURL     string     `json:"html_url"`
+	Path        string     `json:"path,omitempty"`
+	Line        int        `json:"line,omitempty"`
+	DiffHunk    string     `json:"diff_hunk,omitempty"`
+	ReviewID    int64      `json:"review_id,omitempty"`
+	InReplyToID int64      `json:"in_reply_to_id,omitempty"`
+	IsAnswer    bool       `json:"is_answer,omitempty"` // 仅用于Discussion
+	Replies     []*Comment `json:"replies,omitempty"`   // 仅用于Discussion
+	Reactions   Reactions  `json:"reactions"`
+	Edits       []*Edit    `json:"edits,omitempty"` // 修改历史，由GetEditHistory填充
+	// Minimized 评论是否被版主折叠，MinimizedReason为折叠原因，如 spam、off-topic、outdated、resolved
+	Minimized       bool   `json:"minimized,omitempty"`
+	MinimizedReason string `json:"minimized_reason,omitempty"`
+}
+
+// IsReviewComment 判断评论是否为代码评审评论
+func (c *Comment) IsReviewComment() bool {
+	return c.Path != ""
+}
+
+// PullRequest 表示一个GitHub Pull Request
+// 内嵌Issue承载标题、正文、标签等与Issue共有的字段
+type PullRequest struct {
+	Issue
+	Draft              bool       `json:"draft"`
+	Merged             bool       `json:"merged"`
+	MergedAt           *time.Time `json:"merged_at,omitempty"`
+	MergedBy           *User      `json:"merged_by,omitempty"`
+	MergeCommitSHA     string     `json:"merge_commit_sha,omitempty"`
+	Head               Branch     `json:"head"`
+	Base               Branch     `json:"base"`
+	RequestedReviewers []User     `json:"requested_reviewers,omitempty"`
+	RequestedTeams     []Team     `json:"requested_teams,omitempty"`
+	Reviews            []*Review  `json:"reviews,omitempty"`
+	Comments           []*Comment `json:"comments,omitempty"` // 普通评论与代码评审评论，按创建时间排序
+}
+
+// Status 返回PR状态，已合并的PR返回"merged"
+func (pr *PullRequest) Status() string {
+	if pr.Merged {
+		return "merged"
+	}
+	return pr.State
+}
+
+// Discussion 表示一个GitHub Discussion
+type Discussion struct {
+	Number         int                `json:"number"`
+	Title          string             `json:"title"`
+	Body           string             `json:"body"`
+	User           User               `json:"user"`
+	Category       DiscussionCategory `json:"category"`
+	Labels         []Label            `json:"labels"`
+	Closed         bool               `json:"closed"`
+	Answered       bool               `json:"answered"`
+	Answer         *Comment           `json:"-"` // 指向Comments中被采纳的答案
+	AnswerChosenAt *time.Time         `json:"answer_chosen_at,omitempty"`
+	AnswerChosenBy *User              `json:"answer_chosen_by,omitempty"`
+	CreatedAt      time.Time          `json:"created_at"`
+	UpdatedAt      time.Time          `json:"updated_at"`
+	ClosedAt       *time.Time         `json:"closed_at,omitempty"`
+	HTMLURL        string             `json:"html_url"`
+	Reactions      Reactions          `json:"reactions"`
+	Comments       []*Comment         `json:"comments,omitempty"`
+}
+
+// Status 返回Discussion状态: answered、closed 或 open
+func (d *Discussion) Status() string {
+	switch {
+	case d.Answered:
+		return "answered"
+	case d.Closed:
+		return "closed"
+	default:
+		return "open"
+	}
+}
+
+// DiscussionCategory 表示Discussion分类
+type DiscussionCategory struct {
+	Name         string `json:"name"`
+	Emoji        string `json:"emoji"`
+	Slug         string `json:"slug"`
+	IsAnswerable bool   `json:"is_answerable"`
+}
+
+// Branch 表示PR的源分支或目标分支
+type Branch struct {
+	Label string `json:"label"`
+	Ref   string `json:"ref"`
+	SHA   string `json:"sha"`
+	Repo  string `json:"repo,omitempty"` // owner/name
+}
+
+// Team 表示被请求评审的团队
+type Team struct {
+	Name    string `json:"name"`
+	Slug    string `json:"slug"`
+	HTMLURL string `json:"html_url"`
+}
+
+// Review 表示PR评审结论
+type Review struct {
+	ID          int64      `json:"id"`
+	User        User       `json:"user"`
+	Body        string     `json:"body"`
+	State       string     `json:"state"` // APPROVED, CHANGES_REQUESTED, COMMENTED, DISMISSED, PENDING
+	SubmittedAt *time.Time `json:"submitted_at,omitempty"`
+	CommitID    string     `json:"commit_id"`
+	HTMLURL     string     `json:"html_url"`
+}
+
+// Reactions 表示reactions统计
+type Reactions struct {
+	TotalCount int `json:"total_count"`
+	ThumbsUp   int `json:"thumbs_up"`
+	ThumbsDown int `json:"thumbs_down"`
+	Laugh      int `json:"laugh"`
+	Hooray     int `json:"hooray"`
+	Confused   int `json:"confused"`
+	Heart      int `json:"heart"`
+	Rocket     int `json:"rocket"`
+	Eyes       int `json:"eyes"`
+}
+
+// Add 累加count个content类型的表情回应，未知类型忽略
+// content不区分大小写，支持REST接口的 +1、-1、laugh 等和GraphQL的 THUMBS_UP、THUMBS_DOWN、LAUGH 等
+func (r *Reactions) Add(content string, count int) {
+	switch strings.ToLower(content) {
+	case "+1", "thumbs_up":
+		r.ThumbsUp += count
+	case "-1", "thumbs_down":
+		r.ThumbsDown += count
+	case "laugh":
+		r.Laugh += count
+	case "hooray":
+		r.Hooray += count
+	case "confused":
+		r.Confused += count
+	case "heart":
+		r.Heart += count
+	case "rocket":
+		r.Rocket += count
+	case "eyes":
+		r.Eyes += count
+	default:
+		return
+	}
+	r.TotalCount += count
+}
+
+// User 表示GitHub用户
+type User struct {
+	Login     string `json:"login"`
+	ID        int64  `json:"id"`
+	AvatarURL string `json:"avatar_url"`
+	HTMLURL   string `json:"html_url"`
+	Type      string `json:"type"`
+}
+
+// Label 表示Issue标签
+type Label struct {
+	Name        string `json:"name"`
+	Color       string `json:"color"`
+	Description string `json:"description,omitempty"`
+}
+
+// Milestone 表示Issue里程碑
+type Milestone struct {
+	Title       string     `json:"title"`
+	Number      int        `json:"number"`
+	State       string     `json:"state"`
+	Description string     `json:"description"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	DueDate     *time.Time `json:"due_date,omitempty"`
+	ClosedAt    *time.Time `json:"closed_at,omitempty"`
+}
+
+// Repository 表示GitHub仓库
+type Repository struct {
+	Owner    string `json:"owner"`
+	Name     string `json:"name"`
+	FullName string `json:"full_name"`
+	URL      string `json:"url"`
+	HTMLURL  string `json:"html_url"`
+}
+
+// 时间线事件类型
+const (
+	EventLabeled             = "labeled"
+	EventUnlabeled           = "unlabeled"
+	EventAssigned            = "assigned"
+	EventUnassigned          = "unassigned"
+	EventMilestoned          = "milestoned"
+	EventDemilestoned        = "demilestoned"
+	EventClosed              = "closed"
+	EventReopened            = "reopened"
+	EventRenamed             = "renamed"
+	EventCrossReferenced     = "cross-referenced"
+	EventTransferred         = "transferred"
+	EventMarkedAsDuplicate   = "marked_as_duplicate"
+	EventUnmarkedAsDuplicate = "unmarked_as_duplicate"
+)
+
+// Edit 正文或评论的一个历史版本
+// 第一次修改时GitHub会同时记录原始内容，因此有修改历史的内容至少有两个版本
+type Edit struct {
+	Editor   User      `json:"editor"`
+	EditedAt time.Time `json:"edited_at"`
+	Body     string    `json:"body"`              // 该版本的完整内容
+	Deleted  bool      `json:"deleted,omitempty"` // 该版本已被删除，Body为空
+}
+
+// EditHistory Issue或Pull Request正文及其评论的修改历史，各版本按时间升序排列
+type EditHistory struct {
+	Body     []*Edit
+	Comments map[int64][]*Edit // 按评论ID索引，没有修改过的评论不在其中
+}
+
+// ProjectItem Issue或Pull Request在一个Projects (v2)项目中的条目
+type ProjectItem struct {
+	Project string          `json:"project"` // 项目标题
+	Number  int             `json:"number"`
+	URL     string          `json:"url"`
+	Fields  []*ProjectField `json:"fields,omitempty"` // 按字段名排序
+}
+
+// ProjectField 条目的一个自定义字段值
+type ProjectField struct {
+	Name string `json:"name"`
+	// Value 单选、文本、日期和迭代字段为string（迭代为迭代标题），数字字段为float64
+	Value interface{} `json:"value"`
+}
+
+// Resolution 描述Issue是如何被解决的
+type Resolution struct {
+	// ClosedBy 关闭Issue的Pull Request，包括Development中关联的和通过"Fixes #n"关闭的
+	ClosedBy []*ClosingPullRequest `json:"closed_by,omitempty"`
+	// Commit 关闭Issue的提交，通过PR关闭时为PR的合并提交，PR未合并时为nil
+	Commit *ClosingCommit `json:"commit,omitempty"`
+}
+
+// ClosingPullRequest 关闭Issue的Pull Request
+type ClosingPullRequest struct {
+	Repo     string     `json:"repo"`
+	Number   int        `json:"number"`
+	Title    string     `json:"title"`
+	URL      string     `json:"url"`
+	State    string     `json:"state"` // open、closed 或 merged
+	MergedAt *time.Time `json:"merged_at,omitempty"`
+}
+
+// ClosingCommit 关闭Issue的提交
+type ClosingCommit struct {
+	SHA     string `json:"sha"`
+	Message string `json:"message"` // 提交信息的第一行
+	URL     string `json:"url"`
+}
+
+// Asset 下载的附件或图片
+type Asset struct {
+	Data        []byte
+	ContentType string
+}
+
+// IssueListOptions 列出仓库Issue时的过滤和排序条件，零值表示不过滤
+type IssueListOptions struct {
+	// State open、closed或all，为空时为open
+	State string
+	// Labels 必须同时带有的标签
+	Labels []string
+	// Milestone 里程碑编号或标题，"*"表示有任意里程碑，"none"表示没有里程碑
+	Milestone string
+	// Assignee 指派人用户名，"*"表示已指派，"none"表示未指派
+	Assignee string
+	// Creator 创建者用户名
+	Creator string
+	// Since 只列出在此时间之后更新过的Issue
+	Since time.Time
+	// Sort created、updated或comments，为空时为created
+	Sort string
+	// Direction asc或desc，为空时为desc
+	Direction string
+	// ExcludePullRequests 跳过Pull Request，GitHub的Issue列表接口默认包含Pull Request
+	ExcludePullRequests bool
+}
@@ -0,0 +1,75 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIssue(t *testing.T) {
+	now := time.Now()
+	issue := &Issue{
+		Number:    123,
+		Title:     "Test Issue",
+		Body:      "This is a test issue",
+		State:     "open",
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if issue.Number != 123 {
+		t.Errorf("Expected issue number 123, got %d", issue.Number)
+	}
+
+	if issue.Title != "Test Issue" {
+		t.Errorf("Expected title 'Test Issue', got %s", issue.Title)
+	}
+}
+
+func TestComment(t *testing.T) {
+	now := time.Now()
+	comment := &Comment{
+		ID:        456,
+		Body:      "This is a test comment",
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if comment.ID != 456 {
+		t.Errorf("Expected comment ID 456, got %d", comment.ID)
+	}
+
+	if comment.Body != "This is a test comment" {
+		t.Errorf("Expected body 'This is a test comment', got %s", comment.Body)
+	}
+}
+
+func TestUser(t *testing.T) {
+	user := &User{
+		Login:     "testuser",
+		ID:        789,
+		AvatarURL: "https://example.com/avatar.jpg",
+		Type:      "User",
+	}
+
+	if user.Login != "testuser" {
+		t.Errorf("Expected login 'testuser', got %s", user.Login)
+	}
+
+	if user.ID != 789 {
+		t.Errorf("Expected ID 789, got %d", user.ID)
+	}
+}
+
+func TestReactionsAdd(t *testing.T) {
+	var reactions Reactions
+	// REST接口和GraphQL的表情名称都能识别，未知类型不计数
+	for _, content := range []string{"+1", "THUMBS_UP", "-1", "laugh", "HOORAY", "confused", "heart", "ROCKET", "eyes", "unknown"} {
+		reactions.Add(content, 1)
+	}
+	reactions.Add("THUMBS_DOWN", 2)
+
+	want := Reactions{TotalCount: 11, ThumbsUp: 2, ThumbsDown: 3, Laugh: 1, Hooray: 1, Confused: 1, Heart: 1, Rocket: 1, Eyes: 1}
+	if reactions != want {
+		t.Errorf("Reactions = %+v, want %+v", reactions, want)
+	}
+}
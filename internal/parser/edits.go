@@ -0,0 +1,145 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bigwhite/issue2md/internal/model"
+)
+
+// diffContextLines 修改历史的差异中改动前后保留的未改动行数
+const diffContextLines = 2
+
+// writeEdits 将修改历史渲染为可折叠的差异，每个版本与前一个未删除的版本比较
+// 少于两个版本时没有可展示的修改
+func (p *MarkdownParser) writeEdits(b *strings.Builder, edits []*model.Edit) {
+	if len(edits) < 2 {
+		return
+	}
+
+	fmt.Fprintf(b, "\n<details>\n<summary>Edit history (%d edits)</summary>\n", len(edits)-1)
+	previous := edits[0].Body
+	for _, edit := range edits[1:] {
+		b.WriteString("\n**" + p.formatUser(edit.Editor) + " edited")
+		if p.options.IncludeTimestamps {
+			b.WriteString(" " + formatDisplayTime(edit.EditedAt))
+		}
+		b.WriteString("**")
+		if edit.Deleted {
+			b.WriteString(" *(this revision was deleted)*\n")
+			continue
+		}
+		b.WriteString("\n\n")
+
+		diff := strings.Join(lineDiff(previous, edit.Body), "\n")
+		fence := codeFence(diff)
+		fmt.Fprintf(b, "%sdiff\n%s\n%s\n", fence, diff, fence)
+		previous = edit.Body
+	}
+	b.WriteString("\n</details>\n")
+}
+
+// hasEdits 判断正文或任意评论是否带有修改历史
+func hasEdits(edits []*model.Edit, comments []*model.Comment) bool {
+	if len(edits) > 1 {
+		return true
+	}
+	for _, comment := range comments {
+		if hasEdits(comment.Edits, comment.Replies) {
+			return true
+		}
+	}
+	return false
+}
+
+// lineDiff 返回从before到after的逐行差异，每行以"-"、"+"或" "开头
+// 未改动的行只保留改动前后各diffContextLines行，省略的部分用"@@"表示
+func lineDiff(before, after string) []string {
+	a := strings.Split(strings.ReplaceAll(before, "\r\n", "\n"), "\n")
+	c := strings.Split(strings.ReplaceAll(after, "\r\n", "\n"), "\n")
+
+	// lcs[i][j] 为a[i:]与c[j:]的最长公共子序列长度
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(c)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(c) - 1; j >= 0; j-- {
+			switch {
+			case a[i] == c[j]:
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var lines []string
+	i, j := 0, 0
+	for i < len(a) || j < len(c) {
+		switch {
+		case i < len(a) && j < len(c) && a[i] == c[j]:
+			lines = append(lines, " "+a[i])
+			i++
+			j++
+		case j == len(c) || (i < len(a) && lcs[i+1][j] >= lcs[i][j+1]):
+			lines = append(lines, "-"+a[i])
+			i++
+		default:
+			lines = append(lines, "+"+c[j])
+			j++
+		}
+	}
+	return trimDiffContext(lines)
+}
+
+// trimDiffContext 省略离改动较远的未改动行
+func trimDiffContext(lines []string) []string {
+	keep := make([]bool, len(lines))
+	for i, line := range lines {
+		if line[0] == ' ' {
+			continue
+		}
+		for k := i - diffContextLines; k <= i+diffContextLines; k++ {
+			if k >= 0 && k < len(lines) {
+				keep[k] = true
+			}
+		}
+	}
+
+	var trimmed []string
+	skipped := false
+	for i, line := range lines {
+		if !keep[i] {
+			skipped = true
+			continue
+		}
+		if skipped && len(trimmed) > 0 {
+			trimmed = append(trimmed, "@@")
+		}
+		skipped = false
+		trimmed = append(trimmed, line)
+	}
+	return trimmed
+}
+
+// codeFence 返回比内容中最长的连续反引号更长的代码块围栏，至少三个反引号
+func codeFence(content string) string {
+	longest, run := 0, 0
+	for _, r := range content {
+		if r == '`' {
+			run++
+			if run > longest {
+				longest = run
+			}
+			continue
+		}
+		run = 0
+	}
+	if longest < 3 {
+		return "```"
+	}
+	return strings.Repeat("`", longest+1)
+}
@@ -0,0 +1,117 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// frontmatterField YAML frontmatter中的一个字段
+type frontmatterField struct {
+	Key      string
+	Value    interface{}          // string和time.Time加引号输出，其他类型原样输出
+	Children []frontmatterField   // 非空时输出为嵌套映射
+	Items    [][]frontmatterField // 非空时输出为流式的映射列表，如 [{pr: 1}, {pr: 2}]
+}
+
+// writeFrontmatter 将字段按顺序写为YAML frontmatter
+func writeFrontmatter(b *strings.Builder, fields []frontmatterField) {
+	b.WriteString("---\n")
+	writeFrontmatterFields(b, fields, 0)
+	b.WriteString("---\n")
+}
+
+// writeFrontmatterFields 写入一组字段，indent为嵌套层级
+func writeFrontmatterFields(b *strings.Builder, fields []frontmatterField, indent int) {
+	prefix := strings.Repeat("  ", indent)
+	for _, field := range fields {
+		if len(field.Children) > 0 {
+			fmt.Fprintf(b, "%s%s:\n", prefix, formatYAMLKey(field.Key))
+			writeFrontmatterFields(b, field.Children, indent+1)
+			continue
+		}
+		if len(field.Items) > 0 {
+			fmt.Fprintf(b, "%s%s: %s\n", prefix, formatYAMLKey(field.Key), formatYAMLItems(field.Items))
+			continue
+		}
+		fmt.Fprintf(b, "%s%s: %s\n", prefix, formatYAMLKey(field.Key), formatYAMLValue(field.Value))
+	}
+}
+
+// flattenFrontmatter 将字段展平为字符串映射，嵌套字段使用点号连接键名
+func flattenFrontmatter(metadata map[string]string, fields []frontmatterField, prefix string) {
+	for _, field := range fields {
+		key := prefix + field.Key
+		if len(field.Children) > 0 {
+			flattenFrontmatter(metadata, field.Children, key+".")
+			continue
+		}
+		if len(field.Items) > 0 {
+			metadata[key] = formatYAMLItems(field.Items)
+			continue
+		}
+		metadata[key] = formatMetadataValue(field.Value)
+	}
+}
+
+// formatYAMLItems 将映射列表格式化为YAML流式写法
+func formatYAMLItems(items [][]frontmatterField) string {
+	var parts []string
+	for _, item := range items {
+		var pairs []string
+		for _, field := range item {
+			pairs = append(pairs, field.Key+": "+formatYAMLValue(field.Value))
+		}
+		parts = append(parts, "{"+strings.Join(pairs, ", ")+"}")
+	}
+	return "[" + strings.Join(parts, ", ") + "]"
+}
+
+// emptyMap 输出为YAML空映射 {} 的值
+type emptyMap struct{}
+
+// yamlPlainKey 无需加引号的YAML键
+var yamlPlainKey = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_ .-]*$`)
+
+// formatYAMLKey 格式化YAML键，含有冒号、#等特殊字符或首尾空格的键（如项目字段名）加引号输出
+func formatYAMLKey(key string) string {
+	if yamlPlainKey.MatchString(key) && !strings.HasSuffix(key, " ") {
+		return key
+	}
+	return strconv.Quote(key)
+}
+
+// formatYAMLValue 格式化YAML标量值，nil输出为null
+func formatYAMLValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case emptyMap:
+		return "{}"
+	case string:
+		return strconv.Quote(v)
+	case time.Time:
+		return strconv.Quote(formatRFC3339(v))
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// formatMetadataValue 格式化元数据映射中的值
+func formatMetadataValue(value interface{}) string {
+	switch v := value.(type) {
+	case time.Time:
+		return formatRFC3339(v)
+	case emptyMap:
+		return "{}"
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// formatRFC3339 以UTC的RFC3339格式输出时间
+func formatRFC3339(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,521 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bigwhite/issue2md/internal/model"
+)
+
+// displayTimeFormat 正文中展示时间的格式
+const displayTimeFormat = "2006-01-02 15:04:05 UTC"
+
+// resource 渲染所需的通用资源信息，由Issue、Discussion等转换而来
+type resource struct {
+	Type      string // issue, pr, discussion
+	Title     string
+	URL       string
+	Author    model.User
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Status    string
+	Body      string
+	Reactions model.Reactions
+	Details   []string // 追加在头部元信息之后的行
+	Comments  []*model.Comment
+	Events    []*model.TimelineEvent // 与评论交错展示的时间线事件
+	SubIssues []*model.SubIssue      // 展示为正文之后的任务清单
+	Edits     []*model.Edit          // 正文的修改历史
+	Projects  []*model.ProjectItem   // 写入frontmatter的projects映射
+
+	// 以下字段只用于Issue
+	ClosedAt   *time.Time
+	Resolution *model.Resolution // 展示为Resolution一节，并写入frontmatter的closed_by
+}
+
+// Parse 将Issue及其评论渲染为Markdown文档
+// issue.Timeline中的事件会按时间与评论交错展示，父Issue列在元信息中，子Issue渲染为嵌套的任务清单
+func (p *MarkdownParser) Parse(issue *model.Issue, comments []*model.Comment) (*MarkdownDocument, error) {
+	if issue == nil {
+		return nil, NewProcessingError("issue is nil", "NIL_RESOURCE", "")
+	}
+
+	var details []string
+	if issue.Parent != nil {
+		details = append(details, "**父Issue:** "+formatSubIssue(issue.Parent))
+	}
+
+	doc := p.render(&resource{
+		Type:       "issue",
+		Title:      issue.Title,
+		URL:        issue.HTMLURL,
+		Author:     issue.User,
+		CreatedAt:  issue.CreatedAt,
+		UpdatedAt:  issue.UpdatedAt,
+		Status:     issue.State,
+		Body:       issue.Body,
+		Reactions:  issue.Reactions,
+		Details:    details,
+		Comments:   comments,
+		Events:     issue.Timeline,
+		SubIssues:  issue.SubIssues,
+		ClosedAt:   issue.ClosedAt,
+		Resolution: issue.Resolution,
+		Edits:      issue.Edits,
+		Projects:   issue.Projects,
+	})
+	doc.Issue = issue
+	doc.Comments = comments
+	return doc, nil
+}
+
+// ParsePullRequest 将Pull Request渲染为Markdown文档
+// 不包含代码diff，代码评审评论与普通评论按时间交错展示并标注所在文件行
+func (p *MarkdownParser) ParsePullRequest(pr *model.PullRequest) (*MarkdownDocument, error) {
+	if pr == nil {
+		return nil, NewProcessingError("pull request is nil", "NIL_RESOURCE", "")
+	}
+
+	var details []string
+	if pr.Head.Ref != "" && pr.Base.Ref != "" {
+		details = append(details, fmt.Sprintf("**分支:** `%s` → `%s`", pr.Head.Label, pr.Base.Label))
+	}
+
+	doc := p.render(&resource{
+		Type:      "pr",
+		Title:     pr.Title,
+		URL:       pr.HTMLURL,
+		Author:    pr.User,
+		CreatedAt: pr.CreatedAt,
+		UpdatedAt: pr.UpdatedAt,
+		Status:    pr.Status(),
+		Body:      pr.Body,
+		Reactions: pr.Reactions,
+		Details:   details,
+		Comments:  pr.Comments,
+		Events:    pr.Timeline,
+		Edits:     pr.Edits,
+		Projects:  pr.Projects,
+	})
+	doc.PullRequest = pr
+	return doc, nil
+}
+
+// ParseDiscussion 将Discussion渲染为Markdown文档
+// 被采纳的答案会带有 ✅ [Accepted Answer] 标识，回复嵌套在所属评论之下
+func (p *MarkdownParser) ParseDiscussion(discussion *model.Discussion) (*MarkdownDocument, error) {
+	if discussion == nil {
+		return nil, NewProcessingError("discussion is nil", "NIL_RESOURCE", "")
+	}
+
+	var details []string
+	if discussion.Category.Name != "" {
+		category := discussion.Category.Name
+		if p.options.EmojisEnabled && discussion.Category.Emoji != "" {
+			category = discussion.Category.Emoji + " " + category
+		}
+		details = append(details, "**分类:** "+category)
+	}
+
+	return p.render(&resource{
+		Type:      "discussion",
+		Title:     discussion.Title,
+		URL:       discussion.HTMLURL,
+		Author:    discussion.User,
+		CreatedAt: discussion.CreatedAt,
+		UpdatedAt: discussion.UpdatedAt,
+		Status:    discussion.Status(),
+		Body:      discussion.Body,
+		Reactions: discussion.Reactions,
+		Details:   details,
+		Comments:  discussion.Comments,
+	}), nil
+}
+
+// render 按统一结构渲染资源: frontmatter、标题、元信息、正文和评论
+func (p *MarkdownParser) render(res *resource) *MarkdownDocument {
+	if p.options.MinimizedComments == MinimizedDrop {
+		res.Comments = dropMinimized(res.Comments)
+	}
+	totalComments := countComments(res.Comments)
+	fields := []frontmatterField{
+		{Key: "title", Value: res.Title},
+		{Key: "url", Value: res.URL},
+		{Key: "author", Value: login(res.Author)},
+		{Key: "author_url", Value: userURL(res.Author)},
+		{Key: "created_at", Value: res.CreatedAt},
+		{Key: "updated_at", Value: res.UpdatedAt},
+		{Key: "status", Value: res.Status},
+		{Key: "type", Value: res.Type},
+	}
+	if p.options.EnableReactions {
+		var counts []frontmatterField
+		for _, rc := range reactionCounts(res.Reactions) {
+			counts = append(counts, frontmatterField{Key: rc.Key, Value: rc.Count})
+		}
+		fields = append(fields, frontmatterField{Key: "reaction_counts", Children: counts})
+	}
+	fields = append(fields, frontmatterField{Key: "total_comments", Value: totalComments})
+	if hasEdits(res.Edits, res.Comments) {
+		fields = append(fields, frontmatterField{Key: "edited", Value: true})
+	}
+	fields = append(fields, resolutionFields(res.Resolution)...)
+	projects := projectFields(res.Projects)
+	if len(projects) > 0 {
+		fields = append(fields, frontmatterField{Key: "projects", Children: projects})
+	}
+
+	metadata := make(map[string]string)
+	flattenFrontmatter(metadata, fields, "")
+
+	var b strings.Builder
+	if p.options.IncludeMetadata {
+		writeFrontmatter(&b, fields)
+		b.WriteString("\n")
+	}
+
+	fmt.Fprintf(&b, "# %s - %s\n\n", res.Title, displayStatus(res.Status))
+
+	if p.options.IncludeMetadata {
+		fmt.Fprintf(&b, "**作者:** %s\n", p.formatUser(res.Author))
+		if p.options.IncludeTimestamps {
+			fmt.Fprintf(&b, "**创建时间:** %s\n", formatDisplayTime(res.CreatedAt))
+			fmt.Fprintf(&b, "**最后更新:** %s\n", formatDisplayTime(res.UpdatedAt))
+		}
+		fmt.Fprintf(&b, "**状态:** %s\n", displayStatus(res.Status))
+		fmt.Fprintf(&b, "**评论数:** %d\n", totalComments)
+		if reactions := p.formatReactions(res.Reactions); p.options.EnableReactions && reactions != "" {
+			fmt.Fprintf(&b, "**Reactions:** %s\n", reactions)
+		}
+		for _, detail := range res.Details {
+			b.WriteString(detail + "\n")
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("## Description\n\n")
+	b.WriteString(formatBody(res.Body, "*No description provided.*"))
+	p.writeEdits(&b, res.Edits)
+
+	if len(res.SubIssues) > 0 {
+		fmt.Fprintf(&b, "\n## Sub-issues (%d/%d)\n\n", countClosed(res.SubIssues), len(res.SubIssues))
+		writeSubIssues(&b, res.SubIssues, 0)
+	}
+
+	if res.Resolution != nil {
+		b.WriteString("\n## Resolution\n\n")
+		p.writeResolution(&b, res.ClosedAt, res.Resolution)
+	}
+
+	if p.options.IncludeComments && (len(res.Comments) > 0 || len(res.Events) > 0) {
+		fmt.Fprintf(&b, "\n## Comments (%d)\n", totalComments)
+		p.writeTimeline(&b, res.Comments, res.Events)
+	}
+
+	return &MarkdownDocument{
+		Title:    res.Title,
+		Content:  b.String(),
+		Metadata: metadata,
+		Projects: projectValues(projects),
+	}
+}
+
+// writeComment 渲染单条评论及其回复，level为标题层级
+func (p *MarkdownParser) writeComment(b *strings.Builder, comment *model.Comment, level int) {
+	b.WriteString(strings.Repeat("#", level) + " ")
+	if comment.IsAnswer && p.options.EmojisEnabled {
+		b.WriteString("✅ ")
+	}
+	b.WriteString(p.formatUser(comment.User))
+	if p.options.IncludeTimestamps {
+		b.WriteString(" - " + formatDisplayTime(comment.CreatedAt))
+	}
+	if comment.IsAnswer {
+		b.WriteString(" [Accepted Answer]")
+	}
+	if comment.Minimized {
+		b.WriteString(" [Hidden")
+		if comment.MinimizedReason != "" {
+			b.WriteString(": " + comment.MinimizedReason)
+		}
+		b.WriteString("]")
+	}
+	b.WriteString("\n")
+	if comment.IsReviewComment() {
+		location := comment.Path
+		if comment.Line > 0 {
+			location = fmt.Sprintf("%s:%d", comment.Path, comment.Line)
+		}
+		fmt.Fprintf(b, "*on `%s`*\n\n", location)
+	}
+	if comment.Minimized && p.options.MinimizedComments != MinimizedInclude {
+		summary := "This comment was hidden"
+		if comment.MinimizedReason != "" {
+			summary += " as " + comment.MinimizedReason
+		}
+		fmt.Fprintf(b, "<details>\n<summary>%s</summary>\n\n%s\n</details>\n", summary, formatBody(comment.Body, "*No content.*"))
+	} else {
+		b.WriteString(formatBody(comment.Body, "*No content.*"))
+	}
+	p.writeEdits(b, comment.Edits)
+	if reactions := p.formatReactions(comment.Reactions); p.options.EnableReactions && reactions != "" {
+		fmt.Fprintf(b, "\n*Reactions: %s*\n", reactions)
+	}
+	b.WriteString("\n")
+
+	for _, reply := range comment.Replies {
+		b.WriteString("\n")
+		p.writeComment(b, reply, level+1)
+	}
+}
+
+// writeSubIssues 将子Issue渲染为任务清单，已关闭的子Issue勾选，depth为嵌套层级
+func writeSubIssues(b *strings.Builder, subIssues []*model.SubIssue, depth int) {
+	for _, sub := range subIssues {
+		check := " "
+		if sub.State == "closed" {
+			check = "x"
+		}
+		fmt.Fprintf(b, "%s- [%s] %s\n", strings.Repeat("  ", depth), check, formatSubIssue(&sub.IssueReference))
+		writeSubIssues(b, sub.Children, depth+1)
+	}
+}
+
+// formatSubIssue 格式化父子Issue引用，如 [owner/repo#12](12.md) Title · Closed
+// 标题和状态未知时省略
+func formatSubIssue(ref *model.IssueReference) string {
+	text := formatReference(ref)
+	if ref.Title != "" {
+		text += " " + ref.Title
+	}
+	if ref.State != "" {
+		text += " · " + displayStatus(ref.State)
+	}
+	return text
+}
+
+// countClosed 统计已关闭的子Issue数，不含更深层的子Issue
+func countClosed(subIssues []*model.SubIssue) int {
+	count := 0
+	for _, sub := range subIssues {
+		if sub.State == "closed" {
+			count++
+		}
+	}
+	return count
+}
+
+// writeResolution 渲染关闭Issue的Pull Request和提交
+func (p *MarkdownParser) writeResolution(b *strings.Builder, closedAt *time.Time, resolution *model.Resolution) {
+	if closedAt != nil && p.options.IncludeTimestamps {
+		fmt.Fprintf(b, "Closed on %s by:\n\n", formatDisplayTime(*closedAt))
+	} else {
+		b.WriteString("Closed by:\n\n")
+	}
+
+	for _, pr := range resolution.ClosedBy {
+		ref := &model.IssueReference{Repo: pr.Repo, Number: pr.Number, Title: pr.Title, State: pr.State, URL: pr.URL}
+		line := formatSubIssue(ref)
+		if pr.MergedAt != nil && p.options.IncludeTimestamps {
+			line += " " + formatDisplayTime(*pr.MergedAt)
+		}
+		fmt.Fprintf(b, "- %s\n", line)
+	}
+	if commit := resolution.Commit; commit != nil {
+		sha := commit.SHA
+		if len(sha) > 7 {
+			sha = sha[:7]
+		}
+		if commit.URL != "" {
+			sha = fmt.Sprintf("[%s](%s)", sha, commit.URL)
+		}
+		fmt.Fprintf(b, "- Commit %s %s\n", sha, commit.Message)
+	}
+}
+
+// resolutionFields 返回解决方式的frontmatter字段: closed_by列出关闭Issue的PR，closed_by_commit为关闭提交
+func resolutionFields(resolution *model.Resolution) []frontmatterField {
+	if resolution == nil {
+		return nil
+	}
+
+	var fields []frontmatterField
+	if len(resolution.ClosedBy) > 0 {
+		var items [][]frontmatterField
+		for _, pr := range resolution.ClosedBy {
+			var mergedAt interface{}
+			if pr.MergedAt != nil {
+				mergedAt = *pr.MergedAt
+			}
+			items = append(items, []frontmatterField{
+				{Key: "pr", Value: pr.Number},
+				{Key: "merged_at", Value: mergedAt},
+				{Key: "url", Value: pr.URL},
+			})
+		}
+		fields = append(fields, frontmatterField{Key: "closed_by", Items: items})
+	}
+	if resolution.Commit != nil {
+		fields = append(fields, frontmatterField{Key: "closed_by_commit", Value: resolution.Commit.SHA})
+	}
+	return fields
+}
+
+// projectFields 返回frontmatter中projects映射的字段: 项目标题到字段名和值的映射
+// 多个项目同名时在标题后加上项目编号区分；没有自定义字段值的项目输出为空映射
+func projectFields(items []*model.ProjectItem) []frontmatterField {
+	titles := make(map[string]int)
+	for _, item := range items {
+		titles[item.Project]++
+	}
+
+	var fields []frontmatterField
+	for _, item := range items {
+		key := item.Project
+		if titles[key] > 1 {
+			key = fmt.Sprintf("%s #%d", key, item.Number)
+		}
+		var values []frontmatterField
+		for _, field := range item.Fields {
+			values = append(values, frontmatterField{Key: field.Name, Value: field.Value})
+		}
+		if len(values) == 0 {
+			fields = append(fields, frontmatterField{Key: key, Value: emptyMap{}})
+			continue
+		}
+		fields = append(fields, frontmatterField{Key: key, Children: values})
+	}
+	return fields
+}
+
+// projectValues 将projects字段转换为文档的Projects映射
+func projectValues(fields []frontmatterField) map[string]map[string]interface{} {
+	if len(fields) == 0 {
+		return nil
+	}
+	projects := make(map[string]map[string]interface{}, len(fields))
+	for _, project := range fields {
+		values := make(map[string]interface{}, len(project.Children))
+		for _, field := range project.Children {
+			values[field.Key] = field.Value
+		}
+		projects[project.Key] = values
+	}
+	return projects
+}
+
+// reactionCount 单个reaction的统计
+type reactionCount struct {
+	Key   string
+	Emoji string
+	Count int
+}
+
+// reactionCounts 按spec中frontmatter的顺序返回各reaction的统计
+func reactionCounts(reactions model.Reactions) []reactionCount {
+	return []reactionCount{
+		{Key: "thumbs_up", Emoji: "👍", Count: reactions.ThumbsUp},
+		{Key: "thumbs_down", Emoji: "👎", Count: reactions.ThumbsDown},
+		{Key: "laugh", Emoji: "😄", Count: reactions.Laugh},
+		{Key: "hooray", Emoji: "🎉", Count: reactions.Hooray},
+		{Key: "confused", Emoji: "😕", Count: reactions.Confused},
+		{Key: "heart", Emoji: "❤️", Count: reactions.Heart},
+		{Key: "rocket", Emoji: "🚀", Count: reactions.Rocket},
+		{Key: "eyes", Emoji: "👀", Count: reactions.Eyes},
+	}
+}
+
+// formatReactions 将非零的reactions格式化为一行，如 "👍 5 · ❤️ 3"
+// 禁用emoji时使用键名代替表情
+func (p *MarkdownParser) formatReactions(reactions model.Reactions) string {
+	var parts []string
+	for _, rc := range reactionCounts(reactions) {
+		if rc.Count == 0 {
+			continue
+		}
+		label := rc.Key
+		if p.options.EmojisEnabled {
+			label = rc.Emoji
+		}
+		parts = append(parts, fmt.Sprintf("%s %d", label, rc.Count))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// formatUser 格式化用户名，启用用户链接时渲染为GitHub主页链接
+func (p *MarkdownParser) formatUser(user model.User) string {
+	name := "@" + login(user)
+	if p.options.IncludeUserLinks {
+		return fmt.Sprintf("[%s](%s)", name, userURL(user))
+	}
+	return name
+}
+
+// userURL 返回用户主页地址
+func userURL(user model.User) string {
+	if user.HTMLURL != "" {
+		return user.HTMLURL
+	}
+	return "https://github.com/" + login(user)
+}
+
+// ghostLogin 账号已删除的用户在GitHub上显示的用户名
+const ghostLogin = "ghost"
+
+// login 返回用户名，账号已删除（用户名为空）时返回ghost
+func login(user model.User) string {
+	if user.Login == "" {
+		return ghostLogin
+	}
+	return user.Login
+}
+
+// dropMinimized 返回去掉被折叠评论及回复后的评论列表，不修改原列表
+func dropMinimized(comments []*model.Comment) []*model.Comment {
+	var kept []*model.Comment
+	for _, comment := range comments {
+		if comment.Minimized {
+			continue
+		}
+		if len(comment.Replies) > 0 {
+			c := *comment
+			c.Replies = dropMinimized(comment.Replies)
+			comment = &c
+		}
+		kept = append(kept, comment)
+	}
+	return kept
+}
+
+// countComments 统计评论数，包括嵌套的回复
+func countComments(comments []*model.Comment) int {
+	count := 0
+	for _, comment := range comments {
+		count += 1 + countComments(comment.Replies)
+	}
+	return count
+}
+
+// formatBody 统一换行符并去除首尾空行，正文为空时输出占位文本
+func formatBody(body, placeholder string) string {
+	body = strings.ReplaceAll(body, "\r\n", "\n")
+	body = strings.Trim(body, "\n")
+	if strings.TrimSpace(body) == "" {
+		return placeholder + "\n"
+	}
+	return body + "\n"
+}
+
+// formatDisplayTime 以UTC格式输出正文中展示的时间
+func formatDisplayTime(t time.Time) string {
+	return t.UTC().Format(displayTimeFormat)
+}
+
+// displayStatus 将状态转换为首字母大写的展示形式
+func displayStatus(status string) string {
+	if status == "" {
+		return ""
+	}
+	return strings.ToUpper(status[:1]) + strings.ToLower(status[1:])
+}
@@ -0,0 +1,748 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bigwhite/issue2md/internal/model"
+)
+
+// newTestIssue 创建测试用的Issue
+func newTestIssue() *model.Issue {
+	return &model.Issue{
+		Number:    1,
+		Title:     "Add support for GitHub Discussions",
+		Body:      "It would be great to also support GitHub Discussions.",
+		State:     "open",
+		User:      model.User{Login: "johndoe", HTMLURL: "https://github.com/johndoe"},
+		CreatedAt: time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
+		UpdatedAt: time.Date(2024, 1, 2, 15, 30, 0, 0, time.UTC),
+		HTMLURL:   "https://github.com/bigwhite/issue2md/issues/1",
+	}
+}
+
+// newTestComments 创建测试用的评论列表
+func newTestComments() []*model.Comment {
+	return []*model.Comment{
+		{
+			ID:        1,
+			Body:      "Great idea!",
+			User:      model.User{Login: "alice"},
+			CreatedAt: time.Date(2024, 1, 1, 11, 0, 0, 0, time.UTC),
+		},
+		{
+			ID:        2,
+			Body:      "I agree.",
+			User:      model.User{Login: "bob"},
+			CreatedAt: time.Date(2024, 1, 1, 12, 30, 0, 0, time.UTC),
+		},
+	}
+}
+
+func TestMarkdownParserParse(t *testing.T) {
+	tests := []struct {
+		name        string
+		opts        *Options
+		issue       *model.Issue
+		comments    []*model.Comment
+		wantErr     bool
+		contains    []string
+		notContains []string
+	}{
+		{
+			name:    "Nil issue",
+			opts:    DefaultOptions(),
+			issue:   nil,
+			wantErr: true,
+		},
+		{
+			name:     "Default options",
+			opts:     DefaultOptions(),
+			issue:    newTestIssue(),
+			comments: newTestComments(),
+			contains: []string{
+				"---\ntitle: \"Add support for GitHub Discussions\"\n",
+				"status: \"open\"\n",
+				"type: \"issue\"\n",
+				"total_comments: 2\n",
+				"# Add support for GitHub Discussions - Open\n",
+				"**创建时间:** 2024-01-01 10:00:00 UTC\n",
+				"## Description\n\nIt would be great to also support GitHub Discussions.\n",
+				"## Comments (2)\n",
+				"### [@alice](https://github.com/alice) - 2024-01-01 11:00:00 UTC\nGreat idea!\n",
+			},
+		},
+		{
+			name: "Without metadata, timestamps and user links",
+			opts: &Options{
+				IncludeComments: true,
+			},
+			issue:    newTestIssue(),
+			comments: newTestComments(),
+			contains: []string{
+				"# Add support for GitHub Discussions - Open\n",
+				"### @bob\nI agree.\n",
+			},
+			notContains: []string{"---\n", "**作者:**", "UTC"},
+		},
+		{
+			name: "Without comments",
+			opts: &Options{
+				IncludeMetadata: true,
+			},
+			issue:       newTestIssue(),
+			comments:    newTestComments(),
+			contains:    []string{"total_comments: 2\n"},
+			notContains: []string{"## Comments", "Great idea!"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := NewParser(tt.opts)
+			doc, err := parser.Parse(tt.issue, tt.comments)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Error("Parse() expected error, but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse() unexpected error = %v", err)
+			}
+
+			for _, want := range tt.contains {
+				if !strings.Contains(doc.Content, want) {
+					t.Errorf("Parse() content missing %q\n%s", want, doc.Content)
+				}
+			}
+			for _, unwanted := range tt.notContains {
+				if strings.Contains(doc.Content, unwanted) {
+					t.Errorf("Parse() content unexpectedly contains %q\n%s", unwanted, doc.Content)
+				}
+			}
+
+			if doc.Title != tt.issue.Title {
+				t.Errorf("Parse().Title = %v, want %v", doc.Title, tt.issue.Title)
+			}
+			if doc.Metadata["author"] != "johndoe" || doc.Metadata["created_at"] != "2024-01-01T10:00:00Z" {
+				t.Errorf("Parse().Metadata = %v", doc.Metadata)
+			}
+		})
+	}
+}
+
+func TestMarkdownParserReactions(t *testing.T) {
+	issue := newTestIssue()
+	issue.Reactions = model.Reactions{TotalCount: 13, ThumbsUp: 8, Hooray: 3, Heart: 2}
+	comments := newTestComments()
+	comments[0].Reactions = model.Reactions{TotalCount: 1, Rocket: 1}
+
+	tests := []struct {
+		name        string
+		opts        *Options
+		contains    []string
+		notContains []string
+	}{
+		{
+			name: "Reactions disabled",
+			opts: DefaultOptions(),
+			notContains: []string{
+				"reaction_counts:",
+				"**Reactions:**",
+				"*Reactions:",
+			},
+		},
+		{
+			name: "Reactions enabled",
+			opts: &Options{IncludeComments: true, IncludeMetadata: true, EmojisEnabled: true, EnableReactions: true},
+			contains: []string{
+				"reaction_counts:\n  thumbs_up: 8\n  thumbs_down: 0\n  laugh: 0\n  hooray: 3\n  confused: 0\n  heart: 2\n  rocket: 0\n  eyes: 0\ntotal_comments: 2\n",
+				"**Reactions:** 👍 8 · 🎉 3 · ❤️ 2\n",
+				"Great idea!\n\n*Reactions: 🚀 1*\n",
+			},
+			notContains: []string{"I agree.\n\n*Reactions:"},
+		},
+		{
+			name: "Reactions enabled without emojis",
+			opts: &Options{IncludeComments: true, IncludeMetadata: true, EnableReactions: true},
+			contains: []string{
+				"**Reactions:** thumbs_up 8 · hooray 3 · heart 2\n",
+				"*Reactions: rocket 1*\n",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := NewParser(tt.opts).Parse(issue, comments)
+			if err != nil {
+				t.Fatalf("Parse() unexpected error = %v", err)
+			}
+
+			for _, want := range tt.contains {
+				if !strings.Contains(doc.Content, want) {
+					t.Errorf("Parse() content missing %q\n%s", want, doc.Content)
+				}
+			}
+			for _, unwanted := range tt.notContains {
+				if strings.Contains(doc.Content, unwanted) {
+					t.Errorf("Parse() content unexpectedly contains %q\n%s", unwanted, doc.Content)
+				}
+			}
+		})
+	}
+}
+
+func TestMarkdownParserTimeline(t *testing.T) {
+	issue := newTestIssue()
+	issue.Timeline = []*model.TimelineEvent{
+		{
+			Event:     model.EventLabeled,
+			Actor:     model.User{Login: "alice"},
+			CreatedAt: time.Date(2024, 1, 1, 10, 30, 0, 0, time.UTC),
+			Label:     "bug",
+		},
+		{
+			Event:     model.EventAssigned,
+			Actor:     model.User{Login: "alice"},
+			CreatedAt: time.Date(2024, 1, 1, 11, 0, 0, 0, time.UTC),
+			Assignee:  &model.User{Login: "bob"},
+		},
+		{
+			Event:       model.EventClosed,
+			Actor:       model.User{Login: "bob"},
+			CreatedAt:   time.Date(2024, 1, 3, 9, 0, 0, 0, time.UTC),
+			StateReason: "not_planned",
+		},
+	}
+
+	tests := []struct {
+		name        string
+		opts        *Options
+		comments    []*model.Comment
+		contains    []string
+		notContains []string
+	}{
+		{
+			name:     "Events interleaved with comments",
+			opts:     &Options{IncludeComments: true, IncludeTimestamps: true},
+			comments: newTestComments(),
+			contains: []string{
+				"## Comments (2)\n\n*@alice added label `bug` — 2024-01-01*\n\n### @alice - 2024-01-01 11:00:00 UTC\nGreat idea!\n\n\n*@alice assigned @bob — 2024-01-01*\n\n### @bob",
+				"I agree.\n\n\n*@bob closed this as not planned — 2024-01-03*\n",
+			},
+		},
+		{
+			name: "Events without comments",
+			opts: &Options{IncludeComments: true},
+			contains: []string{
+				"## Comments (0)\n\n*@alice added label `bug`*\n",
+			},
+			notContains: []string{"— 2024"},
+		},
+		{
+			name:        "Comments disabled",
+			opts:        &Options{},
+			comments:    newTestComments(),
+			notContains: []string{"## Comments", "added label"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := NewParser(tt.opts).Parse(issue, tt.comments)
+			if err != nil {
+				t.Fatalf("Parse() unexpected error = %v", err)
+			}
+
+			for _, want := range tt.contains {
+				if !strings.Contains(doc.Content, want) {
+					t.Errorf("Parse() content missing %q\n%s", want, doc.Content)
+				}
+			}
+			for _, unwanted := range tt.notContains {
+				if strings.Contains(doc.Content, unwanted) {
+					t.Errorf("Parse() content unexpectedly contains %q\n%s", unwanted, doc.Content)
+				}
+			}
+		})
+	}
+}
+
+func TestMarkdownParserSubIssues(t *testing.T) {
+	issue := newTestIssue()
+	issue.Parent = &model.IssueReference{Repo: "bigwhite/issue2md", Number: 10, Title: "Roadmap", State: "open", URL: "https://github.com/bigwhite/issue2md/issues/10"}
+	issue.SubIssues = []*model.SubIssue{
+		{
+			IssueReference: model.IssueReference{Repo: "bigwhite/issue2md", Number: 2, Title: "Schema", State: "closed", File: "2.md"},
+			Children: []*model.SubIssue{
+				{IssueReference: model.IssueReference{Repo: "bigwhite/issue2md", Number: 4, Title: "Draft", State: "open", File: "4.md"}},
+			},
+		},
+		{IssueReference: model.IssueReference{Repo: "other/repo", Number: 3, URL: "https://github.com/other/repo/issues/3"}},
+	}
+
+	doc, err := NewParser(&Options{IncludeMetadata: true}).Parse(issue, nil)
+	if err != nil {
+		t.Fatalf("Parse() unexpected error = %v", err)
+	}
+
+	for _, want := range []string{
+		"**父Issue:** [bigwhite/issue2md#10](https://github.com/bigwhite/issue2md/issues/10) Roadmap · Open\n",
+		"## Sub-issues (1/2)\n\n" +
+			"- [x] [bigwhite/issue2md#2](2.md) Schema · Closed\n" +
+			"  - [ ] [bigwhite/issue2md#4](4.md) Draft · Open\n" +
+			"- [ ] [other/repo#3](https://github.com/other/repo/issues/3)\n",
+	} {
+		if !strings.Contains(doc.Content, want) {
+			t.Errorf("Parse() content missing %q\n%s", want, doc.Content)
+		}
+	}
+}
+
+func TestMarkdownParserResolution(t *testing.T) {
+	closedAt := time.Date(2024, 1, 5, 10, 0, 0, 0, time.UTC)
+	mergedAt := time.Date(2024, 1, 5, 9, 58, 0, 0, time.UTC)
+	issue := newTestIssue()
+	issue.State = "closed"
+	issue.ClosedAt = &closedAt
+	issue.Resolution = &model.Resolution{
+		ClosedBy: []*model.ClosingPullRequest{
+			{Repo: "bigwhite/issue2md", Number: 10, Title: "Fix crash", URL: "https://github.com/bigwhite/issue2md/pull/10", State: "merged", MergedAt: &mergedAt},
+			{Repo: "bigwhite/issue2md", Number: 11, Title: "Alternative fix", URL: "https://github.com/bigwhite/issue2md/pull/11", State: "closed"},
+		},
+		Commit: &model.ClosingCommit{SHA: "abc1234def", Message: "Fix typo", URL: "https://github.com/bigwhite/issue2md/commit/abc1234def"},
+	}
+
+	doc, err := NewParser(&Options{IncludeMetadata: true, IncludeTimestamps: true}).Parse(issue, nil)
+	if err != nil {
+		t.Fatalf("Parse() unexpected error = %v", err)
+	}
+
+	wantClosedBy := `[{pr: 10, merged_at: "2024-01-05T09:58:00Z", url: "https://github.com/bigwhite/issue2md/pull/10"}, ` +
+		`{pr: 11, merged_at: null, url: "https://github.com/bigwhite/issue2md/pull/11"}]`
+	for _, want := range []string{
+		"closed_by: " + wantClosedBy + "\n",
+		"closed_by_commit: \"abc1234def\"\n",
+		"## Resolution\n\nClosed on 2024-01-05 10:00:00 UTC by:\n\n" +
+			"- [bigwhite/issue2md#10](https://github.com/bigwhite/issue2md/pull/10) Fix crash · Merged 2024-01-05 09:58:00 UTC\n" +
+			"- [bigwhite/issue2md#11](https://github.com/bigwhite/issue2md/pull/11) Alternative fix · Closed\n" +
+			"- Commit [abc1234](https://github.com/bigwhite/issue2md/commit/abc1234def) Fix typo\n",
+	} {
+		if !strings.Contains(doc.Content, want) {
+			t.Errorf("Parse() content missing %q\n%s", want, doc.Content)
+		}
+	}
+	if doc.Metadata["closed_by"] != wantClosedBy {
+		t.Errorf("Metadata[closed_by] = %q, want %q", doc.Metadata["closed_by"], wantClosedBy)
+	}
+}
+
+func TestMarkdownParserEdits(t *testing.T) {
+	issue := newTestIssue()
+	issue.Body = "Crash on startup\n\nSteps: run it"
+	issue.Edits = []*model.Edit{
+		{Editor: model.User{Login: "johndoe"}, EditedAt: time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC), Body: "Crash"},
+		{Editor: model.User{Login: "bob"}, EditedAt: time.Date(2024, 1, 2, 9, 0, 0, 0, time.UTC), Deleted: true},
+		{Editor: model.User{Login: "alice"}, EditedAt: time.Date(2024, 1, 3, 10, 0, 0, 0, time.UTC), Body: "Crash on startup\n\nSteps: run it"},
+	}
+	comments := newTestComments()
+	comments[0].Edits = []*model.Edit{
+		{Editor: model.User{Login: "alice"}, Body: "Great idea"},
+		{Editor: model.User{Login: "alice"}, Body: "```go\nx := 1\n```"},
+	}
+
+	tests := []struct {
+		name        string
+		issue       *model.Issue
+		comments    []*model.Comment
+		contains    []string
+		notContains []string
+	}{
+		{
+			name:     "Body and comment history",
+			issue:    issue,
+			comments: comments,
+			contains: []string{
+				"edited: true\n",
+				"Steps: run it\n\n<details>\n<summary>Edit history (2 edits)</summary>\n\n" +
+					"**@bob edited 2024-01-02 09:00:00 UTC** *(this revision was deleted)*\n\n" +
+					"**@alice edited 2024-01-03 10:00:00 UTC**\n\n```diff\n-Crash\n+Crash on startup\n+\n+Steps: run it\n```\n\n</details>\n",
+				"````diff\n-Great idea\n+```go\n+x := 1\n+```\n````\n",
+			},
+		},
+		{
+			name:        "No history",
+			issue:       newTestIssue(),
+			comments:    newTestComments(),
+			notContains: []string{"edited: true", "<details>"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := NewParser(&Options{IncludeComments: true, IncludeMetadata: true, IncludeTimestamps: true}).Parse(tt.issue, tt.comments)
+			if err != nil {
+				t.Fatalf("Parse() unexpected error = %v", err)
+			}
+			for _, want := range tt.contains {
+				if !strings.Contains(doc.Content, want) {
+					t.Errorf("Parse() content missing %q\n%s", want, doc.Content)
+				}
+			}
+			for _, unwanted := range tt.notContains {
+				if strings.Contains(doc.Content, unwanted) {
+					t.Errorf("Parse() content unexpectedly contains %q\n%s", unwanted, doc.Content)
+				}
+			}
+		})
+	}
+}
+
+func TestMarkdownParserMinimizedComments(t *testing.T) {
+	newComments := func() []*model.Comment {
+		comments := newTestComments()
+		comments[1].Minimized = true
+		comments[1].MinimizedReason = "off-topic"
+		comments[1].User = model.User{}
+		return comments
+	}
+
+	tests := []struct {
+		name        string
+		policy      string
+		contains    []string
+		notContains []string
+	}{
+		{
+			name:   "Collapse by default",
+			policy: "",
+			contains: []string{
+				"## Comments (2)\n",
+				"### @ghost [Hidden: off-topic]\n<details>\n<summary>This comment was hidden as off-topic</summary>\n\nI agree.\n\n</details>\n",
+			},
+		},
+		{
+			name:        "Include",
+			policy:      MinimizedInclude,
+			contains:    []string{"### @ghost [Hidden: off-topic]\nI agree.\n"},
+			notContains: []string{"<details>"},
+		},
+		{
+			name:        "Drop",
+			policy:      MinimizedDrop,
+			contains:    []string{"## Comments (1)\n", "Great idea!"},
+			notContains: []string{"I agree.", "@ghost"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := &Options{IncludeComments: true, MinimizedComments: tt.policy}
+			doc, err := NewParser(opts).Parse(newTestIssue(), newComments())
+			if err != nil {
+				t.Fatalf("Parse() unexpected error = %v", err)
+			}
+			for _, want := range tt.contains {
+				if !strings.Contains(doc.Content, want) {
+					t.Errorf("Parse() content missing %q\n%s", want, doc.Content)
+				}
+			}
+			for _, unwanted := range tt.notContains {
+				if strings.Contains(doc.Content, unwanted) {
+					t.Errorf("Parse() content unexpectedly contains %q\n%s", unwanted, doc.Content)
+				}
+			}
+		})
+	}
+}
+
+func TestMarkdownParserGhostAuthor(t *testing.T) {
+	issue := newTestIssue()
+	issue.User = model.User{}
+
+	doc, err := NewParser(&Options{IncludeMetadata: true, IncludeUserLinks: true}).Parse(issue, nil)
+	if err != nil {
+		t.Fatalf("Parse() unexpected error = %v", err)
+	}
+	if !strings.Contains(doc.Content, "[@ghost](https://github.com/ghost)") {
+		t.Errorf("Parse() content missing ghost author\n%s", doc.Content)
+	}
+	if doc.Metadata["author"] != "ghost" {
+		t.Errorf("Metadata[author] = %q, want ghost", doc.Metadata["author"])
+	}
+}
+
+func TestMarkdownParserProjects(t *testing.T) {
+	issue := newTestIssue()
+	issue.Projects = []*model.ProjectItem{
+		{Project: "Roadmap", Number: 3, Fields: []*model.ProjectField{
+			{Name: "Estimate", Value: 5.0},
+			{Name: "Status", Value: "In Progress"},
+			{Name: "Team: owner", Value: "Core"},
+		}},
+		{Project: "Triage", Number: 4},
+		{Project: "Triage", Number: 9, Fields: []*model.ProjectField{{Name: "Priority", Value: "P1"}}},
+	}
+
+	doc, err := NewParser(&Options{IncludeMetadata: true}).Parse(issue, nil)
+	if err != nil {
+		t.Fatalf("Parse() unexpected error = %v", err)
+	}
+
+	want := "projects:\n" +
+		"  Roadmap:\n" +
+		"    Estimate: 5\n" +
+		"    Status: \"In Progress\"\n" +
+		"    \"Team: owner\": \"Core\"\n" +
+		"  \"Triage #4\": {}\n" +
+		"  \"Triage #9\":\n" +
+		"    Priority: \"P1\"\n"
+	if !strings.Contains(doc.Content, want) {
+		t.Errorf("Parse() content missing %q\n%s", want, doc.Content)
+	}
+	if doc.Metadata["projects.Roadmap.Status"] != "In Progress" {
+		t.Errorf("Metadata[projects.Roadmap.Status] = %q, want In Progress", doc.Metadata["projects.Roadmap.Status"])
+	}
+	if got := doc.Projects["Roadmap"]["Estimate"]; got != 5.0 {
+		t.Errorf("Projects[Roadmap][Estimate] = %v, want 5", got)
+	}
+	if got, ok := doc.Projects["Triage #4"]; !ok || len(got) != 0 {
+		t.Errorf("Projects[Triage #4] = %v, want empty map", got)
+	}
+}
+
+func TestLineDiff(t *testing.T) {
+	tests := []struct {
+		name   string
+		before string
+		after  string
+		want   []string
+	}{
+		{
+			name:   "Changed line keeps nearby context",
+			before: "a\nb\nc\nd\ne\nf\ng",
+			after:  "a\nb\nc\nd\nE\nf\ng",
+			want:   []string{" c", " d", "-e", "+E", " f", " g"},
+		},
+		{
+			name:   "Distant changes separated",
+			before: "1\n2\n3\n4\n5\n6\n7\n8",
+			after:  "0\n2\n3\n4\n5\n6\n7\n9",
+			want:   []string{"-1", "+0", " 2", " 3", "@@", " 6", " 7", "-8", "+9"},
+		},
+		{
+			name:   "Appended lines",
+			before: "a",
+			after:  "a\r\nb",
+			want:   []string{" a", "+b"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := lineDiff(tt.before, tt.after); strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("lineDiff() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDescribeTimelineEvent(t *testing.T) {
+	alice := model.User{Login: "alice"}
+	ref := &model.IssueReference{Type: "issue", Repo: "owner/repo", Number: 12, URL: "https://github.com/owner/repo/issues/12"}
+
+	tests := []struct {
+		name  string
+		event *model.TimelineEvent
+		want  string
+	}{
+		{name: "Unlabeled", event: &model.TimelineEvent{Event: model.EventUnlabeled, Label: "wontfix"}, want: "removed label `wontfix`"},
+		{name: "Self-assigned", event: &model.TimelineEvent{Event: model.EventAssigned, Actor: alice, Assignee: &alice}, want: "self-assigned this"},
+		{name: "Unassigned", event: &model.TimelineEvent{Event: model.EventUnassigned, Actor: alice, Assignee: &model.User{Login: "bob"}}, want: "unassigned @bob"},
+		{name: "Milestoned", event: &model.TimelineEvent{Event: model.EventMilestoned, Milestone: "v1.0"}, want: "added this to the `v1.0` milestone"},
+		{name: "Closed without reason", event: &model.TimelineEvent{Event: model.EventClosed}, want: "closed this"},
+		{name: "Closed as completed", event: &model.TimelineEvent{Event: model.EventClosed, StateReason: "completed"}, want: "closed this as completed"},
+		{name: "Reopened", event: &model.TimelineEvent{Event: model.EventReopened}, want: "reopened this"},
+		{name: "Renamed", event: &model.TimelineEvent{Event: model.EventRenamed, RenameFrom: "Crash", RenameTo: "Crash on startup"}, want: `changed the title from "Crash" to "Crash on startup"`},
+		{name: "Cross-referenced", event: &model.TimelineEvent{Event: model.EventCrossReferenced, Source: ref}, want: "mentioned this in [owner/repo#12](https://github.com/owner/repo/issues/12)"},
+		{name: "Transferred", event: &model.TimelineEvent{Event: model.EventTransferred, FromRepo: "owner/old"}, want: "transferred this issue from owner/old"},
+		{name: "Marked as duplicate", event: &model.TimelineEvent{Event: model.EventMarkedAsDuplicate, Source: ref}, want: "marked this as a duplicate of [owner/repo#12](https://github.com/owner/repo/issues/12)"},
+		{name: "Unknown event", event: &model.TimelineEvent{Event: "subscribed"}, want: ""},
+	}
+
+	p := NewParser(&Options{})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := describeTimelineEvent(tt.event, p.formatUser); got != tt.want {
+				t.Errorf("describeTimelineEvent() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarkdownParserParsePullRequest(t *testing.T) {
+	pr := &model.PullRequest{
+		Issue:  *newTestIssue(),
+		Merged: true,
+		Head:   model.Branch{Label: "alice:feature", Ref: "feature"},
+		Base:   model.Branch{Label: "bigwhite:main", Ref: "main"},
+		Comments: []*model.Comment{
+			{
+				ID:        1,
+				Body:      "Nit: rename this.",
+				User:      model.User{Login: "bob"},
+				CreatedAt: time.Date(2024, 1, 1, 11, 0, 0, 0, time.UTC),
+				Path:      "internal/github/client.go",
+				Line:      42,
+			},
+		},
+	}
+
+	doc, err := NewParser(&Options{IncludeComments: true, IncludeMetadata: true}).ParsePullRequest(pr)
+	if err != nil {
+		t.Fatalf("ParsePullRequest() unexpected error = %v", err)
+	}
+
+	for _, want := range []string{
+		"type: \"pr\"\n",
+		"status: \"merged\"\n",
+		"# Add support for GitHub Discussions - Merged\n",
+		"**分支:** `alice:feature` → `bigwhite:main`\n",
+		"### @bob\n*on `internal/github/client.go:42`*\n\nNit: rename this.\n",
+	} {
+		if !strings.Contains(doc.Content, want) {
+			t.Errorf("ParsePullRequest() content missing %q\n%s", want, doc.Content)
+		}
+	}
+
+	if _, err := NewParser(nil).ParsePullRequest(nil); err == nil {
+		t.Error("ParsePullRequest(nil) expected error, but got nil")
+	}
+}
+
+func TestMarkdownParserParseDiscussion(t *testing.T) {
+	answer := &model.Comment{
+		ID:        3,
+		Body:      "Thanks for the feedback!",
+		User:      model.User{Login: "johndoe"},
+		CreatedAt: time.Date(2024, 1, 2, 15, 30, 0, 0, time.UTC),
+		IsAnswer:  true,
+	}
+	comments := newTestComments()
+	comments[0].Replies = []*model.Comment{
+		{
+			ID:        4,
+			Body:      "Me too.",
+			User:      model.User{Login: "carol"},
+			CreatedAt: time.Date(2024, 1, 1, 11, 30, 0, 0, time.UTC),
+		},
+	}
+	discussion := &model.Discussion{
+		Number:    123,
+		Title:     "How to export?",
+		Body:      "Question body",
+		User:      model.User{Login: "johndoe"},
+		Category:  model.DiscussionCategory{Name: "Q&A", Emoji: ":pray:"},
+		Answered:  true,
+		Answer:    answer,
+		CreatedAt: time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
+		UpdatedAt: time.Date(2024, 1, 2, 15, 30, 0, 0, time.UTC),
+		Comments:  append(comments, answer),
+	}
+
+	tests := []struct {
+		name        string
+		opts        *Options
+		discussion  *model.Discussion
+		wantErr     bool
+		contains    []string
+		notContains []string
+	}{
+		{
+			name:       "Nil discussion",
+			opts:       DefaultOptions(),
+			discussion: nil,
+			wantErr:    true,
+		},
+		{
+			name:       "Accepted answer with emojis",
+			opts:       &Options{IncludeComments: true, IncludeMetadata: true, IncludeTimestamps: true, EmojisEnabled: true},
+			discussion: discussion,
+			contains: []string{
+				"type: \"discussion\"\n",
+				"status: \"answered\"\n",
+				"total_comments: 4\n",
+				"# How to export? - Answered\n",
+				"**分类:** :pray: Q&A\n",
+				"### ✅ @johndoe - 2024-01-02 15:30:00 UTC [Accepted Answer]\nThanks for the feedback!\n",
+				"#### @carol - 2024-01-01 11:30:00 UTC\nMe too.\n",
+			},
+		},
+		{
+			name:       "Accepted answer without emojis",
+			opts:       &Options{IncludeComments: true, IncludeMetadata: true},
+			discussion: discussion,
+			contains: []string{
+				"**分类:** Q&A\n",
+				"### @johndoe [Accepted Answer]\n",
+			},
+			notContains: []string{"✅", ":pray:"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := NewParser(tt.opts)
+			doc, err := parser.ParseDiscussion(tt.discussion)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Error("ParseDiscussion() expected error, but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseDiscussion() unexpected error = %v", err)
+			}
+
+			for _, want := range tt.contains {
+				if !strings.Contains(doc.Content, want) {
+					t.Errorf("ParseDiscussion() content missing %q\n%s", want, doc.Content)
+				}
+			}
+			for _, unwanted := range tt.notContains {
+				if strings.Contains(doc.Content, unwanted) {
+					t.Errorf("ParseDiscussion() content unexpectedly contains %q\n%s", unwanted, doc.Content)
+				}
+			}
+		})
+	}
+}
+
+func TestFrontmatter(t *testing.T) {
+	fields := []frontmatterField{
+		{Key: "title", Value: `Say "hi"`},
+		{Key: "created_at", Value: time.Date(2024, 1, 1, 10, 0, 0, 0, time.FixedZone("CST", 8*3600))},
+		{Key: "total_comments", Value: 3},
+		{Key: "nested", Children: []frontmatterField{
+			{Key: "flag", Value: true},
+		}},
+	}
+
+	var b strings.Builder
+	writeFrontmatter(&b, fields)
+	want := "---\ntitle: \"Say \\\"hi\\\"\"\ncreated_at: \"2024-01-01T02:00:00Z\"\ntotal_comments: 3\nnested:\n  flag: true\n---\n"
+	if b.String() != want {
+		t.Errorf("writeFrontmatter() = %q, want %q", b.String(), want)
+	}
+
+	metadata := make(map[string]string)
+	flattenFrontmatter(metadata, fields, "")
+	if metadata["nested.flag"] != "true" || metadata["title"] != `Say "hi"` || metadata["created_at"] != "2024-01-01T02:00:00Z" {
+		t.Errorf("flattenFrontmatter() = %v", metadata)
+	}
+}
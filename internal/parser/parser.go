@@ -2,13 +2,18 @@ package parser
 
 import (
 	"fmt"
+	"net/url"
 	"strconv"
 	"strings"
 )
 
+// DefaultHost 公共GitHub的主机名
+const DefaultHost = "github.com"
+
 // ResourceURL 表示解析后的GitHub资源URL
 type ResourceURL struct {
-	Type   string // "issue", "pull", "discussion"
+	Type   string // "issue", "pull", "discussion", "repository", "epic"（GitLab）
+	Host   string // github.com、GitHub Enterprise Server、GitLab 或 Gitea 主机名
 	Owner  string
 	Repo   string
 	Number int
@@ -20,6 +25,9 @@ type URLParser interface {
 	// Parse 解析GitHub URL
 	Parse(rawURL string) (*ResourceURL, error)
 
+	// ParseRepository 解析仓库URL，如 https://github.com/owner/repo 或 .../owner/repo/issues
+	ParseRepository(rawURL string) (*ResourceURL, error)
+
 	// Validate 验证URL格式
 	Validate(rawURL string) error
 
@@ -28,11 +36,18 @@ type URLParser interface {
 }
 
 // GitHubURLParser GitHub URL解析器实现
-type GitHubURLParser struct{}
+type GitHubURLParser struct {
+	hosts map[string]bool
+}
 
 // NewURLParser 创建新的URL解析器
-func NewURLParser() *GitHubURLParser {
-	return &GitHubURLParser{}
+// 除github.com外，还接受enterpriseHosts中的GitHub Enterprise Server主机
+func NewURLParser(enterpriseHosts ...string) *GitHubURLParser {
+	hosts := map[string]bool{DefaultHost: true}
+	for _, host := range enterpriseHosts {
+		hosts[strings.ToLower(host)] = true
+	}
+	return &GitHubURLParser{hosts: hosts}
 }
 
 // Parse 解析GitHub URL
@@ -43,12 +58,13 @@ func (p *GitHubURLParser) Parse(rawURL string) (*ResourceURL, error) {
 	}
 
 	// 检查是否为有效的GitHub URL
-	if !strings.HasPrefix(rawURL, "https://github.com/") {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme != "https" || !p.hosts[strings.ToLower(u.Host)] {
 		return nil, fmt.Errorf("invalid URL: %w", fmt.Errorf("not a GitHub URL"))
 	}
 
 	// 分割和验证路径
-	owner, repo, resourceType, numberStr, err := p.splitAndValidatePath(rawURL)
+	owner, repo, resourceType, numberStr, err := p.splitAndValidatePath(strings.TrimPrefix(u.Path, "/"))
 	if err != nil {
 		return nil, fmt.Errorf("invalid URL: %w", err)
 	}
@@ -68,6 +84,7 @@ func (p *GitHubURLParser) Parse(rawURL string) (*ResourceURL, error) {
 	// 构造返回结果
 	return &ResourceURL{
 		Type:   resultType,
+		Host:   strings.ToLower(u.Host),
 		Owner:  owner,
 		Repo:   repo,
 		Number: number,
@@ -75,17 +92,43 @@ func (p *GitHubURLParser) Parse(rawURL string) (*ResourceURL, error) {
 	}, nil
 }
 
+// ParseRepository 解析仓库URL
+// 接受仓库主页和Issue列表页，返回的ResourceURL类型为"repository"，Number为0
+func (p *GitHubURLParser) ParseRepository(rawURL string) (*ResourceURL, error) {
+	if rawURL == "" {
+		return nil, fmt.Errorf("empty URL")
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme != "https" || !p.hosts[strings.ToLower(u.Host)] {
+		return nil, fmt.Errorf("invalid URL: %w", fmt.Errorf("not a GitHub URL"))
+	}
+
+	pathParts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(pathParts) == 3 && pathParts[2] == "issues" {
+		pathParts = pathParts[:2]
+	}
+	if len(pathParts) != 2 || pathParts[0] == "" || pathParts[1] == "" {
+		return nil, fmt.Errorf("invalid URL: not a repository URL")
+	}
+
+	return &ResourceURL{
+		Type:  "repository",
+		Host:  strings.ToLower(u.Host),
+		Owner: pathParts[0],
+		Repo:  pathParts[1],
+		URL:   rawURL,
+	}, nil
+}
+
 // Validate 验证URL格式
 func (p *GitHubURLParser) Validate(rawURL string) error {
 	_, err := p.Parse(rawURL)
 	return err
 }
 
-// splitAndValidatePath 分割URL路径并进行基础验证
-func (p *GitHubURLParser) splitAndValidatePath(rawURL string) (owner, repo, resourceType, numberStr string, err error) {
-	// 移除协议前缀进行路径解析
-	path := strings.TrimPrefix(rawURL, "https://github.com/")
-
+// splitAndValidatePath 分割URL路径（不含开头的/）并进行基础验证
+func (p *GitHubURLParser) splitAndValidatePath(path string) (owner, repo, resourceType, numberStr string, err error) {
 	// 分割路径部分
 	pathParts := strings.Split(path, "/")
 	if len(pathParts) < 3 {
@@ -172,6 +172,122 @@ func TestParse(t *testing.T) {
 	}
 }
 
+func TestParseEnterpriseHosts(t *testing.T) {
+	parser := NewURLParser("git.corp.example")
+
+	tests := []struct {
+		name      string
+		rawURL    string
+		wantHost  string
+		wantError bool
+	}{
+		{
+			name:     "Public GitHub still accepted",
+			rawURL:   "https://github.com/facebook/react/issues/12345",
+			wantHost: "github.com",
+		},
+		{
+			name:     "Enterprise host",
+			rawURL:   "https://git.corp.example/platform/infra/pull/42",
+			wantHost: "git.corp.example",
+		},
+		{
+			name:     "Enterprise host is case-insensitive",
+			rawURL:   "https://Git.Corp.Example/platform/infra/issues/7",
+			wantHost: "git.corp.example",
+		},
+		{
+			name:     "Comment anchor ignored",
+			rawURL:   "https://git.corp.example/platform/infra/issues/7#issuecomment-1",
+			wantHost: "git.corp.example",
+		},
+		{
+			name:      "Unconfigured host",
+			rawURL:    "https://git.other.example/platform/infra/issues/7",
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parser.Parse(tt.rawURL)
+			if tt.wantError {
+				if err == nil {
+					t.Errorf("Parse() expected error, but got %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse() unexpected error = %v", err)
+			}
+			if got.Host != tt.wantHost {
+				t.Errorf("Parse().Host = %v, want %v", got.Host, tt.wantHost)
+			}
+		})
+	}
+}
+
+func TestParseRepository(t *testing.T) {
+	parser := NewURLParser("git.corp.example")
+
+	tests := []struct {
+		name      string
+		rawURL    string
+		wantHost  string
+		wantOwner string
+		wantRepo  string
+		wantError bool
+	}{
+		{
+			name:      "Repository homepage",
+			rawURL:    "https://github.com/facebook/react",
+			wantHost:  "github.com",
+			wantOwner: "facebook",
+			wantRepo:  "react",
+		},
+		{
+			name:      "Issue list with trailing slash",
+			rawURL:    "https://git.corp.example/platform/infra/issues/",
+			wantHost:  "git.corp.example",
+			wantOwner: "platform",
+			wantRepo:  "infra",
+		},
+		{
+			name:      "Single issue is not a repository",
+			rawURL:    "https://github.com/facebook/react/issues/12345",
+			wantError: true,
+		},
+		{
+			name:      "Owner only",
+			rawURL:    "https://github.com/facebook",
+			wantError: true,
+		},
+		{
+			name:      "Not GitHub",
+			rawURL:    "https://gitlab.com/facebook/react",
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parser.ParseRepository(tt.rawURL)
+			if tt.wantError {
+				if err == nil {
+					t.Errorf("ParseRepository() expected error, but got %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseRepository() unexpected error = %v", err)
+			}
+			if got.Type != "repository" || got.Host != tt.wantHost || got.Owner != tt.wantOwner || got.Repo != tt.wantRepo || got.Number != 0 {
+				t.Errorf("ParseRepository() = %+v", got)
+			}
+		})
+	}
+}
+
 func TestValidate(t *testing.T) {
 	parser := NewURLParser()
 
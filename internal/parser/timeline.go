@@ -0,0 +1,115 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bigwhite/issue2md/internal/model"
+)
+
+// eventDateFormat 时间线事件中展示日期的格式
+const eventDateFormat = "2006-01-02"
+
+// writeTimeline 按时间顺序交错渲染评论和时间线事件
+// 两者均已按时间排序，时间相同时评论在前
+func (p *MarkdownParser) writeTimeline(b *strings.Builder, comments []*model.Comment, events []*model.TimelineEvent) {
+	i, j := 0, 0
+	for i < len(comments) || j < len(events) {
+		if j < len(events) && (i == len(comments) || events[j].CreatedAt.Before(comments[i].CreatedAt)) {
+			if line := p.formatTimelineEvent(events[j]); line != "" {
+				b.WriteString("\n" + line + "\n")
+			}
+			j++
+			continue
+		}
+		b.WriteString("\n")
+		p.writeComment(b, comments[i], 3)
+		i++
+	}
+}
+
+// formatTimelineEvent 将时间线事件格式化为一行，如 "*@alice added label `bug` — 2024-01-03*"
+// 无法描述的事件返回空字符串
+func (p *MarkdownParser) formatTimelineEvent(event *model.TimelineEvent) string {
+	action := describeTimelineEvent(event, p.formatUser)
+	if action == "" {
+		return ""
+	}
+
+	line := p.formatUser(event.Actor) + " " + action
+	if p.options.IncludeTimestamps {
+		line += " — " + event.CreatedAt.UTC().Format(eventDateFormat)
+	}
+	return "*" + line + "*"
+}
+
+// describeTimelineEvent 描述事件的动作部分（不含执行者和时间）
+func describeTimelineEvent(event *model.TimelineEvent, formatUser func(model.User) string) string {
+	switch event.Event {
+	case model.EventLabeled:
+		return fmt.Sprintf("added label `%s`", event.Label)
+	case model.EventUnlabeled:
+		return fmt.Sprintf("removed label `%s`", event.Label)
+	case model.EventAssigned, model.EventUnassigned:
+		verb := "assigned"
+		if event.Event == model.EventUnassigned {
+			verb = "unassigned"
+		}
+		if event.Assignee == nil {
+			return verb + " this"
+		}
+		if event.Assignee.Login == event.Actor.Login {
+			if event.Event == model.EventAssigned {
+				return "self-assigned this"
+			}
+			return "removed their assignment"
+		}
+		return verb + " " + formatUser(*event.Assignee)
+	case model.EventMilestoned:
+		return fmt.Sprintf("added this to the `%s` milestone", event.Milestone)
+	case model.EventDemilestoned:
+		return fmt.Sprintf("removed this from the `%s` milestone", event.Milestone)
+	case model.EventClosed:
+		if event.StateReason == "" {
+			return "closed this"
+		}
+		return "closed this as " + strings.ReplaceAll(event.StateReason, "_", " ")
+	case model.EventReopened:
+		return "reopened this"
+	case model.EventRenamed:
+		return fmt.Sprintf("changed the title from %q to %q", event.RenameFrom, event.RenameTo)
+	case model.EventCrossReferenced:
+		if event.Source == nil {
+			return "mentioned this"
+		}
+		return "mentioned this in " + formatReference(event.Source)
+	case model.EventTransferred:
+		if event.FromRepo == "" {
+			return "transferred this issue"
+		}
+		return "transferred this issue from " + event.FromRepo
+	case model.EventMarkedAsDuplicate:
+		if event.Source == nil {
+			return "marked this as a duplicate"
+		}
+		return "marked this as a duplicate of " + formatReference(event.Source)
+	case model.EventUnmarkedAsDuplicate:
+		return "unmarked this as a duplicate"
+	default:
+		return ""
+	}
+}
+
+// formatReference 将Issue/PR引用格式化为链接，如 [owner/repo#12](url)
+// 引用带有导出文件时链接到该文件
+func formatReference(ref *model.IssueReference) string {
+	text := fmt.Sprintf("%s#%d", ref.Repo, ref.Number)
+	target := ref.URL
+	if ref.File != "" {
+		target = ref.File
+	}
+	if target == "" {
+		return text
+	}
+	return fmt.Sprintf("[%s](%s)", text, target)
+}
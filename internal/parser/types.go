@@ -1,12 +1,14 @@
 package parser
 
 import (
-	"github.com/bigwhite/issue2md/internal/github"
+	"github.com/bigwhite/issue2md/internal/model"
 )
 
 // Parser 定义Markdown解析器接口
 type Parser interface {
-	Parse(issue *github.Issue, comments []*github.Comment) (*MarkdownDocument, error)
+	Parse(issue *model.Issue, comments []*model.Comment) (*MarkdownDocument, error)
+	ParsePullRequest(pr *model.PullRequest) (*MarkdownDocument, error)
+	ParseDiscussion(discussion *model.Discussion) (*MarkdownDocument, error)
 }
 
 // MarkdownParser Markdown解析器实现
@@ -22,8 +24,18 @@ type Options struct {
 	IncludeUserLinks   bool `json:"include_user_links"`
 	EmojisEnabled      bool `json:"emojis_enabled"`
 	PreserveLineBreaks bool `json:"preserve_line_breaks"`
+	EnableReactions    bool `json:"enable_reactions"`
+	// MinimizedComments 被版主折叠的评论的处理方式，取值见Minimized*常量，为空时按MinimizedCollapse处理
+	MinimizedComments string `json:"minimized_comments"`
 }
 
+// 被折叠评论的处理方式
+const (
+	MinimizedInclude  = "include"  // 与普通评论一样展示正文，标题中标注折叠原因
+	MinimizedCollapse = "collapse" // 正文收进<details>
+	MinimizedDrop     = "drop"     // 不展示，也不计入评论数
+)
+
 // DefaultOptions 返回默认解析器选项
 func DefaultOptions() *Options {
 	return &Options{
@@ -33,6 +45,7 @@ func DefaultOptions() *Options {
 		IncludeUserLinks:   true,
 		EmojisEnabled:      true,
 		PreserveLineBreaks: true,
+		MinimizedComments:  MinimizedCollapse,
 	}
 }
 
@@ -51,6 +64,12 @@ type MarkdownDocument struct {
 	Title    string            `json:"title"`
 	Content  string            `json:"content"`
 	Metadata map[string]string `json:"metadata"`
+	// Projects 所属项目标题到自定义字段值的映射，与frontmatter中的projects一致
+	Projects map[string]map[string]interface{} `json:"projects,omitempty"`
+	// Issue、Comments和PullRequest 渲染文档所用的数据，JSON输出一并写出，render子命令据此重新渲染；Discussion文档为空
+	Issue       *model.Issue       `json:"issue,omitempty"`
+	Comments    []*model.Comment   `json:"comments,omitempty"`
+	PullRequest *model.PullRequest `json:"pull_request,omitempty"`
 }
 
 // IssueMetadata Issue元数据
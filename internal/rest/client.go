@@ -0,0 +1,165 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/bigwhite/issue2md/internal/model"
+	"github.com/bigwhite/issue2md/internal/transport"
+)
+
+// Client GitLab、Gitea等平台REST接口的JSON客户端
+// 负责认证、错误响应和附件下载，各平台在此之上实现接口路径、分页和数据转换
+type Client struct {
+	// BaseURL REST接口地址，如 https://gitlab.example.com/api/v4/
+	BaseURL *url.URL
+	// Retrier 对瞬时故障重试的传输层，可通过Policy调整重试策略
+	Retrier *transport.RetryTransport
+	// Cache 磁盘缓存层，设置Dir后启用
+	Cache *transport.CacheTransport
+
+	httpClient    *http.Client
+	authorization string
+}
+
+// NewClient 创建REST客户端
+// baseURL只给出主机地址（如 https://git.example.com）时自动补全apiPath（如 api/v4/）；
+// httpClient的Transport会依次被包装为RetryTransport和CacheTransport（默认禁用）；
+// authorization为发往接口主机的Authorization头，如 "Bearer <token>"，为空时不认证
+func NewClient(httpClient *http.Client, baseURL, apiPath, authorization string) (*Client, error) {
+	base, err := url.Parse(baseURL)
+	if err != nil || base.Host == "" {
+		return nil, fmt.Errorf("invalid base URL %q", baseURL)
+	}
+	if !strings.HasSuffix(base.Path, "/") {
+		base.Path += "/"
+	}
+	if !strings.HasSuffix(base.Path, "/"+apiPath) {
+		base.Path += apiPath
+	}
+
+	if httpClient == nil {
+		httpClient = &http.Client{}
+	}
+	retrier := transport.NewRetryTransport(httpClient.Transport)
+	cache := transport.NewCacheTransport(retrier, "", transport.CacheRevalidate)
+	wrapped := *httpClient
+	wrapped.Transport = cache
+
+	return &Client{
+		BaseURL:       base,
+		Retrier:       retrier,
+		Cache:         cache,
+		httpClient:    &wrapped,
+		authorization: authorization,
+	}, nil
+}
+
+// Error 接口返回的错误响应
+type Error struct {
+	StatusCode int
+	Message    string
+	URL        string
+}
+
+// Error 实现error接口
+func (e *Error) Error() string {
+	return fmt.Sprintf("GET %s: %d %s", e.URL, e.StatusCode, e.Message)
+}
+
+// Get 请求接口并将JSON响应解码到out，返回响应头供调用方判断分页
+// path相对BaseURL，不以/开头；204响应视为空结果，out保持不变
+func (c *Client) Get(ctx context.Context, path string, query url.Values, out interface{}) (http.Header, error) {
+	u, err := c.BaseURL.Parse(path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid API path %q: %w", path, err)
+	}
+	if len(query) > 0 {
+		u.RawQuery = query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.authorization != "" {
+		req.Header.Set("Authorization", c.authorization)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNoContent:
+		return resp.Header, nil
+	default:
+		return nil, &Error{StatusCode: resp.StatusCode, Message: errorMessage(resp), URL: u.String()}
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return nil, fmt.Errorf("failed to decode response from %s: %w", u, err)
+	}
+	return resp.Header, nil
+}
+
+// errorMessage 从错误响应中提取错误信息
+// GitLab的message可能是字符串或按字段分组的对象，OAuth错误使用error字段；Gitea使用message字段
+func errorMessage(resp *http.Response) string {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	var apiErr struct {
+		Message interface{} `json:"message"`
+		Error   string      `json:"error"`
+	}
+	if json.Unmarshal(body, &apiErr) == nil {
+		if apiErr.Message != nil && apiErr.Message != "" {
+			return fmt.Sprint(apiErr.Message)
+		}
+		if apiErr.Error != "" {
+			return apiErr.Error
+		}
+	}
+	return http.StatusText(resp.StatusCode)
+}
+
+// DownloadAsset 下载正文或评论中引用的附件
+// 只有发往接口所在主机的请求附带令牌，重定向到其他主机时不再附带令牌
+func (c *Client) DownloadAsset(ctx context.Context, rawURL string) (*model.Asset, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid asset URL %q: %w", rawURL, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create asset request: %w", err)
+	}
+	if c.authorization != "" && strings.EqualFold(u.Host, c.BaseURL.Host) {
+		req.Header.Set("Authorization", c.authorization)
+	}
+
+	// 标准库跟随重定向到其他主机时会去掉Authorization头
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download asset %s: %w", rawURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download asset %s: %s", rawURL, resp.Status)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download asset %s: %w", rawURL, err)
+	}
+	return &model.Asset{Data: data, ContentType: resp.Header.Get("Content-Type")}, nil
+}
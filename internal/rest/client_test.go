@@ -0,0 +1,125 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newTestClient 创建访问测试服务器的客户端，禁用重试
+func newTestClient(t *testing.T, server *httptest.Server) *Client {
+	t.Helper()
+	client, err := NewClient(server.Client(), server.URL, "api/v4/", "Bearer test-token")
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	client.Retrier.Policy.MaxAttempts = 1
+	return client
+}
+
+func TestNewClient(t *testing.T) {
+	tests := []struct {
+		name    string
+		baseURL string
+		want    string
+		wantErr bool
+	}{
+		{name: "host only", baseURL: "https://git.example.com", want: "https://git.example.com/api/v4/"},
+		{name: "full API URL", baseURL: "https://git.example.com/api/v4", want: "https://git.example.com/api/v4/"},
+		{name: "sub path", baseURL: "https://example.com/gitlab/", want: "https://example.com/gitlab/api/v4/"},
+		{name: "missing host", baseURL: "gitlab", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewClient(nil, tt.baseURL, "api/v4/", "")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewClient() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && client.BaseURL.String() != tt.want {
+				t.Errorf("BaseURL = %q, want %q", client.BaseURL, tt.want)
+			}
+		})
+	}
+}
+
+func TestClientGet(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		body        string
+		want        string
+		wantMessage string
+	}{
+		{name: "OK", status: http.StatusOK, body: `{"title": "Crash"}`, want: "Crash"},
+		{name: "No content", status: http.StatusNoContent, want: "unchanged"},
+		{name: "String message", status: http.StatusNotFound, body: `{"message": "404 Not found"}`, wantMessage: "404 Not found"},
+		{name: "Field messages", status: http.StatusBadRequest, body: `{"message": {"title": ["is missing"]}}`, wantMessage: "map[title:[is missing]]"},
+		{name: "OAuth error", status: http.StatusUnauthorized, body: `{"error": "invalid_token"}`, wantMessage: "invalid_token"},
+		{name: "Not JSON", status: http.StatusBadGateway, body: `<html>`, wantMessage: "Bad Gateway"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if got := r.Header.Get("Authorization"); got != "Bearer test-token" {
+					t.Errorf("Authorization = %q", got)
+				}
+				if r.URL.Path != "/api/v4/projects/1" || r.URL.Query().Get("page") != "2" {
+					t.Errorf("request URL = %s", r.URL)
+				}
+				w.Header().Set("X-Next-Page", "3")
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			out := struct{ Title string }{Title: "unchanged"}
+			header, err := newTestClient(t, server).Get(context.Background(), "projects/1", map[string][]string{"page": {"2"}}, &out)
+			if tt.wantMessage != "" {
+				var apiErr *Error
+				if !errors.As(err, &apiErr) || apiErr.StatusCode != tt.status || apiErr.Message != tt.wantMessage {
+					t.Fatalf("Get() error = %v, want status %d with message %q", err, tt.status, tt.wantMessage)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Get() unexpected error = %v", err)
+			}
+			if out.Title != tt.want || header.Get("X-Next-Page") != "3" {
+				t.Errorf("Get() = %q, next page %q", out.Title, header.Get("X-Next-Page"))
+			}
+		})
+	}
+}
+
+func TestClientDownloadAsset(t *testing.T) {
+	var authorization []string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = append(authorization, r.Header.Get("Authorization"))
+		w.Header().Set("Content-Type", "image/png")
+		w.Write([]byte("png"))
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	other := httptest.NewServer(handler)
+	defer other.Close()
+
+	client := newTestClient(t, server)
+	for _, rawURL := range []string{server.URL + "/uploads/a/log.png", other.URL + "/log.png"} {
+		asset, err := client.DownloadAsset(context.Background(), rawURL)
+		if err != nil {
+			t.Fatalf("DownloadAsset(%s) unexpected error = %v", rawURL, err)
+		}
+		if string(asset.Data) != "png" || asset.ContentType != "image/png" {
+			t.Errorf("DownloadAsset(%s) = %+v", rawURL, asset)
+		}
+	}
+
+	// 令牌只发送给接口所在主机
+	if len(authorization) != 2 || authorization[0] != "Bearer test-token" || authorization[1] != "" {
+		t.Errorf("Authorization headers = %q", authorization)
+	}
+}
@@ -0,0 +1,200 @@
+package source
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/bigwhite/issue2md/internal/model"
+	"github.com/bigwhite/issue2md/internal/parser"
+)
+
+// ErrUnsupported 来源平台不支持请求的资源类型或操作
+var ErrUnsupported = errors.New("not supported by this source")
+
+// Client 所有来源平台都支持的核心操作: 获取Issue及其评论和Pull Request（GitLab为Merge Request）
+// 平台特有的数据由下面的可选接口提供，调用方通过类型断言检查客户端是否支持，不支持时跳过
+type Client interface {
+	GetIssue(ctx context.Context, owner, repo string, issueNumber int) (*model.Issue, error)
+	GetIssueComments(ctx context.Context, owner, repo string, issueNumber int) ([]*model.Comment, error)
+	GetPullRequest(ctx context.Context, owner, repo string, number int) (*model.PullRequest, error)
+}
+
+// TimelineClient 支持Issue时间线事件的客户端
+type TimelineClient interface {
+	GetIssueTimeline(ctx context.Context, owner, repo string, issueNumber int) ([]*model.TimelineEvent, error)
+}
+
+// SubIssueClient 支持父Issue和子Issue的客户端
+type SubIssueClient interface {
+	GetSubIssues(ctx context.Context, owner, repo string, issueNumber int) ([]*model.SubIssue, error)
+	GetParentIssue(ctx context.Context, owner, repo string, issueNumber int) (*model.IssueReference, error)
+}
+
+// ResolutionClient 支持查询关闭Issue的Pull Request和提交的客户端
+type ResolutionClient interface {
+	GetIssueResolution(ctx context.Context, owner, repo string, issueNumber int) (*model.Resolution, error)
+}
+
+// ProjectClient 支持Projects (v2)条目的客户端
+type ProjectClient interface {
+	GetProjectItems(ctx context.Context, owner, repo string, number int) ([]*model.ProjectItem, error)
+}
+
+// EditHistoryClient 支持正文和评论修改历史的客户端
+type EditHistoryClient interface {
+	GetEditHistory(ctx context.Context, owner, repo string, number int) (*model.EditHistory, error)
+}
+
+// MinimizedClient 支持评论折叠状态的客户端
+type MinimizedClient interface {
+	GetMinimizedComments(ctx context.Context, owner, repo string, number int) (map[int64]string, error)
+}
+
+// DiscussionClient 支持Discussion的客户端
+type DiscussionClient interface {
+	GetDiscussion(ctx context.Context, owner, repo string, number int) (*model.Discussion, error)
+}
+
+// ListClient 支持按条件列出仓库Issue的客户端
+type ListClient interface {
+	ListIssues(ctx context.Context, owner, repo string, opts *model.IssueListOptions) ([]*model.Issue, error)
+}
+
+// SearchClient 支持按搜索语法查询Issue和Pull Request的客户端
+type SearchClient interface {
+	SearchIssues(ctx context.Context, query string) ([]*model.Issue, error)
+}
+
+// AssetClient 支持下载用户上传的附件和图片的客户端
+type AssetClient interface {
+	DownloadAsset(ctx context.Context, rawURL string) (*model.Asset, error)
+}
+
+// EpicClient 支持Epic（GitLab群组级Issue）的客户端
+type EpicClient interface {
+	// GetEpic 获取群组的Epic及其评论，Epic下的Issue填充在SubIssues中
+	GetEpic(ctx context.Context, group string, number int) (*model.Issue, []*model.Comment, error)
+}
+
+// Provider 一个来源平台，如GitHub或GitLab
+// 平台的客户端实现Client接口和平台支持的可选接口，并把平台数据转换为github包中的数据模型，
+// 因此解析器和转换器无需关心资源来自哪个平台
+type Provider struct {
+	// Name 平台名称，如 github、gitlab
+	Name string
+	// Hosts 属于该平台的主机名
+	Hosts []string
+	// URLParser 解析该平台的资源URL
+	URLParser parser.URLParser
+	// NewClient 创建访问指定主机的客户端
+	NewClient func(host string) (Client, error)
+}
+
+// Registry 按URL的主机把请求分派给对应平台，实现parser.URLParser接口
+type Registry struct {
+	providers []*Provider
+	hosts     map[string]*Provider
+}
+
+// NewRegistry 创建平台注册表
+// 同一主机出现在多个平台中时以先注册的平台为准
+// 参数:
+//   - providers: 来源平台
+//
+// 返回值: *Registry - 注册表
+func NewRegistry(providers ...*Provider) *Registry {
+	r := &Registry{providers: providers, hosts: make(map[string]*Provider)}
+	for _, provider := range providers {
+		for _, host := range provider.Hosts {
+			host = strings.ToLower(host)
+			if _, ok := r.hosts[host]; !ok {
+				r.hosts[host] = provider
+			}
+		}
+	}
+	return r
+}
+
+// Provider 返回主机所属的平台
+func (r *Registry) Provider(host string) (*Provider, bool) {
+	provider, ok := r.hosts[strings.ToLower(host)]
+	return provider, ok
+}
+
+// Parse 实现parser.URLParser接口，由URL主机所属平台的解析器解析
+func (r *Registry) Parse(rawURL string) (*parser.ResourceURL, error) {
+	provider, err := r.providerForURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	return provider.URLParser.Parse(rawURL)
+}
+
+// ParseRepository 实现parser.URLParser接口，由URL主机所属平台的解析器解析
+func (r *Registry) ParseRepository(rawURL string) (*parser.ResourceURL, error) {
+	provider, err := r.providerForURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	return provider.URLParser.ParseRepository(rawURL)
+}
+
+// Validate 实现parser.URLParser接口
+func (r *Registry) Validate(rawURL string) error {
+	_, err := r.Parse(rawURL)
+	return err
+}
+
+// SupportedTypes 实现parser.URLParser接口，返回所有平台支持的资源类型
+func (r *Registry) SupportedTypes() []string {
+	seen := make(map[string]bool)
+	var types []string
+	for _, provider := range r.providers {
+		for _, t := range provider.URLParser.SupportedTypes() {
+			if !seen[t] {
+				seen[t] = true
+				types = append(types, t)
+			}
+		}
+	}
+	return types
+}
+
+// ClientForHost 创建访问主机的客户端，可直接用作cli.Exporter的ClientForHost
+func (r *Registry) ClientForHost(host string) (Client, error) {
+	provider, ok := r.Provider(host)
+	if !ok {
+		return nil, fmt.Errorf("no source configured for host %s", host)
+	}
+	return provider.NewClient(strings.ToLower(host))
+}
+
+// providerForURL 返回URL主机所属的平台
+func (r *Registry) providerForURL(rawURL string) (*Provider, error) {
+	if rawURL == "" {
+		return nil, fmt.Errorf("empty URL")
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid URL: %q", rawURL)
+	}
+	provider, ok := r.Provider(u.Host)
+	if !ok {
+		return nil, fmt.Errorf("invalid URL: unsupported host %s, known hosts: %s", u.Host, strings.Join(r.knownHosts(), ", "))
+	}
+	return provider, nil
+}
+
+// knownHosts 返回已注册的主机名，按字母排序
+func (r *Registry) knownHosts() []string {
+	hosts := make([]string, 0, len(r.hosts))
+	for host := range r.hosts {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+	return hosts
+}
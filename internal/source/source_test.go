@@ -0,0 +1,105 @@
+package source
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bigwhite/issue2md/internal/parser"
+)
+
+// fakeURLParser 返回固定类型的URL解析器
+type fakeURLParser struct {
+	kind string
+}
+
+func (p *fakeURLParser) Parse(rawURL string) (*parser.ResourceURL, error) {
+	return &parser.ResourceURL{Type: p.kind, URL: rawURL}, nil
+}
+
+func (p *fakeURLParser) ParseRepository(rawURL string) (*parser.ResourceURL, error) {
+	return &parser.ResourceURL{Type: "repository", URL: rawURL}, nil
+}
+
+func (p *fakeURLParser) Validate(rawURL string) error {
+	return nil
+}
+
+func (p *fakeURLParser) SupportedTypes() []string {
+	return []string{p.kind, "issue"}
+}
+
+func newTestRegistry(created *[]string) *Registry {
+	newClient := func(name string) func(host string) (Client, error) {
+		return func(host string) (Client, error) {
+			*created = append(*created, name+":"+host)
+			return nil, nil
+		}
+	}
+	return NewRegistry(
+		&Provider{Name: "github", Hosts: []string{"github.com", "git.corp.example"}, URLParser: &fakeURLParser{kind: "pull"}, NewClient: newClient("github")},
+		&Provider{Name: "gitlab", Hosts: []string{"gitlab.com", "GIT.CORP.EXAMPLE"}, URLParser: &fakeURLParser{kind: "epic"}, NewClient: newClient("gitlab")},
+	)
+}
+
+func TestRegistryParse(t *testing.T) {
+	var created []string
+	r := newTestRegistry(&created)
+
+	tests := []struct {
+		url      string
+		wantType string
+		wantErr  string
+	}{
+		{url: "https://github.com/owner/repo/pull/1", wantType: "pull"},
+		{url: "https://GitLab.com/group/app/-/epics/1", wantType: "epic"},
+		{url: "https://git.corp.example/owner/repo/pull/1", wantType: "pull"},
+		{url: "https://example.com/owner/repo/issues/1", wantErr: "unsupported host example.com, known hosts: git.corp.example, github.com, gitlab.com"},
+		{url: "", wantErr: "empty URL"},
+		{url: "not a url", wantErr: "invalid URL"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			got, err := r.Parse(tt.url)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Parse() error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got.Type != tt.wantType {
+				t.Errorf("Type = %q, want %q", got.Type, tt.wantType)
+			}
+		})
+	}
+}
+
+func TestRegistryClientForHost(t *testing.T) {
+	var created []string
+	r := newTestRegistry(&created)
+
+	for _, host := range []string{"gitlab.com", "Git.Corp.Example"} {
+		if _, err := r.ClientForHost(host); err != nil {
+			t.Fatalf("ClientForHost(%q) error = %v", host, err)
+		}
+	}
+	if _, err := r.ClientForHost("example.com"); err == nil {
+		t.Error("ClientForHost(example.com) error = nil, want error")
+	}
+
+	want := []string{"gitlab:gitlab.com", "github:git.corp.example"}
+	if strings.Join(created, ",") != strings.Join(want, ",") {
+		t.Errorf("created clients = %v, want %v", created, want)
+	}
+}
+
+func TestRegistrySupportedTypes(t *testing.T) {
+	var created []string
+	got := newTestRegistry(&created).SupportedTypes()
+	if want := "pull,issue,epic"; strings.Join(got, ",") != want {
+		t.Errorf("SupportedTypes() = %v, want %s", got, want)
+	}
+}
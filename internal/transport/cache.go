@@ -0,0 +1,257 @@
+package transport
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// CacheMode 磁盘缓存的使用方式
+type CacheMode int
+
+const (
+	// CacheRevalidate 使用缓存，并通过ETag/Last-Modified条件请求向GitHub确认是否过期
+	CacheRevalidate CacheMode = iota
+	// CacheRefresh 忽略已有缓存重新获取，并用新响应更新缓存
+	CacheRefresh
+	// CacheOffline 只读取缓存，不发出任何网络请求
+	CacheOffline
+)
+
+// ErrNotCached 离线模式下请求的资源不在缓存中
+var ErrNotCached = errors.New("response not in cache")
+
+// cacheHeader 标记响应来自缓存的响应头
+const cacheHeader = "X-Issue2md-Cache"
+
+// cacheEntry 磁盘上缓存的一个响应
+type cacheEntry struct {
+	Method     string      `json:"method"`
+	URL        string      `json:"url"`
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header"`
+	Body       []byte      `json:"body"`
+	StoredAt   time.Time   `json:"stored_at"`
+}
+
+// CacheTransport 基于磁盘的HTTP缓存
+// GET请求的响应连同ETag/Last-Modified保存在Dir中，之后的请求以条件请求重新验证，
+// GitHub对304响应不计入限流额度。GraphQL查询没有条件请求，仅在离线模式下使用缓存。
+// Dir为空时缓存被禁用，请求直接透传。
+type CacheTransport struct {
+	// Base 实际发送请求的RoundTripper，为nil时使用http.DefaultTransport
+	Base http.RoundTripper
+	// Dir 缓存目录
+	Dir string
+	// Mode 缓存模式
+	Mode CacheMode
+}
+
+// NewCacheTransport 创建磁盘缓存RoundTripper
+func NewCacheTransport(base http.RoundTripper, dir string, mode CacheMode) *CacheTransport {
+	return &CacheTransport{
+		Base: base,
+		Dir:  dir,
+		Mode: mode,
+	}
+}
+
+// RoundTrip 实现http.RoundTripper接口
+func (t *CacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.Dir == "" || !isCacheable(req) {
+		return t.base().RoundTrip(req)
+	}
+
+	key, err := cacheKey(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var entry *cacheEntry
+	if t.Mode != CacheRefresh {
+		entry = t.load(key)
+	}
+
+	if t.Mode == CacheOffline {
+		if entry == nil {
+			return nil, fmt.Errorf("%w: %s %s", ErrNotCached, req.Method, req.URL)
+		}
+		return entry.response(req), nil
+	}
+
+	// GraphQL查询不支持条件请求，在线时总是重新获取
+	conditional := entry != nil && req.Method == http.MethodGet
+	outReq := req
+	if conditional {
+		outReq = req.Clone(req.Context())
+		if etag := entry.Header.Get("ETag"); etag != "" {
+			outReq.Header.Set("If-None-Match", etag)
+		}
+		if lastModified := entry.Header.Get("Last-Modified"); lastModified != "" {
+			outReq.Header.Set("If-Modified-Since", lastModified)
+		}
+	}
+
+	resp, err := t.base().RoundTrip(outReq)
+	if err != nil {
+		return nil, err
+	}
+
+	if conditional && resp.StatusCode == http.StatusNotModified {
+		DrainBody(resp.Body)
+		// 304响应携带的最新响应头（如限流状态）覆盖缓存中的旧值
+		resp.Header.Del("Content-Length")
+		for name, values := range resp.Header {
+			entry.Header[name] = values
+		}
+		cached := entry.response(req)
+		cached.Header.Set(cacheHeader, "revalidated")
+		if err := t.store(key, entry); err != nil {
+			return nil, err
+		}
+		return cached, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body for cache: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	if err := t.store(key, &cacheEntry{
+		Method:     req.Method,
+		URL:        req.URL.String(),
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header.Clone(),
+		Body:       body,
+		StoredAt:   time.Now().UTC(),
+	}); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// base 返回实际发送请求的RoundTripper
+func (t *CacheTransport) base() http.RoundTripper {
+	if t.Base != nil {
+		return t.Base
+	}
+	return http.DefaultTransport
+}
+
+// load 读取缓存条目，不存在或已损坏时返回nil
+func (t *CacheTransport) load(key string) *cacheEntry {
+	data, err := os.ReadFile(filepath.Join(t.Dir, key+".json"))
+	if err != nil {
+		return nil
+	}
+
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil
+	}
+	if entry.Header == nil {
+		entry.Header = make(http.Header)
+	}
+	return &entry
+}
+
+// store 写入缓存条目，先写临时文件再重命名，避免并发读到半写的文件
+// 限流相关的响应头只反映当时的状态，不予保存
+func (t *CacheTransport) store(key string, entry *cacheEntry) error {
+	for name := range entry.Header {
+		if strings.HasPrefix(http.CanonicalHeaderKey(name), "X-Ratelimit-") || name == cacheHeader {
+			entry.Header.Del(name)
+		}
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode cache entry: %w", err)
+	}
+
+	if err := os.MkdirAll(t.Dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(t.Dir, key+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(t.Dir, key+".json")); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	return nil
+}
+
+// response 由缓存条目构造响应
+func (e *cacheEntry) response(req *http.Request) *http.Response {
+	header := e.Header.Clone()
+	header.Set(cacheHeader, "hit")
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", e.StatusCode, http.StatusText(e.StatusCode)),
+		StatusCode:    e.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
+
+// isCacheable 判断请求能否使用缓存: GET请求和标记为幂等的POST请求（GraphQL查询）
+func isCacheable(req *http.Request) bool {
+	if req.Method == http.MethodGet {
+		return true
+	}
+	marked, _ := req.Context().Value(idempotentKey{}).(bool)
+	return marked && req.Method == http.MethodPost && CanReplay(req)
+}
+
+// cacheKey 计算请求的缓存键
+// 键包含方法、URL、Accept头和请求正文，不包含认证信息: 令牌轮换或离线运行不带令牌时仍能命中缓存，
+// 缓存目录本身属于当前用户
+func cacheKey(req *http.Request) (string, error) {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n%s\n%s\n", req.Method, req.URL.String(), req.Header.Get("Accept"))
+
+	if req.GetBody != nil && req.Body != http.NoBody {
+		body, err := req.GetBody()
+		if err != nil {
+			return "", fmt.Errorf("failed to read request body for cache key: %w", err)
+		}
+		_, err = io.Copy(h, body)
+		body.Close()
+		if err != nil {
+			return "", fmt.Errorf("failed to read request body for cache key: %w", err)
+		}
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
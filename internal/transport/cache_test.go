@@ -0,0 +1,153 @@
+package transport
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// createETagServer 创建支持ETag条件请求的服务器，返回全部请求数和304响应数
+func createETagServer(t *testing.T, body string) (*httptest.Server, *int32, *int32) {
+	var calls, notModified int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("X-RateLimit-Remaining", "4999")
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			atomic.AddInt32(&notModified, 1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	}))
+	return server, &calls, &notModified
+}
+
+func TestCacheTransport(t *testing.T) {
+	const body = `{"number": 1}`
+
+	tests := []struct {
+		name            string
+		mode            CacheMode
+		dir             bool
+		prime           bool
+		wantCalls       int32
+		wantNotModified int32
+		wantCache       string
+		wantErr         error
+	}{
+		{name: "Cache disabled", wantCalls: 1},
+		{name: "First fetch stores", dir: true, wantCalls: 1},
+		{name: "Revalidated with 304", dir: true, prime: true, wantCalls: 1, wantNotModified: 1, wantCache: "revalidated"},
+		{name: "Refresh ignores cache", dir: true, prime: true, mode: CacheRefresh, wantCalls: 1},
+		{name: "Offline hit", dir: true, prime: true, mode: CacheOffline, wantCache: "hit"},
+		{name: "Offline miss", dir: true, mode: CacheOffline, wantErr: ErrNotCached},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, calls, notModified := createETagServer(t, body)
+			defer server.Close()
+
+			dir := ""
+			if tt.dir {
+				dir = t.TempDir()
+			}
+			transport := NewCacheTransport(server.Client().Transport, dir, CacheRevalidate)
+
+			fetch := func() (*http.Response, error) {
+				req, _ := http.NewRequest(http.MethodGet, server.URL+"/repos/o/r/issues/1", nil)
+				req.Header.Set("Authorization", "Bearer test-token")
+				return transport.RoundTrip(req)
+			}
+
+			if tt.prime {
+				resp, err := fetch()
+				if err != nil {
+					t.Fatalf("priming RoundTrip() unexpected error = %v", err)
+				}
+				resp.Body.Close()
+				atomic.StoreInt32(calls, 0)
+			}
+
+			transport.Mode = tt.mode
+			resp, err := fetch()
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("RoundTrip() error = %v, want %v", err, tt.wantErr)
+				}
+				if got := atomic.LoadInt32(calls); got != 0 {
+					t.Errorf("server calls = %d, want 0 in offline mode", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RoundTrip() unexpected error = %v", err)
+			}
+			defer resp.Body.Close()
+
+			got, _ := io.ReadAll(resp.Body)
+			if resp.StatusCode != http.StatusOK || string(got) != body {
+				t.Errorf("RoundTrip() = %d %q, want 200 %q", resp.StatusCode, got, body)
+			}
+			if got := atomic.LoadInt32(calls); got != tt.wantCalls {
+				t.Errorf("server calls = %d, want %d", got, tt.wantCalls)
+			}
+			if got := atomic.LoadInt32(notModified); got != tt.wantNotModified {
+				t.Errorf("304 responses = %d, want %d", got, tt.wantNotModified)
+			}
+			if got := resp.Header.Get(cacheHeader); got != tt.wantCache {
+				t.Errorf("%s header = %q, want %q", cacheHeader, got, tt.wantCache)
+			}
+			if resp.Header.Get("ETag") != `"v1"` {
+				t.Errorf("ETag header = %q, want \"v1\"", resp.Header.Get("ETag"))
+			}
+			// 限流头只来自实时响应，离线时不应带出缓存时的旧值
+			if wantRateLimit := tt.mode != CacheOffline; (resp.Header.Get("X-RateLimit-Remaining") != "") != wantRateLimit {
+				t.Errorf("X-RateLimit-Remaining = %q, want present %v", resp.Header.Get("X-RateLimit-Remaining"), wantRateLimit)
+			}
+		})
+	}
+}
+
+func TestCacheKey(t *testing.T) {
+	newRequest := func(method, rawURL, token, body string) *http.Request {
+		var reader io.Reader
+		if body != "" {
+			reader = strings.NewReader(body)
+		}
+		req, _ := http.NewRequest(method, rawURL, reader)
+		req.Header.Set("Authorization", token)
+		return req
+	}
+
+	base, _ := cacheKey(newRequest(http.MethodGet, "https://api.github.com/repos/o/r/issues/1", "a", ""))
+	tests := []struct {
+		name string
+		req  *http.Request
+		same bool
+	}{
+		{name: "Same request", req: newRequest(http.MethodGet, "https://api.github.com/repos/o/r/issues/1", "a", ""), same: true},
+		{name: "Different URL", req: newRequest(http.MethodGet, "https://api.github.com/repos/o/r/issues/2", "a", "")},
+		{name: "Different token", req: newRequest(http.MethodGet, "https://api.github.com/repos/o/r/issues/1", "b", ""), same: true},
+		{name: "No token", req: newRequest(http.MethodGet, "https://api.github.com/repos/o/r/issues/1", "", ""), same: true},
+		{name: "Different body", req: newRequest(http.MethodPost, "https://api.github.com/graphql", "a", `{"query": "x"}`)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := cacheKey(tt.req)
+			if err != nil {
+				t.Fatalf("cacheKey() unexpected error = %v", err)
+			}
+			if (key == base) != tt.same {
+				t.Errorf("cacheKey() == base is %v, want %v", key == base, tt.same)
+			}
+		})
+	}
+}
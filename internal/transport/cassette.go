@@ -0,0 +1,372 @@
+package transport
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// CassetteMode 录制回放的工作方式
+type CassetteMode int
+
+const (
+	// CassetteRecord 正常发送请求，并把每次请求和响应保存到磁带目录
+	CassetteRecord CassetteMode = iota
+	// CassetteReplay 只从磁带目录回放响应，不发出任何网络请求
+	CassetteReplay
+)
+
+// ErrNotRecorded 回放模式下请求不在磁带中
+var ErrNotRecorded = errors.New("request not recorded in cassette")
+
+// cassetteRedactedHeaders 录制时不保存的头，避免令牌和会话信息写入磁带
+var cassetteRedactedHeaders = []string{"Authorization", "Cookie", "Set-Cookie"}
+
+// cassetteRequest 磁带中保存的请求
+type cassetteRequest struct {
+	Method     string      `json:"method"`
+	URL        string      `json:"url"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+	BodyBase64 string      `json:"body_base64,omitempty"`
+}
+
+// cassetteResponse 磁带中保存的响应
+type cassetteResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+	BodyBase64 string      `json:"body_base64,omitempty"`
+}
+
+// interaction 一次请求和响应
+type interaction struct {
+	Request    cassetteRequest  `json:"request"`
+	Response   cassetteResponse `json:"response"`
+	RecordedAt time.Time        `json:"recorded_at"`
+}
+
+// CassetteTransport 录制和回放GitHub API交互的http.RoundTripper
+// 每次交互保存为磁带目录中的一个JSON文件，文件名带有序号，便于人工查看和编辑。
+// 回放时按方法、URL和请求正文匹配；同一请求录制了多次时按录制顺序依次返回，用尽后重复最后一次。
+// 录制时不保存Authorization等敏感头，磁带可以直接交给他人复现问题。
+type CassetteTransport struct {
+	// Base 录制模式下实际发送请求的RoundTripper，为nil时使用http.DefaultTransport
+	Base http.RoundTripper
+	// Dir 磁带目录
+	Dir string
+	// Mode 工作方式
+	Mode CassetteMode
+
+	mu     sync.Mutex
+	seq    int
+	tape   map[string][]*interaction
+	cursor map[string]int
+}
+
+// NewCassetteTransport 创建录制回放RoundTripper
+// 回放模式会立即读取磁带目录，目录不存在或没有任何交互时返回错误；
+// 录制模式会在已有交互之后继续编号
+func NewCassetteTransport(base http.RoundTripper, dir string, mode CassetteMode) (*CassetteTransport, error) {
+	t := &CassetteTransport{
+		Base:   base,
+		Dir:    dir,
+		Mode:   mode,
+		tape:   make(map[string][]*interaction),
+		cursor: make(map[string]int),
+	}
+
+	files, err := t.files()
+	if err != nil && (mode == CassetteReplay || !errors.Is(err, os.ErrNotExist)) {
+		return nil, fmt.Errorf("failed to read cassette: %w", err)
+	}
+	if len(files) > 0 {
+		t.seq = sequenceNumber(files[len(files)-1])
+	}
+
+	if mode == CassetteReplay {
+		if len(files) == 0 {
+			return nil, fmt.Errorf("cassette %s contains no recorded requests", dir)
+		}
+		for _, file := range files {
+			if err := t.loadInteraction(file); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return t, nil
+}
+
+// RoundTrip 实现http.RoundTripper接口
+func (t *CassetteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := requestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	key := interactionKey(req.Method, req.URL.String(), body)
+
+	if t.Mode == CassetteReplay {
+		return t.replay(req, key)
+	}
+
+	resp, err := t.base().RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body for cassette: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	rec := &interaction{
+		Request: cassetteRequest{
+			Method: req.Method,
+			URL:    req.URL.String(),
+			Header: redactHeader(req.Header),
+		},
+		Response: cassetteResponse{
+			StatusCode: resp.StatusCode,
+			Header:     redactHeader(resp.Header),
+		},
+		RecordedAt: time.Now().UTC(),
+	}
+	rec.Request.Body, rec.Request.BodyBase64 = encodeBody(body)
+	rec.Response.Body, rec.Response.BodyBase64 = encodeBody(respBody)
+
+	if err := t.save(rec); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// base 返回实际发送请求的RoundTripper
+func (t *CassetteTransport) base() http.RoundTripper {
+	if t.Base != nil {
+		return t.Base
+	}
+	return http.DefaultTransport
+}
+
+// replay 返回录制的响应
+// 回放的响应不带限流头，避免录制时的限流状态让回放等待
+func (t *CassetteTransport) replay(req *http.Request, key string) (*http.Response, error) {
+	t.mu.Lock()
+	recorded := t.tape[key]
+	if len(recorded) == 0 {
+		t.mu.Unlock()
+		return nil, fmt.Errorf("%w: %s %s", ErrNotRecorded, req.Method, req.URL)
+	}
+	i := t.cursor[key]
+	if i < len(recorded)-1 {
+		t.cursor[key] = i + 1
+	}
+	rec := recorded[i]
+	t.mu.Unlock()
+
+	body, err := decodeBody(rec.Response.Body, rec.Response.BodyBase64)
+	if err != nil {
+		return nil, fmt.Errorf("corrupt cassette entry for %s %s: %w", req.Method, req.URL, err)
+	}
+
+	header := rec.Response.Header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	for name := range header {
+		if strings.HasPrefix(http.CanonicalHeaderKey(name), "X-Ratelimit-") {
+			header.Del(name)
+		}
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", rec.Response.StatusCode, http.StatusText(rec.Response.StatusCode)),
+		StatusCode:    rec.Response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// save 把交互写入磁带目录
+func (t *CassetteTransport) save(rec *interaction) error {
+	data, err := json.MarshalIndent(rec, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode cassette entry: %w", err)
+	}
+
+	t.mu.Lock()
+	t.seq++
+	name := fmt.Sprintf("%04d-%s-%s.json", t.seq, strings.ToLower(rec.Request.Method), interactionSlug(rec.Request.URL))
+	t.mu.Unlock()
+
+	if err := os.MkdirAll(t.Dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create cassette directory: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(t.Dir, name), data, 0o644); err != nil {
+		return fmt.Errorf("failed to write cassette entry: %w", err)
+	}
+	return nil
+}
+
+// files 按录制顺序返回磁带目录中的交互文件
+// 按文件名的序号数值排序，序号超过四位时仍保持录制顺序
+func (t *CassetteTransport) files() ([]string, error) {
+	entries, err := os.ReadDir(t.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".json") {
+			files = append(files, filepath.Join(t.Dir, entry.Name()))
+		}
+	}
+	sort.Slice(files, func(i, j int) bool {
+		si, sj := sequenceNumber(files[i]), sequenceNumber(files[j])
+		if si != sj {
+			return si < sj
+		}
+		return files[i] < files[j]
+	})
+	return files, nil
+}
+
+// sequenceNumber 返回交互文件名开头的录制序号，没有序号时返回0
+func sequenceNumber(path string) int {
+	prefix, _, _ := strings.Cut(filepath.Base(path), "-")
+	seq, err := strconv.Atoi(prefix)
+	if err != nil {
+		return 0
+	}
+	return seq
+}
+
+// loadInteraction 读取一个交互文件并加入回放索引
+func (t *CassetteTransport) loadInteraction(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read cassette entry: %w", err)
+	}
+
+	var rec interaction
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return fmt.Errorf("corrupt cassette entry %s: %w", filepath.Base(path), err)
+	}
+	body, err := decodeBody(rec.Request.Body, rec.Request.BodyBase64)
+	if err != nil {
+		return fmt.Errorf("corrupt cassette entry %s: %w", filepath.Base(path), err)
+	}
+
+	key := interactionKey(rec.Request.Method, rec.Request.URL, body)
+	t.tape[key] = append(t.tape[key], &rec)
+	return nil
+}
+
+// requestBody 读取请求正文而不消耗它
+func requestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	if req.GetBody == nil {
+		body, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read request body for cassette: %w", err)
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		return body, nil
+	}
+
+	rc, err := req.GetBody()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body for cassette: %w", err)
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
+
+// interactionKey 计算匹配请求用的键
+func interactionKey(method, rawURL string, body []byte) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n%s\n", method, rawURL)
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// interactionSlug 由URL路径生成可读的文件名片段
+func interactionSlug(rawURL string) string {
+	path := rawURL
+	if i := strings.Index(path, "://"); i >= 0 {
+		path = path[i+3:]
+	}
+	if i := strings.Index(path, "/"); i >= 0 {
+		path = path[i+1:]
+	}
+	if i := strings.IndexAny(path, "?#"); i >= 0 {
+		path = path[:i]
+	}
+
+	slug := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' {
+			return r
+		}
+		return '_'
+	}, strings.Trim(path, "/"))
+	if len(slug) > 80 {
+		slug = slug[:80]
+	}
+	if slug == "" {
+		slug = "root"
+	}
+	return slug
+}
+
+// redactHeader 复制响应头并去掉敏感头
+func redactHeader(header http.Header) http.Header {
+	clean := header.Clone()
+	for _, name := range cassetteRedactedHeaders {
+		clean.Del(name)
+	}
+	if len(clean) == 0 {
+		return nil
+	}
+	return clean
+}
+
+// encodeBody 正文为UTF-8文本时原样保存，否则保存为Base64
+func encodeBody(body []byte) (string, string) {
+	if utf8.Valid(body) {
+		return string(body), ""
+	}
+	return "", base64.StdEncoding.EncodeToString(body)
+}
+
+// decodeBody 还原encodeBody保存的正文
+func decodeBody(text, encoded string) ([]byte, error) {
+	if encoded != "" {
+		return base64.StdEncoding.DecodeString(encoded)
+	}
+	return []byte(text), nil
+}
@@ -0,0 +1,79 @@
+package transport
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewCassetteTransportReplayErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(dir string)
+	}{
+		{name: "Missing directory", setup: func(dir string) { os.Remove(dir) }},
+		{name: "Empty directory", setup: func(dir string) {}},
+		{name: "Corrupt entry", setup: func(dir string) { os.WriteFile(filepath.Join(dir, "0001-get-x.json"), []byte("{"), 0o644) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			tt.setup(dir)
+			if _, err := NewCassetteTransport(nil, dir, CassetteReplay); err == nil {
+				t.Error("NewCassetteTransport() expected error, but got nil")
+			}
+		})
+	}
+}
+
+func TestCassetteFilesOrder(t *testing.T) {
+	dir := t.TempDir()
+	names := []string{"10000-get-c.json", "9999-get-b.json", "0002-get-a.json", "10001-post-graphql.json", "notes.txt"}
+	for _, name := range names {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("{}"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	transport := &CassetteTransport{Dir: dir}
+	files, err := transport.files()
+	if err != nil {
+		t.Fatalf("files() unexpected error = %v", err)
+	}
+	want := []string{"0002-get-a.json", "9999-get-b.json", "10000-get-c.json", "10001-post-graphql.json"}
+	if len(files) != len(want) {
+		t.Fatalf("files() = %v, want %v", files, want)
+	}
+	for i, file := range files {
+		if filepath.Base(file) != want[i] {
+			t.Errorf("files()[%d] = %s, want %s", i, filepath.Base(file), want[i])
+		}
+	}
+
+	// 继续录制时从最大的序号往后编号
+	recorder, err := NewCassetteTransport(nil, dir, CassetteRecord)
+	if err != nil {
+		t.Fatalf("NewCassetteTransport() unexpected error = %v", err)
+	}
+	if recorder.seq != 10001 {
+		t.Errorf("seq = %d, want 10001", recorder.seq)
+	}
+}
+
+func TestInteractionSlug(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{url: "https://api.github.com/repos/o/r/issues/1/comments?page=2", want: "repos_o_r_issues_1_comments"},
+		{url: "https://api.github.com/graphql", want: "graphql"},
+		{url: "https://api.github.com/", want: "root"},
+	}
+
+	for _, tt := range tests {
+		if got := interactionSlug(tt.url); got != tt.want {
+			t.Errorf("interactionSlug(%q) = %q, want %q", tt.url, got, tt.want)
+		}
+	}
+}
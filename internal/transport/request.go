@@ -0,0 +1,59 @@
+// Package transport 提供各平台客户端共用的http.RoundTripper：瞬时故障重试、磁盘缓存和录制回放，
+// 以及供其他传输层重发请求使用的辅助函数
+package transport
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// CanReplay 判断请求正文能否在重试时重新发送
+func CanReplay(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// RewindRequest 复制请求并重置正文，用于重试
+func RewindRequest(req *http.Request) (*http.Request, error) {
+	next := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, fmt.Errorf("failed to rewind request body: %w", err)
+		}
+		next.Body = body
+	}
+	return next, nil
+}
+
+// DrainBody 读完并关闭响应正文，以便复用连接
+func DrainBody(body io.ReadCloser) {
+	if body == nil {
+		return
+	}
+	io.Copy(io.Discard, body)
+	body.Close()
+}
+
+// SleepContext 等待指定时间，context取消时提前返回
+func SleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// idempotentKey 标记请求为幂等的context键
+type idempotentKey struct{}
+
+// WithIdempotent 将请求标记为幂等，用于只读的POST请求（如GraphQL查询）
+func WithIdempotent(ctx context.Context) context.Context {
+	return context.WithValue(ctx, idempotentKey{}, true)
+}
@@ -0,0 +1,189 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy 瞬时故障的重试策略
+type RetryPolicy struct {
+	// MaxAttempts 最大尝试次数（含首次请求），小于等于1时不重试
+	MaxAttempts int
+	// BaseDelay 首次重试前的等待时间，之后每次翻倍
+	BaseDelay time.Duration
+	// MaxDelay 单次等待时间的上限
+	MaxDelay time.Duration
+}
+
+// DefaultRetryPolicy 返回默认重试策略
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   500 * time.Millisecond,
+		MaxDelay:    10 * time.Second,
+	}
+}
+
+// backoff 返回第attempt次失败后的等待时间
+// 采用指数退避，并在[delay/2, delay]区间内随机抖动，避免并发请求同时重试
+func (p RetryPolicy) backoff(attempt int, randInt63n func(int64) int64) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < attempt && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+
+	half := delay / 2
+	if half <= 0 {
+		return delay
+	}
+	return half + time.Duration(randInt63n(int64(half)+1))
+}
+
+// RetryError 重试耗尽后返回的错误，记录尝试次数和最后一次失败的原因
+type RetryError struct {
+	Attempts int
+	Err      error
+}
+
+// Error 实现error接口
+func (e *RetryError) Error() string {
+	return fmt.Sprintf("giving up after %d attempts: %v", e.Attempts, e.Err)
+}
+
+// Unwrap 返回最后一次失败的原因
+func (e *RetryError) Unwrap() error {
+	return e.Err
+}
+
+// RetryTransport 对瞬时故障自动重试的http.RoundTripper
+// 可重试的故障包括502/503/504响应、连接被重置和超时。
+// 幂等请求（GET、HEAD、OPTIONS、PUT、DELETE及标记为幂等的请求）在上述故障时都会重试；
+// 非幂等请求仅在连接被拒绝、请求确定未发出时重试。
+type RetryTransport struct {
+	// Base 实际发送请求的RoundTripper，为nil时使用http.DefaultTransport
+	Base http.RoundTripper
+	// Policy 重试策略
+	Policy RetryPolicy
+
+	mu    sync.Mutex
+	rnd   *rand.Rand
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// NewRetryTransport 创建使用默认重试策略的RoundTripper
+func NewRetryTransport(base http.RoundTripper) *RetryTransport {
+	return &RetryTransport{
+		Base:   base,
+		Policy: DefaultRetryPolicy(),
+		rnd:    rand.New(rand.NewSource(time.Now().UnixNano())),
+		sleep:  SleepContext,
+	}
+}
+
+// RoundTrip 实现http.RoundTripper接口
+func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	idempotent := isIdempotent(req)
+
+	for attempt := 1; ; attempt++ {
+		resp, err := t.base().RoundTrip(req)
+
+		var failure error
+		switch {
+		case err != nil:
+			if ctx.Err() != nil || !isTransientError(err, idempotent) {
+				return nil, err
+			}
+			failure = err
+		case idempotent && isTransientStatus(resp.StatusCode):
+			failure = fmt.Errorf("server returned %s", resp.Status)
+		default:
+			return resp, nil
+		}
+
+		if attempt >= t.Policy.MaxAttempts || !CanReplay(req) {
+			// 未发生过重试时原样返回，保留响应供调用方解析错误详情
+			if attempt == 1 {
+				return resp, err
+			}
+			if resp != nil {
+				DrainBody(resp.Body)
+			}
+			return nil, &RetryError{Attempts: attempt, Err: failure}
+		}
+
+		if resp != nil {
+			DrainBody(resp.Body)
+		}
+		if err := t.sleep(ctx, t.Policy.backoff(attempt, t.randInt63n)); err != nil {
+			return nil, &RetryError{Attempts: attempt, Err: fmt.Errorf("%v; retry interrupted: %w", failure, err)}
+		}
+		if req, err = RewindRequest(req); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// base 返回实际发送请求的RoundTripper
+func (t *RetryTransport) base() http.RoundTripper {
+	if t.Base != nil {
+		return t.Base
+	}
+	return http.DefaultTransport
+}
+
+// randInt63n 并发安全地生成[0, n)区间的随机数
+func (t *RetryTransport) randInt63n(n int64) int64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.rnd.Int63n(n)
+}
+
+// isIdempotent 判断请求是否可以安全地重复发送
+func isIdempotent(req *http.Request) bool {
+	if marked, _ := req.Context().Value(idempotentKey{}).(bool); marked {
+		return true
+	}
+
+	switch req.Method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// isTransientStatus 判断响应状态码是否为可重试的网关类故障
+func isTransientStatus(code int) bool {
+	return code == http.StatusBadGateway || code == http.StatusServiceUnavailable || code == http.StatusGatewayTimeout
+}
+
+// isTransientError 判断网络错误是否可重试
+// 连接被拒绝时请求未发出，任何请求都可重试；其余故障仅对幂等请求重试
+func isTransientError(err error, idempotent bool) bool {
+	if errors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+	if !idempotent {
+		return false
+	}
+
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
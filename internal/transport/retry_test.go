@@ -0,0 +1,208 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// createFlakyServer 创建前failures次请求返回status、之后返回正常响应的服务器
+// status为0时直接断开连接，模拟connection reset
+func createFlakyServer(t *testing.T, failures int32, status int, body string) (*httptest.Server, *int32) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) <= failures {
+			if status == 0 {
+				conn, _, err := w.(http.Hijacker).Hijack()
+				if err != nil {
+					t.Errorf("failed to hijack connection: %v", err)
+					return
+				}
+				conn.Close()
+				return
+			}
+			w.WriteHeader(status)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	}))
+	return server, &calls
+}
+
+// recordSleep 记录等待时长而不真正等待
+func recordSleep(waits *[]time.Duration) func(context.Context, time.Duration) error {
+	return func(ctx context.Context, d time.Duration) error {
+		*waits = append(*waits, d)
+		return ctx.Err()
+	}
+}
+
+func TestRetryTransport(t *testing.T) {
+	tests := []struct {
+		name          string
+		method        string
+		idempotent    bool
+		failures      int32
+		status        int
+		wantCalls     int32
+		wantErr       bool
+		errorContains string
+		wantStatus    int
+	}{
+		{
+			name:       "Recovers from 503",
+			method:     http.MethodGet,
+			failures:   2,
+			status:     http.StatusServiceUnavailable,
+			wantCalls:  3,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "Recovers from connection reset",
+			method:     http.MethodGet,
+			failures:   1,
+			wantCalls:  2,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:          "Gives up after max attempts",
+			method:        http.MethodGet,
+			failures:      10,
+			status:        http.StatusBadGateway,
+			wantCalls:     3,
+			wantErr:       true,
+			errorContains: "giving up after 3 attempts: server returned 502 Bad Gateway",
+		},
+		{
+			name:       "Non-transient status is not retried",
+			method:     http.MethodGet,
+			failures:   10,
+			status:     http.StatusInternalServerError,
+			wantCalls:  1,
+			wantStatus: http.StatusInternalServerError,
+		},
+		{
+			name:       "POST is not retried on 504",
+			method:     http.MethodPost,
+			failures:   1,
+			status:     http.StatusGatewayTimeout,
+			wantCalls:  1,
+			wantStatus: http.StatusGatewayTimeout,
+		},
+		{
+			name:       "POST marked idempotent is retried",
+			method:     http.MethodPost,
+			idempotent: true,
+			failures:   1,
+			status:     http.StatusGatewayTimeout,
+			wantCalls:  2,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:      "POST is not retried on connection reset",
+			method:    http.MethodPost,
+			failures:  1,
+			wantCalls: 1,
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, calls := createFlakyServer(t, tt.failures, tt.status, `{}`)
+			defer server.Close()
+
+			var waits []time.Duration
+			transport := NewRetryTransport(server.Client().Transport)
+			transport.sleep = recordSleep(&waits)
+
+			ctx := context.Background()
+			if tt.idempotent {
+				ctx = WithIdempotent(ctx)
+			}
+			req, _ := http.NewRequestWithContext(ctx, tt.method, server.URL, strings.NewReader(`{"query": "{ viewer { login } }"}`))
+			resp, err := transport.RoundTrip(req)
+
+			if got := atomic.LoadInt32(calls); got != tt.wantCalls {
+				t.Errorf("server calls = %d, want %d", got, tt.wantCalls)
+			}
+			if len(waits) != int(tt.wantCalls)-1 {
+				t.Errorf("waits = %v, want %d", waits, tt.wantCalls-1)
+			}
+
+			if tt.wantErr {
+				if err == nil {
+					resp.Body.Close()
+					t.Fatal("RoundTrip() expected error, but got nil")
+				}
+				if !strings.Contains(err.Error(), tt.errorContains) {
+					t.Errorf("RoundTrip() error = %v, expected to contain '%s'", err, tt.errorContains)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RoundTrip() unexpected error = %v", err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("RoundTrip() status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 10, BaseDelay: time.Second, MaxDelay: 5 * time.Second}
+
+	tests := []struct {
+		name    string
+		attempt int
+		rnd     func(int64) int64
+		want    time.Duration
+	}{
+		{name: "First retry minimum", attempt: 1, rnd: func(int64) int64 { return 0 }, want: 500 * time.Millisecond},
+		{name: "First retry maximum", attempt: 1, rnd: func(n int64) int64 { return n - 1 }, want: time.Second},
+		{name: "Doubles", attempt: 3, rnd: func(int64) int64 { return 0 }, want: 2 * time.Second},
+		{name: "Capped at MaxDelay", attempt: 8, rnd: func(n int64) int64 { return n - 1 }, want: 5 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := policy.backoff(tt.attempt, tt.rnd); got != tt.want {
+				t.Errorf("backoff(%d) = %v, want %v", tt.attempt, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryTransportCanceled(t *testing.T) {
+	server, calls := createFlakyServer(t, 10, http.StatusServiceUnavailable, `{}`)
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	transport := NewRetryTransport(server.Client().Transport)
+	transport.sleep = func(ctx context.Context, d time.Duration) error {
+		cancel()
+		return SleepContext(ctx, d)
+	}
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	_, err := transport.RoundTrip(req)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("RoundTrip() error = %v, want context.Canceled", err)
+	}
+
+	var retryErr *RetryError
+	if !errors.As(err, &retryErr) || retryErr.Attempts != 1 {
+		t.Errorf("RoundTrip() error = %v, want RetryError after 1 attempt", err)
+	}
+	if got := atomic.LoadInt32(calls); got != 1 {
+		t.Errorf("server calls = %d, want 1", got)
+	}
+}